@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/wandermaia/customer-api/docs"
 	"github.com/wandermaia/customer-api/internal/config"
@@ -57,14 +59,22 @@ func main() {
 		log.Fatalf("Falha ao conectar ao banco de dados: %v", err)
 	}
 
-	// Inicializa o repositório
+	// Inicializa os repositórios
 	customerRepo := repository.NewPostgresCustomerRepository(db)
+	idempotencyRepo := repository.NewPostgresIdempotencyRepository(db)
+	segmentRepo := repository.NewPostgresSegmentRepository(db)
 
-	// Inicializa o serviço
+	// Inicializa os serviços
 	customerService := service.NewCustomerService(customerRepo)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.IdempotencyTTL)
+	segmentService := service.NewSegmentService(segmentRepo, customerService)
 
-	// Inicializa o handler
+	// Remove periodicamente as chaves de idempotência expiradas
+	go purgeExpiredIdempotencyKeys(idempotencyService, time.Hour)
+
+	// Inicializa os handlers
 	customerHandler := handler.NewCustomerHandler(customerService)
+	segmentHandler := handler.NewSegmentHandler(segmentService)
 
 	// Configura o router
 	router := gin.Default()
@@ -72,8 +82,10 @@ func main() {
 	// Adiciona middleware
 	router.Use(middleware.Logger())
 
-	// Registra as rotas
-	customerHandler.RegisterRoutes(router)
+	// Registra as rotas. A idempotência vale somente para a criação de clientes, para que as rotas que leem o
+	// corpo em fluxo, como a importação, não o carreguem em memória.
+	customerHandler.RegisterRoutes(router, middleware.Idempotency(idempotencyService))
+	segmentHandler.RegisterRoutes(router)
 
 	// Adiciona rota de health check
 	router.GET("/health", func(c *gin.Context) {
@@ -92,3 +104,12 @@ func main() {
 		log.Fatalf("Falha ao iniciar o servidor: %v", err)
 	}
 }
+
+// purgeExpiredIdempotencyKeys remove as chaves de idempotência expiradas a cada intervalo
+func purgeExpiredIdempotencyKeys(idempotencyService service.IdempotencyService, interval time.Duration) {
+	for range time.Tick(interval) {
+		if _, err := idempotencyService.PurgeExpired(context.Background()); err != nil {
+			log.Printf("Falha ao remover chaves de idempotência expiradas: %v", err)
+		}
+	}
+}
@@ -26,58 +26,196 @@ const docTemplate = `{
     "paths": {
         "/customers": {
             "get": {
-                "description": "Retorna uma lista com todos os clientes cadastrados",
+                "description": "Retorna uma página de clientes cadastrados, ordenados por data de criação. Os filtros informados são combinados com AND. Os links para as páginas seguinte e anterior são enviados no cabeçalho Link.\nPara percorrer toda a base de forma estável, utilize o cursor retornado em next_cursor.\nO parâmetro filter aceita expressões RSQL sobre os campos id, name, email, phone, address, active, created_at e updated_at, com os operadores ==, !=, =lt= (\u003c), =le= (\u003c=), =gt= (\u003e), =ge= (\u003e=), =in=, =out=, =like= e =ilike=; nos dois últimos, * representa qualquer sequência de caracteres.\nValores com espaços ou caracteres reservados devem ficar entre aspas. Expressões inválidas retornam 400 com a posição do erro em position.\nCom o cabeçalho If-None-Match, retorna 304 quando o conteúdo da página não mudou. If-Modified-Since considera apenas a alteração mais recente entre os clientes da página e não detecta exclusões.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "customers"
                 ],
-                "summary": "Listar todos os clientes",
+                "summary": "Listar clientes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Número da página",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Quantidade de registros por página (máximo 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco retornado em next_cursor (paginação por keyset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de ordenação separados por vírgula; prefixo - para ordem decrescente (ex: -created_at,name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filtra clientes ativos ou inativos",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Criados após a data (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Criados antes da data (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Atualizados desde a data (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domínio do email (ex: example.com)",
+                        "name": "email_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Prefixo numérico do telefone (ex: 11)",
+                        "name": "phone_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expressão de filtro RSQL: comparações unidas por ; (AND) e , (OR), com parênteses (ex: active==true;(name=like=Silva*,email=like=*@corp.com))",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui os clientes excluídos logicamente (uso administrativo)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag recebida anteriormente",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Valor de Last-Modified recebido anteriormente",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Customer"
+                            "$ref": "#/definitions/handler.CustomerPageResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Hash do conteúdo da página"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Data da alteração mais recente entre os clientes da página"
+                            },
+                            "Link": {
+                                "type": "string",
+                                "description": "Links para as páginas seguinte e anterior (RFC 8288)"
                             }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Cria um novo cliente com os dados fornecidos",
+                "description": "Cria um novo cliente com os dados fornecidos.\nCom o cabeçalho Idempotency-Key, repetições da mesma requisição retornam a resposta original sem criar outro cliente.\nUm email já utilizado por outro cliente, sem distinção entre maiúsculas e minúsculas, retorna 409 com o campo em conflito.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "customers"
                 ],
                 "summary": "Criar um novo cliente",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chave que identifica a requisição para repetições seguras",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
                     {
                         "description": "Dados do cliente",
                         "name": "customer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Customer"
+                            "$ref": "#/definitions/handler.CreateCustomerRequest"
                         }
                     }
                 ],
@@ -85,197 +223,247 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.Customer"
+                            "$ref": "#/definitions/handler.CustomerResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão do cliente"
+                            },
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "Presente quando a resposta é a da requisição original"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "406": {
+                        "description": "Not Acceptable",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
-                    }
-                }
-            }
-        },
-        "/customers/count": {
-            "get": {
-                "description": "Retorna o número total de clientes cadastrados no sistema",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "customers"
-                ],
-                "summary": "Contar clientes",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "$ref": "#/definitions/utils.CountResponse"
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/utils.ErrorResponse"
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
             }
         },
-        "/customers/search": {
-            "get": {
-                "description": "Retorna uma lista de clientes que correspondem ao nome fornecido",
+        "/customers/batch": {
+            "post": {
+                "description": "Valida e cria até 1000 clientes, inseridos em lotes, e retorna 207 com o resultado de cada cliente na ordem enviada.\nNo modo all_or_nothing (padrão), a falha de qualquer cliente impede a criação de todos e os demais recebem o status 424.\nNo modo best_effort, os clientes válidos são criados mesmo que outros falhem.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "customers"
                 ],
-                "summary": "Buscar clientes por nome",
+                "summary": "Criar clientes em lote",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Nome do cliente",
-                        "name": "name",
-                        "in": "query",
-                        "required": true
+                        "description": "Clientes e modo de criação",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BatchCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "207": {
+                        "description": "Multi-Status",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Customer"
-                            }
+                            "$ref": "#/definitions/handler.BatchCreateResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
             }
         },
-        "/customers/{id}": {
-            "get": {
-                "description": "Retorna os dados de um cliente específico com base no ID",
+        "/customers/bulk": {
+            "delete": {
+                "description": "Exclui logicamente os clientes selecionados pela lista de IDs (até 1000) ou pelo filtro, em uma única transação.\nO filtro aceita, em expression, uma expressão RSQL como a do parâmetro filter da listagem; expressões inválidas retornam 400 com a posição do erro em position.\nCom dry_run=true, nada é excluído e a resposta traz a quantidade de clientes que seriam excluídos.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "customers"
                 ],
-                "summary": "Buscar cliente por ID",
+                "summary": "Excluir clientes em massa",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID do Cliente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Simula a operação sem excluir os clientes",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Seleção dos clientes",
+                        "name": "bulk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BulkDeleteRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Customer"
+                            "$ref": "#/definitions/handler.BulkResultResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "406": {
+                        "description": "Not Acceptable",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Atualiza os dados de um cliente existente",
+            "patch": {
+                "description": "Aplica as alterações de set aos clientes selecionados pela lista de IDs (até 1000) ou pelo filtro, em uma única transação.\nO filtro aceita, em expression, uma expressão RSQL como a do parâmetro filter da listagem; expressões inválidas retornam 400 com a posição do erro em position.\nClientes cujos valores já correspondem às alterações são contados em matched, mas não são alterados.\nCom dry_run=true, nada é alterado e a resposta traz as contagens que a operação teria.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "customers"
                 ],
-                "summary": "Atualizar cliente",
+                "summary": "Atualizar clientes em massa",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID do Cliente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Simula a operação sem alterar os clientes",
+                        "name": "dry_run",
+                        "in": "query"
                     },
                     {
-                        "description": "Dados atualizados do cliente",
-                        "name": "customer",
+                        "description": "Seleção dos clientes e campos alterados",
+                        "name": "bulk",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Customer"
+                            "$ref": "#/definitions/handler.BulkUpdateRequest"
                         }
                     }
                 ],
@@ -283,139 +471,1777 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Customer"
+                            "$ref": "#/definitions/handler.BulkResultResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "406": {
+                        "description": "Not Acceptable",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove um cliente do sistema com base no ID",
+            }
+        },
+        "/customers/count": {
+            "get": {
+                "description": "Retorna o número total de clientes cadastrados no sistema",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "customers"
                 ],
-                "summary": "Excluir cliente",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID do Cliente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Contar clientes",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.CountResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "406": {
+                        "description": "Not Acceptable",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Customer": {
-            "description": "Entidade que representa um cliente no sistema",
-            "type": "object",
-            "required": [
-                "email",
-                "name"
-            ],
-            "properties": {
-                "active": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "address": {
-                    "type": "string",
-                    "example": "Av. Paulista, 1000, São Paulo - SP"
-                },
-                "created_at": {
-                    "type": "string",
-                    "example": "2025-04-23T15:04:05Z"
-                },
-                "email": {
-                    "type": "string",
-                    "example": "joao@example.com"
-                },
-                "id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3,
-                    "example": "João da Silva"
-                },
-                "phone": {
-                    "type": "string",
-                    "maxLength": 15,
-                    "minLength": 8,
-                    "example": "(11) 98765-4321"
-                },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2025-04-23T15:04:05Z"
-                }
+        },
+        "/customers/export": {
+            "get": {
+                "description": "Exporta todos os clientes que atendem aos filtros, sem paginação, no formato informado (csv, padrão, ou ndjson).\nOs clientes são lidos do banco de dados com um cursor e escritos na resposta à medida que são lidos, com uso de memória constante.\nOs filtros e a ordenação são os mesmos da listagem.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Exportar clientes",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Formato da exportação",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de ordenação separados por vírgula; prefixo - para ordem decrescente (ex: -created_at,name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filtra clientes ativos ou inativos",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Criados após a data (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Criados antes da data (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Atualizados desde a data (RFC 3339 ou AAAA-MM-DD)",
+                        "name": "updated_since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domínio do email (ex: example.com)",
+                        "name": "email_domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Prefixo numérico do telefone (ex: 11)",
+                        "name": "phone_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expressão de filtro RSQL: comparações unidas por ; (AND) e , (OR), com parênteses (ex: active==true;(name=like=Silva*,email=like=*@corp.com))",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Inclui os clientes excluídos logicamente (uso administrativo)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Clientes exportados",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/import": {
+            "post": {
+                "description": "Importa os clientes de um arquivo CSV cujo cabeçalho indica as colunas, em qualquer ordem: name e email (obrigatórias), phone, address e active (padrão true).\nCada linha é validada e as válidas são inseridas em lotes; linhas inválidas ou com email já cadastrado são rejeitadas sem impedir a importação das demais.\nA resposta é um relatório CSV das linhas rejeitadas, com o número da linha no arquivo e o motivo.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Importar clientes de CSV",
+                "parameters": [
+                    {
+                        "description": "Arquivo CSV com linha de cabeçalho",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Relatório das linhas rejeitadas (line,reason)",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Imported-Count": {
+                                "type": "int",
+                                "description": "Quantidade de clientes importados"
+                            },
+                            "X-Rejected-Count": {
+                                "type": "int",
+                                "description": "Quantidade de linhas rejeitadas"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/search": {
+            "get": {
+                "description": "Retorna os clientes que atendem a todos os termos informados; ao menos um de q, name, email, phone ou fulltext é obrigatório.\nO parâmetro q é procurado no nome, no email, no endereço e, quando tem a forma de um telefone com ao menos 3 dígitos, nos dígitos do telefone.\nNome e endereço são comparados por semelhança de trigramas, de 0 a 1, sem diferenciar maiúsculas, minúsculas e acentos (ex: Joao encontra João); somente pontuações de ao menos min_score são consideradas.\nEmail e telefone são encontrados quando contêm o termo, com pontuação 1; o telefone é comparado apenas pelos dígitos, ignorando a formatação.\nO parâmetro fulltext faz uma busca textual em português no nome e no endereço, com a sintaxe de websearch_to_tsquery (\"frases entre aspas\", OR e -exclusão), comparando os radicais das palavras.\nSeus resultados são pontuados por ts_rank, de 0 a 1, e trazem em headline o trecho do nome e do endereço com os termos destacados entre \u003cb\u003e e \u003c/b\u003e.\nCada resultado indica em matched_field o campo de maior pontuação. Sem o parâmetro sort, os clientes são ordenados da maior para a menor pontuação.\nOs resultados são paginados por page e page_size, com no máximo 100 clientes por página; a paginação por cursor não é aceita.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Buscar clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Termo procurado no nome, email, telefone e endereço",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome do cliente",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parte do email do cliente",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parte do telefone do cliente, com ao menos 3 dígitos; somente os dígitos são considerados",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Busca textual no nome e no endereço, na sintaxe de websearch_to_tsquery",
+                        "name": "fulltext",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.3,
+                        "description": "Pontuação mínima de semelhança do nome e do endereço, maior que 0 e no máximo 1",
+                        "name": "min_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de ordenação separados por vírgula; prefixo - para ordem decrescente (ex: name,-created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Número da página",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Quantidade de registros por página (máximo 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.CustomerSearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/{id}": {
+            "get": {
+                "description": "Retorna os dados de um cliente específico com base no ID.\nCom os cabeçalhos If-None-Match ou If-Modified-Since, retorna 304 quando o cliente não foi alterado.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Buscar cliente por ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag recebida anteriormente",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Valor de Last-Modified recebido anteriormente",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CustomerResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versão e representação do cliente (ex: 4-json)"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Data da última alteração do cliente"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Atualiza os dados de um cliente existente. Com o cabeçalho If-Match, a alteração só é aplicada se o cliente ainda estiver na versão informada.\nUm email já utilizado por outro cliente retorna 409 com o campo em conflito.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Atualizar cliente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão esperada do cliente",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Dados atualizados do cliente",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateCustomerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CustomerResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão do cliente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Exclui logicamente um cliente com base no ID. O cliente deixa de ser retornado pela API, mas seu histórico é mantido e ele pode ser restaurado.\nCom o cabeçalho If-Match, a exclusão só é aplicada se o cliente ainda estiver na versão informada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Excluir cliente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão esperada do cliente",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Aplica um JSON Merge Patch (RFC 7396) ou um JSON Patch (RFC 6902) à representação do cliente retornada pela API, conforme o Content-Type.\nSomente name, email, phone, address e active podem ser alterados; patches sobre id, created_at, updated_at, deleted_at ou campos fora da representação retornam 400. A operação test pode comparar qualquer campo.\nNo Merge Patch, somente os campos presentes no documento são alterados; phone e address com valor null são removidos, e null em name, email ou active retorna 400, assim como a operação remove sobre eles no JSON Patch.\nNo JSON Patch, as operações são aplicadas em uma única transação e uma operação test não satisfeita retorna 409.\nUm email já utilizado por outro cliente também retorna 409, com o campo em conflito.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Atualizar cliente parcialmente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag da versão esperada do cliente",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Documento JSON Merge Patch ou lista de operações JSON Patch",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CustomerResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão do cliente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/{id}/purge": {
+            "delete": {
+                "description": "Remove definitivamente um cliente, excluído logicamente ou não, e todo o seu histórico. Esta operação administrativa não pode ser desfeita.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Remover cliente definitivamente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/{id}/restore": {
+            "post": {
+                "description": "Restaura um cliente excluído logicamente. Retorna 409 quando o cliente não está excluído ou quando seu email já é utilizado por outro cliente.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Restaurar cliente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CustomerResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nova versão do cliente"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/segments": {
+            "get": {
+                "description": "Retorna todos os segmentos cadastrados, ordenados pelo nome",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Listar segmentos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handler.SegmentResponse"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Salva uma busca de clientes como um segmento: um nome e uma expressão de filtro RSQL, com a mesma sintaxe do parâmetro filter da listagem de clientes.\nA expressão é validada na criação; expressões inválidas retornam 400 com a posição do erro em position.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Criar um novo segmento",
+                "parameters": [
+                    {
+                        "description": "Dados do segmento",
+                        "name": "segment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SegmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SegmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/segments/{id}": {
+            "get": {
+                "description": "Retorna o nome e a expressão de filtro de um segmento",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Buscar segmento por ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Segmento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SegmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Substitui o nome e a expressão de filtro de um segmento. A expressão é validada como na criação.",
+                "consumes": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Atualizar segmento",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Segmento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados atualizados do segmento",
+                        "name": "segment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SegmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.SegmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove um segmento com base no ID. Os clientes do segmento não são alterados.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Excluir segmento",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Segmento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/segments/{id}/count": {
+            "get": {
+                "description": "Avalia a expressão de filtro do segmento e retorna o número de clientes que a atendem, para pré-visualizar o tamanho do segmento",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Contar clientes do segmento",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Segmento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.CountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/segments/{id}/customers": {
+            "get": {
+                "description": "Avalia a expressão de filtro do segmento e retorna uma página dos clientes que a atendem, com a mesma paginação, ordenação e cabeçalho Link da listagem de clientes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "segments"
+                ],
+                "summary": "Listar clientes do segmento",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID do Segmento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Número da página",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Quantidade de registros por página (máximo 100)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco retornado em next_cursor (paginação por keyset)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos de ordenação separados por vírgula; prefixo - para ordem decrescente (ex: -created_at,name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CustomerPageResponse"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links para as páginas seguinte e anterior (RFC 8288)"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.BatchCreateRequest": {
+            "description": "Clientes a serem criados em lote e o modo de criação",
+            "type": "object",
+            "properties": {
+                "customers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.CreateCustomerRequest"
+                    }
+                },
+                "mode": {
+                    "description": "Mode define se o lote é criado por completo ou nada (all_or_nothing, padrão) ou se os clientes válidos são criados mesmo com falhas (best_effort)",
+                    "type": "string",
+                    "enum": [
+                        "all_or_nothing",
+                        "best_effort"
+                    ],
+                    "example": "best_effort"
+                }
+            }
+        },
+        "handler.BatchCreateResponse": {
+            "description": "Resultado da criação em lote, com um item para cada cliente enviado",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "best_effort"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.BatchItemResponse"
+                    }
+                }
+            }
+        },
+        "handler.BatchItemResponse": {
+            "description": "Resultado da criação de um cliente do lote: o cliente criado ou o erro",
+            "type": "object",
+            "properties": {
+                "customer": {
+                    "$ref": "#/definitions/handler.CustomerResponse"
+                },
+                "error": {
+                    "$ref": "#/definitions/utils.Problem"
+                },
+                "index": {
+                    "description": "Index é a posição do cliente na lista enviada",
+                    "type": "integer",
+                    "example": 0
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 201
+                }
+            }
+        },
+        "handler.BulkChangesRequest": {
+            "description": "Campos alterados nos clientes selecionados; campos omitidos não são alterados",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handler.BulkDeleteRequest": {
+            "description": "Clientes a serem excluídos, selecionados pela lista de IDs ou pelo filtro",
+            "type": "object",
+            "properties": {
+                "filter": {
+                    "$ref": "#/definitions/handler.BulkFilterRequest"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                }
+            }
+        },
+        "handler.BulkFilterRequest": {
+            "description": "Critérios de filtro de uma operação em massa, com o mesmo significado dos filtros da listagem",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created_after": {
+                    "type": "string",
+                    "example": "2025-01-01"
+                },
+                "created_before": {
+                    "type": "string",
+                    "example": "2025-12-31T23:59:59Z"
+                },
+                "email_domain": {
+                    "type": "string",
+                    "example": "partner.com"
+                },
+                "expression": {
+                    "description": "Expression é uma expressão de filtro com a mesma sintaxe do parâmetro filter da listagem",
+                    "type": "string",
+                    "example": "name=like=Silva*,email=like=*@corp.com"
+                },
+                "phone_prefix": {
+                    "type": "string",
+                    "example": "11"
+                },
+                "updated_since": {
+                    "type": "string",
+                    "example": "2025-04-23"
+                }
+            }
+        },
+        "handler.BulkResultResponse": {
+            "description": "Contagens de uma operação em massa. Em uma simulação (dry_run), nada é alterado.",
+            "type": "object",
+            "properties": {
+                "affected": {
+                    "description": "Affected é a quantidade de clientes alterados, ou que seriam alterados em uma simulação",
+                    "type": "integer",
+                    "example": 10
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "matched": {
+                    "description": "Matched é a quantidade de clientes selecionados",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "handler.BulkUpdateRequest": {
+            "description": "Clientes a serem alterados, selecionados pela lista de IDs ou pelo filtro, e os campos alterados",
+            "type": "object",
+            "properties": {
+                "filter": {
+                    "$ref": "#/definitions/handler.BulkFilterRequest"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "set": {
+                    "$ref": "#/definitions/handler.BulkChangesRequest"
+                }
+            }
+        },
+        "handler.CreateCustomerRequest": {
+            "description": "Dados para a criação de um cliente",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active é verdadeiro quando não informado",
+                    "type": "boolean",
+                    "example": true
+                },
+                "address": {
+                    "type": "string",
+                    "example": "Av. Paulista, 1000, São Paulo - SP"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "joao@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "João da Silva"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "(11) 98765-4321"
+                }
+            }
+        },
+        "handler.CustomerPageResponse": {
+            "description": "Envelope de resposta paginada da listagem de clientes. Na paginação por cursor, page e total não são retornados.",
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.CustomerResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJjIjoiMjAyNS0wNC0yM1QxNTowNDowNVoiLCJpIjo0Mn0"
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handler.CustomerResponse": {
+            "description": "Cliente retornado pela API",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "address": {
+                    "type": "string",
+                    "example": "Av. Paulista, 1000, São Paulo - SP"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-23T15:04:05Z"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt é informado somente para clientes excluídos logicamente",
+                    "type": "string",
+                    "example": "2025-05-01T10:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "joao@example.com"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "João da Silva"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "(11) 98765-4321"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-04-23T15:04:05Z"
+                }
+            }
+        },
+        "handler.CustomerSearchResult": {
+            "description": "Cliente encontrado na busca, com o campo de maior pontuação e a pontuação, de 0 a 1",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "address": {
+                    "type": "string",
+                    "example": "Av. Paulista, 1000, São Paulo - SP"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-23T15:04:05Z"
+                },
+                "deleted_at": {
+                    "description": "DeletedAt é informado somente para clientes excluídos logicamente",
+                    "type": "string",
+                    "example": "2025-05-01T10:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "joao@example.com"
+                },
+                "headline": {
+                    "description": "Headline é informado somente na busca textual, com os termos encontrados entre \u003cb\u003e e \u003c/b\u003e",
+                    "type": "string",
+                    "example": "João da Silva - Rua das \u003cb\u003eFlores\u003c/b\u003e, 100"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "matched_field": {
+                    "description": "MatchedField é um dos campos name, email, phone ou address, ou fulltext quando os termos da busca textual\nestão distribuídos entre o nome e o endereço",
+                    "type": "string",
+                    "example": "phone"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "João da Silva"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "(11) 98765-4321"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-04-23T15:04:05Z"
+                }
+            }
+        },
+        "handler.SegmentRequest": {
+            "description": "Nome do segmento e a expressão de filtro que seleciona seus clientes",
+            "type": "object",
+            "properties": {
+                "filter": {
+                    "description": "Filter é uma expressão de filtro RSQL, com a mesma sintaxe do parâmetro filter da listagem de clientes",
+                    "type": "string",
+                    "example": "active==true;email=ilike=*@corp.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Clientes ativos da Corp"
+                }
+            }
+        },
+        "handler.SegmentResponse": {
+            "description": "Segmento de clientes retornado pela API",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-04-23T15:04:05Z"
+                },
+                "filter": {
+                    "type": "string",
+                    "example": "active==true;email=ilike=*@corp.com"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Clientes ativos da Corp"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-04-23T15:04:05Z"
+                }
+            }
+        },
+        "handler.UpdateCustomerRequest": {
+            "description": "Dados para a atualização de um cliente",
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "address": {
+                    "type": "string",
+                    "example": "Av. Paulista, 1000, São Paulo - SP"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "joao@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "João da Silva"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "(11) 98765-4321"
+                }
             }
         },
         "utils.CountResponse": {
@@ -428,13 +2254,65 @@ const docTemplate = `{
                 }
             }
         },
-        "utils.ErrorResponse": {
-            "description": "Modelo padrão para respostas de erro da API",
+        "utils.FieldError": {
+            "description": "Campo que não atende a uma regra de validação",
             "type": "object",
             "properties": {
-                "error": {
+                "field": {
+                    "type": "string",
+                    "example": "email"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "deve ser um email válido"
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "email"
+                }
+            }
+        },
+        "utils.Problem": {
+            "description": "Resposta de erro no formato application/problem+json (RFC 7807)",
+            "type": "object",
+            "properties": {
+                "allowed_values": {
+                    "description": "AllowedValues lista os valores aceitos quando o problema é um parâmetro fora da lista permitida",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "dados do cliente inválidos"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/customers"
+                },
+                "position": {
+                    "description": "Position indica o caractere, a partir de 1, em que foi encontrado o erro de uma expressão inválida",
+                    "type": "integer",
+                    "example": 14
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Bad Request"
+                },
+                "type": {
                     "type": "string",
-                    "example": "Mensagem de erro"
+                    "example": "about:blank"
                 }
             }
         }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -15,6 +16,8 @@ type Config struct {
 	DBPassword  string `mapstructure:"DB_PASSWORD"`
 	DBName      string `mapstructure:"DB_NAME"`
 	Environment string `mapstructure:"ENVIRONMENT"`
+	// IdempotencyTTL é o tempo de validade das chaves de idempotência
+	IdempotencyTTL time.Duration `mapstructure:"IDEMPOTENCY_TTL"`
 }
 
 // LoadConfig carrega as configurações das variáveis de ambiente
@@ -41,13 +44,14 @@ func LoadConfig() (*Config, error) {
 	viper.AutomaticEnv()
 
 	config := &Config{
-		ServerPort:  viper.GetString("SERVER_PORT"),
-		DBHost:      viper.GetString("DB_HOST"),
-		DBPort:      viper.GetString("DB_PORT"),
-		DBUser:      viper.GetString("DB_USER"),
-		DBPassword:  viper.GetString("DB_PASSWORD"),
-		DBName:      viper.GetString("DB_NAME"),
-		Environment: viper.GetString("ENVIRONMENT"),
+		ServerPort:     viper.GetString("SERVER_PORT"),
+		DBHost:         viper.GetString("DB_HOST"),
+		DBPort:         viper.GetString("DB_PORT"),
+		DBUser:         viper.GetString("DB_USER"),
+		DBPassword:     viper.GetString("DB_PASSWORD"),
+		DBName:         viper.GetString("DB_NAME"),
+		Environment:    viper.GetString("ENVIRONMENT"),
+		IdempotencyTTL: viper.GetDuration("IDEMPOTENCY_TTL"),
 	}
 
 	// Valores padrão
@@ -57,6 +61,9 @@ func LoadConfig() (*Config, error) {
 	if config.Environment == "" {
 		config.Environment = "development"
 	}
+	if config.IdempotencyTTL <= 0 {
+		config.IdempotencyTTL = 24 * time.Hour
+	}
 
 	return config, nil
 }
@@ -0,0 +1,32 @@
+package model
+
+// BulkSelection identifica os clientes de uma operação em massa: a lista de IDs ou,
+// quando ela não é informada, os clientes que atendem ao filtro
+type BulkSelection struct {
+	IDs    []uint
+	Filter CustomerFilter
+}
+
+// IsEmpty indica se nenhum critério de seleção foi informado
+func (s BulkSelection) IsEmpty() bool {
+	return len(s.IDs) == 0 && s.Filter.IsEmpty()
+}
+
+// CustomerChanges contém os campos alterados em uma atualização em massa; campos nulos não são alterados
+type CustomerChanges struct {
+	Active *bool
+}
+
+// IsEmpty indica se nenhuma alteração foi informada
+func (c CustomerChanges) IsEmpty() bool {
+	return c.Active == nil
+}
+
+// BulkResult contém as contagens de uma operação em massa
+type BulkResult struct {
+	// Matched é a quantidade de clientes selecionados
+	Matched int64
+	// Affected é a quantidade de clientes alterados, ou que seriam alterados em uma simulação
+	Affected int64
+	DryRun   bool
+}
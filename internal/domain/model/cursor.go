@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Cursor representa a posição do último cliente retornado em uma ordenação.
+// Values contém os valores do cliente para cada campo de Sort, na mesma ordem.
+type Cursor struct {
+	Sort   Sort
+	Values []any
+}
+
+// cursorToken é a representação serializada do cursor
+type cursorToken struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// CursorFor retorna o cursor que aponta para o cliente informado na ordenação informada
+func CursorFor(customer *Customer, sort Sort) Cursor {
+	return Cursor{Sort: sort, Values: sort.Values(customer)}
+}
+
+// Encode serializa o cursor em um token opaco seguro para URLs
+func (c Cursor) Encode() string {
+	token := cursorToken{Sort: c.Sort.String(), Values: make([]json.RawMessage, len(c.Values))}
+	for i, value := range c.Values {
+		token.Values[i], _ = json.Marshal(value)
+	}
+
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor converte um token opaco em um Cursor
+func DecodeCursor(token string) (Cursor, error) {
+	var cursor Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, fmt.Errorf("cursor mal formado: %w", err)
+	}
+
+	var decoded cursorToken
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return cursor, fmt.Errorf("cursor mal formado: %w", err)
+	}
+
+	sort, err := ParseSort(decoded.Sort)
+	if err != nil {
+		return cursor, err
+	}
+	if len(sort) == 0 || len(sort) != len(decoded.Values) {
+		return cursor, fmt.Errorf("cursor incompatível com a ordenação %q", decoded.Sort)
+	}
+
+	cursor.Sort = sort
+	cursor.Values = make([]any, len(sort))
+	for i, field := range sort {
+		value := newSortValue(field.Field)
+		if err := json.Unmarshal(decoded.Values[i], value); err != nil {
+			return Cursor{}, fmt.Errorf("valor inválido no cursor para %q: %w", field.Field, err)
+		}
+		cursor.Values[i] = reflect.ValueOf(value).Elem().Interface()
+	}
+
+	return cursor, nil
+}
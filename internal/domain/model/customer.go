@@ -1,26 +1,73 @@
 package model
 
 import (
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
 )
 
 // Customer representa a entidade de cliente no sistema
 // @Description Entidade que representa um cliente no sistema
 type Customer struct {
-	ID        uint      `json:"id" gorm:"primaryKey" example:"1"`
+	ID        uint      `json:"id" gorm:"primaryKey;index:idx_customers_created_at_id,priority:2" example:"1"`
 	Name      string    `json:"name" validate:"required,min=3,max=100" example:"João da Silva"`
 	Email     string    `json:"email" validate:"required,email" example:"joao@example.com"`
 	Phone     string    `json:"phone" validate:"omitempty,min=8,max=15" example:"(11) 98765-4321"`
 	Address   string    `json:"address" validate:"omitempty" example:"Av. Paulista, 1000, São Paulo - SP"`
-	Active    bool      `json:"active" gorm:"default:true" example:"true"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime" example:"2025-04-23T15:04:05Z"`
+	Active    bool      `json:"active" example:"true"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_customers_created_at_id,priority:1" example:"2025-04-23T15:04:05Z"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime" example:"2025-04-23T15:04:05Z"`
+	// Version é incrementada a cada alteração e exposta somente no cabeçalho ETag
+	Version uint `json:"-" gorm:"not null;default:1"`
+	// DeletedAt é preenchido na exclusão lógica; clientes excluídos são omitidos das consultas por padrão
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index" swaggerignore:"true"`
+}
+
+// IsDeleted indica se o cliente foi excluído logicamente
+func (c *Customer) IsDeleted() bool {
+	return c.DeletedAt.Valid
+}
+
+// validate valida as entidades usando os nomes dos campos em JSON nos erros
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
 }
 
 // Validate valida os campos do cliente
 func (c *Customer) Validate() error {
-	validate := validator.New()
 	return validate.Struct(c)
 }
+
+// ChangedFields retorna as colunas editáveis cujo valor difere entre os dois clientes
+func ChangedFields(before, after *Customer) []string {
+	var fields []string
+	if before.Name != after.Name {
+		fields = append(fields, "name")
+	}
+	if before.Email != after.Email {
+		fields = append(fields, "email")
+	}
+	if before.Phone != after.Phone {
+		fields = append(fields, "phone")
+	}
+	if before.Address != after.Address {
+		fields = append(fields, "address")
+	}
+	if before.Active != after.Active {
+		fields = append(fields, "active")
+	}
+	return fields
+}
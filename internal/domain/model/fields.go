@@ -0,0 +1,42 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SelectableCustomerFields são os campos de Customer que podem ser selecionados nas leituras, na ordem em que
+// são retornados
+var SelectableCustomerFields = []string{"id", "name", "email", "phone", "address", "active", "created_at", "updated_at", "deleted_at"}
+
+// FieldSet representa os campos selecionados de uma leitura (sparse fieldset).
+// Um conjunto vazio seleciona todos os campos.
+type FieldSet []string
+
+// ParseFields converte uma especificação como "id,name,email" em um FieldSet.
+// Somente campos de SelectableCustomerFields são aceitos.
+func ParseFields(spec string) (FieldSet, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	var fields FieldSet
+	for _, part := range strings.Split(spec, ",") {
+		field := strings.TrimSpace(part)
+		if !slices.Contains(SelectableCustomerFields, field) {
+			return nil, fmt.Errorf("campo inválido em fields: %q", field)
+		}
+		if slices.Contains(fields, field) {
+			return nil, fmt.Errorf("campo repetido em fields: %q", field)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// Includes indica se o campo foi selecionado
+func (f FieldSet) Includes(field string) bool {
+	return len(f) == 0 || slices.Contains(f, field)
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/rsql"
+)
+
+// FilterableCustomerFields são as colunas de Customer que podem ser usadas nas expressões de filtro
+var FilterableCustomerFields = rsql.Schema{
+	"id":         rsql.Integer,
+	"name":       rsql.String,
+	"email":      rsql.String,
+	"phone":      rsql.String,
+	"address":    rsql.String,
+	"active":     rsql.Boolean,
+	"created_at": rsql.Time,
+	"updated_at": rsql.Time,
+}
+
+// CustomerFilter representa os critérios de filtragem da listagem de clientes.
+// Os critérios informados são combinados com AND; campos vazios são ignorados.
+type CustomerFilter struct {
+	Active        *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedSince  *time.Time
+	EmailDomain   string
+	// PhonePrefix contém apenas os dígitos iniciais do telefone
+	PhonePrefix string
+	// Expression é uma expressão de filtro validada contra FilterableCustomerFields
+	Expression rsql.Node
+	// IncludeDeleted inclui na listagem os clientes excluídos logicamente
+	IncludeDeleted bool
+	// Fields limita as colunas lidas dos clientes listados; vazio lê todas
+	Fields FieldSet
+}
+
+// IsEmpty indica se nenhum critério de filtragem foi informado
+func (f CustomerFilter) IsEmpty() bool {
+	return f.Active == nil && f.CreatedAfter == nil && f.CreatedBefore == nil && f.UpdatedSince == nil &&
+		f.EmailDomain == "" && f.PhonePrefix == "" && f.Expression == nil
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// IdempotencyRecord representa uma chave de idempotência e a resposta da requisição original.
+// Enquanto a requisição original está em andamento, StatusCode é zero.
+type IdempotencyRecord struct {
+	Key          string `gorm:"primaryKey;size:255"`
+	Fingerprint  string `gorm:"size:64;not null"`
+	StatusCode   int    `gorm:"not null"`
+	ContentType  string `gorm:"size:255"`
+	ResponseBody []byte `gorm:"type:bytea"`
+	// ResponseHeaders contém os cabeçalhos da resposta original devolvidos nas repetições, como ETag e Location
+	ResponseHeaders map[string]string `gorm:"serializer:json;type:jsonb"`
+	CreatedAt       time.Time         `gorm:"not null"`
+	ExpiresAt       time.Time         `gorm:"not null;index"`
+}
+
+// TableName define o nome da tabela de chaves de idempotência
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsComplete indica se a resposta da requisição original já foi armazenada
+func (r *IdempotencyRecord) IsComplete() bool {
+	return r.StatusCode != 0
+}
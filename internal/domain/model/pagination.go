@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+const (
+	// DefaultPageSize é a quantidade de registros por página quando nenhuma é informada
+	DefaultPageSize = 20
+	// MaxPageSize é a quantidade máxima de registros por página aceita pelo servidor
+	MaxPageSize = 100
+)
+
+// Pagination representa os parâmetros de paginação de uma listagem.
+// Quando Cursor é informado, a paginação é feita por keyset e Page é ignorado.
+type Pagination struct {
+	Page     int
+	PageSize int
+	Cursor   string
+}
+
+// Normalize aplica os valores padrão e o limite máximo de registros por página
+func (p Pagination) Normalize() Pagination {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PageSize < 1 {
+		p.PageSize = DefaultPageSize
+	}
+	if p.PageSize > MaxPageSize {
+		p.PageSize = MaxPageSize
+	}
+	return p
+}
+
+// Offset retorna a quantidade de registros a serem ignorados antes da página atual
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// CustomerPage representa uma página de clientes
+// @Description Envelope de resposta paginada da listagem de clientes.
+// @Description Na paginação por cursor, page e total não são retornados.
+type CustomerPage struct {
+	Items      []*Customer `json:"items"`
+	Total      *int64      `json:"total,omitempty" example:"42"`
+	Page       int         `json:"page,omitempty" example:"1"`
+	PageSize   int         `json:"page_size" example:"20"`
+	NextCursor string      `json:"next_cursor,omitempty" example:"eyJjIjoiMjAyNS0wNC0yM1QxNTowNDowNVoiLCJpIjo0Mn0"`
+}
+
+// IsCursorPage indica se a página foi obtida por paginação de cursor
+func (p *CustomerPage) IsCursorPage() bool {
+	return p.Page == 0
+}
+
+// HasNext indica se existe uma próxima página
+func (p *CustomerPage) HasNext() bool {
+	return p.NextCursor != ""
+}
+
+// HasPrev indica se existe uma página anterior
+func (p *CustomerPage) HasPrev() bool {
+	return p.Page > 1
+}
+
+// LastModified retorna a data da alteração mais recente entre os clientes da página
+func (p *CustomerPage) LastModified() time.Time {
+	var last time.Time
+	for _, customer := range p.Items {
+		if customer.UpdatedAt.After(last) {
+			last = customer.UpdatedAt
+		}
+	}
+	return last
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Campos do cliente em que a busca pode encontrar o termo procurado
+const (
+	SearchFieldName    = "name"
+	SearchFieldEmail   = "email"
+	SearchFieldPhone   = "phone"
+	SearchFieldAddress = "address"
+	// SearchFieldFullText indica que os termos da busca textual estão distribuídos entre o nome e o endereço
+	SearchFieldFullText = "fulltext"
+)
+
+// MinPhoneSearchDigits é a quantidade mínima de dígitos procurados no telefone. Com menos dígitos, quase todos os
+// telefones corresponderiam ao termo, e o índice de trigramas não restringe a busca.
+const MinPhoneSearchDigits = 3
+
+// CustomerSearch representa os termos da busca de clientes.
+// Os termos informados são combinados com AND; campos vazios são ignorados.
+type CustomerSearch struct {
+	// Query é procurado no nome, no email, nos dígitos do telefone e no endereço
+	Query string
+	Name  string
+	// Email é procurado como parte do email, sem diferenciar maiúsculas e minúsculas
+	Email string
+	// Phone contém apenas os dígitos procurados no telefone, ignorando a formatação, com ao menos
+	// MinPhoneSearchDigits dígitos
+	Phone string
+	// FullText é uma consulta de busca textual em português sobre o nome e o endereço, na sintaxe de buscadores
+	// da web: palavras, "frases entre aspas", OR e -exclusão
+	FullText string
+	// MinScore é a semelhança mínima, de 0 a 1, dos campos comparados por trigramas (nome e endereço)
+	MinScore float64
+	// Fields limita as colunas lidas dos clientes encontrados; vazio lê todas
+	Fields FieldSet
+}
+
+// IsEmpty indica se nenhum termo de busca foi informado
+func (s CustomerSearch) IsEmpty() bool {
+	return s.Query == "" && s.Name == "" && s.Email == "" && s.Phone == "" && s.FullText == ""
+}
+
+// CustomerMatch é um cliente encontrado na busca, com o campo de maior pontuação e a pontuação, de 0 a 1
+type CustomerMatch struct {
+	Customer     *Customer
+	MatchedField string
+	Score        float64
+	// Headline é o trecho do nome e do endereço com os termos da busca textual destacados, vazio nas demais buscas
+	Headline string
+}
+
+// PhoneDigits remove do telefone todos os caracteres que não são dígitos
+func PhoneDigits(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, value)
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/rsql"
+)
+
+// Segment representa uma busca salva: um grupo de clientes definido por uma expressão de filtro.
+// O filtro é armazenado como texto e avaliado a cada consulta, de modo que o segmento acompanha
+// as alterações dos clientes.
+type Segment struct {
+	ID   uint   `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"not null" validate:"required,min=3,max=100"`
+	// Filter é uma expressão de filtro RSQL sobre FilterableCustomerFields
+	Filter    string    `json:"filter" gorm:"not null" validate:"required,max=2000"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Validate valida os campos do segmento. A expressão de filtro é validada por CustomerFilter.
+func (s *Segment) Validate() error {
+	return validate.Struct(s)
+}
+
+// CustomerFilter converte a expressão do segmento no filtro de clientes. Os erros da expressão são do tipo *rsql.Error.
+func (s *Segment) CustomerFilter() (CustomerFilter, error) {
+	expression, err := rsql.Parse(s.Filter, FilterableCustomerFields)
+	if err != nil {
+		return CustomerFilter{}, err
+	}
+	return CustomerFilter{Expression: expression}, nil
+}
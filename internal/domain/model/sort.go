@@ -0,0 +1,122 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SortableCustomerFields são as colunas de Customer que podem ser usadas na ordenação
+var SortableCustomerFields = []string{"id", "name", "email", "phone", "active", "created_at", "updated_at"}
+
+// DefaultSort é a ordenação aplicada quando nenhuma é informada
+var DefaultSort = Sort{{Field: "created_at"}, {Field: "id"}}
+
+// SortField representa um campo de ordenação e sua direção
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Sort representa uma ordenação por múltiplos campos, aplicados na ordem informada
+type Sort []SortField
+
+// ParseSort converte uma especificação como "-created_at,name" em um Sort.
+// O prefixo "-" indica ordem decrescente. Somente campos de SortableCustomerFields são aceitos.
+func ParseSort(spec string) (Sort, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	var sort Sort
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		field.Field = strings.TrimPrefix(field.Field, "+")
+
+		if !isSortable(field.Field) {
+			return nil, fmt.Errorf("campo de ordenação inválido: %q", field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("campo de ordenação repetido: %q", field.Field)
+		}
+		seen[field.Field] = true
+		sort = append(sort, field)
+	}
+
+	return sort, nil
+}
+
+// WithTiebreaker retorna a ordenação acrescida do id como critério de desempate,
+// garantindo uma ordem total e estável. Uma ordenação vazia resulta em DefaultSort.
+func (s Sort) WithTiebreaker() Sort {
+	if len(s) == 0 {
+		return DefaultSort
+	}
+	for _, field := range s {
+		if field.Field == "id" {
+			return s
+		}
+	}
+	return append(append(Sort{}, s...), SortField{Field: "id"})
+}
+
+// String retorna a especificação textual da ordenação, no mesmo formato aceito por ParseSort
+func (s Sort) String() string {
+	parts := make([]string, len(s))
+	for i, field := range s {
+		parts[i] = field.Field
+		if field.Desc {
+			parts[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Values retorna os valores do cliente para cada campo da ordenação
+func (s Sort) Values(customer *Customer) []any {
+	values := make([]any, len(s))
+	for i, field := range s {
+		switch field.Field {
+		case "id":
+			values[i] = customer.ID
+		case "name":
+			values[i] = customer.Name
+		case "email":
+			values[i] = customer.Email
+		case "phone":
+			values[i] = customer.Phone
+		case "active":
+			values[i] = customer.Active
+		case "created_at":
+			values[i] = customer.CreatedAt
+		case "updated_at":
+			values[i] = customer.UpdatedAt
+		}
+	}
+	return values
+}
+
+// newSortValue retorna um ponteiro para o tipo de valor do campo de ordenação informado
+func newSortValue(field string) any {
+	switch field {
+	case "id":
+		return new(uint)
+	case "active":
+		return new(bool)
+	case "created_at", "updated_at":
+		return new(time.Time)
+	default:
+		return new(string)
+	}
+}
+
+func isSortable(field string) bool {
+	for _, sortable := range SortableCustomerFields {
+		if field == sortable {
+			return true
+		}
+	}
+	return false
+}
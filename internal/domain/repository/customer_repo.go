@@ -2,17 +2,33 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/wandermaia/customer-api/internal/domain/model"
 )
 
+var (
+	// ErrVersionConflict indica que a versão armazenada do registro difere da versão esperada
+	ErrVersionConflict = errors.New("versão do registro não corresponde à esperada")
+	// ErrNotDeleted indica que o registro a ser restaurado não está excluído
+	ErrNotDeleted = errors.New("registro não está excluído")
+)
+
 // CustomerRepository define as operações do repositório de clientes
 type CustomerRepository interface {
 	Create(ctx context.Context, customer *model.Customer) error
-	GetByID(ctx context.Context, id uint) (*model.Customer, error)
-	GetAll(ctx context.Context) ([]*model.Customer, error)
-	GetByName(ctx context.Context, name string) ([]*model.Customer, error)
+	CreateBatch(ctx context.Context, customers []*model.Customer, atomic bool) ([]error, error)
+	GetByID(ctx context.Context, id uint, fields ...string) (*model.Customer, error)
+	GetAll(ctx context.Context, filter model.CustomerFilter, sort model.Sort, pagination model.Pagination) ([]*model.Customer, int64, error)
+	GetAllAfter(ctx context.Context, filter model.CustomerFilter, cursor model.Cursor, limit int) ([]*model.Customer, error)
+	Search(ctx context.Context, search model.CustomerSearch, sort model.Sort, pagination model.Pagination) ([]*model.CustomerMatch, error)
+	Export(ctx context.Context, filter model.CustomerFilter, sort model.Sort, fn func(customer *model.Customer) error) error
 	Update(ctx context.Context, customer *model.Customer) error
-	Delete(ctx context.Context, id uint) error
-	Count(ctx context.Context) (int64, error)
+	UpdateInTx(ctx context.Context, id uint, apply func(current *model.Customer) (*model.Customer, error)) (*model.Customer, error)
+	Delete(ctx context.Context, id uint, version uint) error
+	UpdateMany(ctx context.Context, selection model.BulkSelection, changes model.CustomerChanges, dryRun bool) (*model.BulkResult, error)
+	DeleteMany(ctx context.Context, selection model.BulkSelection, dryRun bool) (*model.BulkResult, error)
+	Restore(ctx context.Context, id uint) (*model.Customer, error)
+	Purge(ctx context.Context, id uint) error
+	Count(ctx context.Context, filter model.CustomerFilter) (int64, error)
 }
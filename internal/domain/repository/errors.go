@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound indica que o registro procurado não existe. Erros de registro não encontrado
+	// do GORM (gorm.ErrRecordNotFound) são convertidos nele, mantendo a causa original.
+	ErrNotFound = errors.New("registro não encontrado")
+	// ErrUnavailable indica que o banco de dados não pôde ser acessado, como em falhas de conexão
+	// ou tempo limite excedido. A causa original é mantida no erro retornado.
+	ErrUnavailable = errors.New("banco de dados indisponível")
+)
+
+// uniqueViolationCode é o código de erro do PostgreSQL para violação de restrição de unicidade
+const uniqueViolationCode = "23505"
+
+// uniqueConstraintFields associa as restrições de unicidade ao campo que elas protegem
+var uniqueConstraintFields = map[string]string{
+	"idx_customers_email_lower": "email",
+}
+
+// DuplicateError indica que a operação violaria a restrição de unicidade do campo Field
+type DuplicateError struct {
+	Field string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("valor duplicado para o campo %s", e.Field)
+}
+
+// translateError converte erros do GORM e do PostgreSQL nos erros do repositório,
+// preservando a causa original para comparações com errors.Is e para os logs
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return &DuplicateError{Field: uniqueConstraintFields[pgErr.ConstraintName]}
+	}
+
+	if isUnavailable(err) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	return err
+}
+
+// isUnavailable indica se o erro é uma falha de acesso ao banco de dados, e não da operação em si.
+// O cancelamento da requisição pelo cliente (context.Canceled) não é uma falha do banco de dados, embora o pgx o
+// informe como tempo limite excedido quando a consulta já estava em andamento.
+func isUnavailable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Classe 08 (connection exception), admin_shutdown, crash_shutdown e cannot_connect_now
+		return strings.HasPrefix(pgErr.Code, "08") ||
+			pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	return errors.As(err, &connectErr) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		pgconn.Timeout(err)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	// connect retorna o erro do pgx ao conectar com o contexto informado, sem acessar um banco de dados.
+	connect := func(ctx context.Context) error {
+		_, err := pgconn.Connect(ctx, "host=127.0.0.1 port=1 user=customer_api")
+		return err
+	}
+
+	// Subteste para a conexão cancelada pelo cliente: o pgx informa uma falha de conexão, mas a causa é o
+	// cancelamento, que não é uma indisponibilidade do banco de dados.
+	t.Run("Canceled Connection", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := translateError(connect(ctx))
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrUnavailable)
+	})
+
+	// Subteste para a conexão com o tempo limite excedido, tratada como indisponibilidade do banco de dados.
+	t.Run("Connection Deadline Exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		err := translateError(connect(ctx))
+
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"slices"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// requiredColumns são as colunas lidas mesmo quando não selecionadas: o id, a versão e a data de alteração,
+// usados nos cabeçalhos ETag e Last-Modified, e a data de exclusão lógica, que identifica os clientes excluídos
+var requiredColumns = []string{"id", "version", "updated_at", "deleted_at"}
+
+// selectFields limita as colunas lidas aos campos selecionados. Sem campos selecionados, as colunas de todos os
+// campos de Customer são lidas, mas não as demais colunas da tabela, como a search_vector da busca textual.
+func selectFields(query *gorm.DB, fields model.FieldSet, sort model.Sort) *gorm.DB {
+	return query.Select(customerColumns(fields, sort))
+}
+
+// customerColumns retorna as colunas da tabela customers dos campos selecionados, ou de todos os campos quando
+// nenhum é selecionado, acrescidas das colunas obrigatórias e das colunas da ordenação, usadas nos cursores de paginação
+func customerColumns(fields model.FieldSet, sort model.Sort) []string {
+	if len(fields) == 0 {
+		fields = model.SelectableCustomerFields
+	}
+
+	var columns []string
+	add := func(field string) {
+		if column := "customers." + field; !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+
+	for _, field := range requiredColumns {
+		add(field)
+	}
+	for _, field := range fields {
+		add(field)
+	}
+	for _, field := range sort {
+		add(field.Field)
+	}
+	return columns
+}
+
+// returningCustomer é a cláusula RETURNING das alterações, que lê de volta as colunas de todos os campos de Customer
+func returningCustomer() clause.Returning {
+	var returning clause.Returning
+	for _, column := range customerColumns(nil, nil) {
+		returning.Columns = append(returning.Columns, clause.Column{Name: column})
+	}
+	return returning
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/schema"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+func TestCustomerColumns(t *testing.T) {
+	// Subteste para a leitura sem campos selecionados: são lidas as colunas de todos os campos de Customer.
+	t.Run("All Fields", func(t *testing.T) {
+		customerSchema, err := schema.Parse(&model.Customer{}, &sync.Map{}, schema.NamingStrategy{})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		var expected []string
+		for _, name := range customerSchema.DBNames {
+			expected = append(expected, "customers."+name)
+		}
+		assert.ElementsMatch(t, expected, customerColumns(nil, nil))
+	})
+
+	// Subteste para os campos selecionados, acrescidos das colunas obrigatórias e das colunas da ordenação.
+	t.Run("Selected Fields", func(t *testing.T) {
+		columns := customerColumns(model.FieldSet{"name", "id"}, model.Sort{{Field: "created_at", Desc: true}})
+
+		assert.Equal(t, []string{
+			"customers.id", "customers.version", "customers.updated_at", "customers.deleted_at",
+			"customers.name", "customers.created_at",
+		}, columns)
+	})
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/rsql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// likeEscaper escapa os caracteres especiais do operador LIKE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// applyCustomerFilter adiciona à consulta as cláusulas parametrizadas do filtro informado
+func applyCustomerFilter(query *gorm.DB, filter model.CustomerFilter) *gorm.DB {
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
+	if filter.Active != nil {
+		query = query.Where("active = ?", *filter.Active)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at > ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	if filter.UpdatedSince != nil {
+		query = query.Where("updated_at >= ?", *filter.UpdatedSince)
+	}
+	if filter.EmailDomain != "" {
+		query = query.Where("LOWER(email) LIKE ?", "%@"+likeEscaper.Replace(strings.ToLower(filter.EmailDomain)))
+	}
+	if filter.PhonePrefix != "" {
+		// O prefixo é comparado apenas com os dígitos do telefone, ignorando a formatação
+		query = query.Where(`regexp_replace(phone, '\D', '', 'g') LIKE ?`, likeEscaper.Replace(filter.PhonePrefix)+"%")
+	}
+	if filter.Expression != nil {
+		query = query.Where(compileExpression(filter.Expression))
+	}
+	return query
+}
+
+// compileExpression converte a expressão de filtro em uma condição parametrizada. Os campos já foram validados
+// contra model.FilterableCustomerFields e correspondem às colunas da tabela.
+func compileExpression(node rsql.Node) clause.Expression {
+	logical, ok := node.(*rsql.Logical)
+	if !ok {
+		return compileComparison(node.(*rsql.Comparison))
+	}
+
+	operands := make([]clause.Expression, len(logical.Operands))
+	for i, operand := range logical.Operands {
+		operands[i] = compileExpression(operand)
+	}
+	if logical.Operator == rsql.Or {
+		return clause.Or(operands...)
+	}
+	return clause.And(operands...)
+}
+
+// compileComparison converte a comparação em uma condição sobre a coluna do campo
+func compileComparison(c *rsql.Comparison) clause.Expression {
+	column := clause.Column{Name: c.Field}
+	value := c.Values[0]
+
+	switch c.Operator {
+	case rsql.NotEqual:
+		return clause.Neq{Column: column, Value: value}
+	case rsql.Less:
+		return clause.Lt{Column: column, Value: value}
+	case rsql.LessOrEqual:
+		return clause.Lte{Column: column, Value: value}
+	case rsql.Greater:
+		return clause.Gt{Column: column, Value: value}
+	case rsql.GreaterOrEqual:
+		return clause.Gte{Column: column, Value: value}
+	case rsql.In:
+		return clause.IN{Column: column, Values: c.Values}
+	case rsql.NotIn:
+		return clause.Not(clause.IN{Column: column, Values: c.Values})
+	case rsql.Like:
+		return clause.Like{Column: column, Value: likePattern(value.(string))}
+	case rsql.ILike:
+		return clause.Expr{SQL: "? ILIKE ?", Vars: []any{column, likePattern(value.(string))}}
+	default:
+		return clause.Eq{Column: column, Value: value}
+	}
+}
+
+// likePattern converte o padrão da expressão de filtro, em que * representa qualquer sequência de caracteres,
+// no padrão do operador LIKE, escapando os caracteres especiais
+func likePattern(pattern string) string {
+	return strings.ReplaceAll(likeEscaper.Replace(pattern), "*", "%")
+}
+
+// applyBulkSelection restringe a consulta aos clientes da lista de IDs ou, sem ela, aos que atendem ao filtro
+func applyBulkSelection(query *gorm.DB, selection model.BulkSelection) *gorm.DB {
+	if len(selection.IDs) > 0 {
+		return query.Where("id IN ?", selection.IDs)
+	}
+	return applyCustomerFilter(query, selection.Filter)
+}
+
+// applyChangedCondition restringe a consulta aos clientes em que alguma das alterações modifica o valor atual
+func applyChangedCondition(query *gorm.DB, changes model.CustomerChanges) *gorm.DB {
+	var conditions []string
+	var args []any
+	if changes.Active != nil {
+		conditions = append(conditions, "active <> ?")
+		args = append(args, *changes.Active)
+	}
+	return query.Where(strings.Join(conditions, " OR "), args...)
+}
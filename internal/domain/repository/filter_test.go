@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/rsql"
+)
+
+func TestCompileExpression(t *testing.T) {
+	// Subteste para cada operador e para o agrupamento das expressões, a partir da expressão de filtro.
+	t.Run("Operators And Grouping", func(t *testing.T) {
+		tests := []struct {
+			input string
+			sql   string
+			vars  []any
+		}{
+			{"id==1", `WHERE "id" = $1`, []any{int64(1)}},
+			{"id!=1", `WHERE "id" <> $1`, []any{int64(1)}},
+			{"id=lt=1", `WHERE "id" < $1`, []any{int64(1)}},
+			{"id<=1", `WHERE "id" <= $1`, []any{int64(1)}},
+			{"id=gt=1", `WHERE "id" > $1`, []any{int64(1)}},
+			{"id>=1", `WHERE "id" >= $1`, []any{int64(1)}},
+			{"created_at>2024-01-02", `WHERE "created_at" > $1`, []any{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+			{"id=in=(1,2,3)", `WHERE "id" IN ($1,$2,$3)`, []any{int64(1), int64(2), int64(3)}},
+			{"id=out=(1,2)", `WHERE "id" NOT IN ($1,$2)`, []any{int64(1), int64(2)}},
+			{"name=like=Sil*", `WHERE "name" LIKE $1`, []any{"Sil%"}},
+			// Os caracteres especiais do LIKE são escapados; somente * representa qualquer sequência
+			{`name=like='*50%_a\\b*'`, `WHERE "name" LIKE $1`, []any{`%50\%\_a\\b%`}},
+			{"email=ilike=*@CORP.com", `WHERE "email" ILIKE $1`, []any{"%@CORP.com"}},
+			// O texto null é um valor como outro qualquer, e não o NULL do SQL
+			{"phone==null", `WHERE "phone" = $1`, []any{"null"}},
+			{"active==true;(name==Ana,email==ana@example.com)", `WHERE ("active" = $1 AND ("name" = $2 OR "email" = $3))`, []any{true, "Ana", "ana@example.com"}},
+			{"name==Ana;email==ana@example.com,active==false", `WHERE (("name" = $1 AND "email" = $2) OR "active" = $3)`, []any{"Ana", "ana@example.com", false}},
+			{"(name==Ana;id==1),(active==false;(id==2,id==3))", `WHERE (("name" = $1 AND "id" = $2) OR ("active" = $3 AND ("id" = $4 OR "id" = $5)))`,
+				[]any{"Ana", int64(1), false, int64(2), int64(3)}},
+			{"name==Ana;email==ana@example.com;phone==123", `WHERE ("name" = $1 AND "email" = $2 AND "phone" = $3)`, []any{"Ana", "ana@example.com", "123"}},
+		}
+
+		for _, tt := range tests {
+			node, err := rsql.Parse(tt.input, model.FilterableCustomerFields)
+			if !assert.NoError(t, err, tt.input) {
+				continue
+			}
+
+			stmt := whereStatement(t, func(query *gorm.DB) *gorm.DB {
+				return query.Where(compileExpression(node))
+			})
+
+			assert.Equal(t, tt.sql+` AND "customers"."deleted_at" IS NULL`, stmt.SQL, tt.input)
+			assert.Equal(t, tt.vars, stmt.Vars, tt.input)
+		}
+	})
+
+	// Subteste para as comparações com NULL, que a expressão de filtro não produz, mas que a conversão
+	// compara com IS NULL e IS NOT NULL em vez de = e <>.
+	t.Run("Null Comparisons", func(t *testing.T) {
+		tests := []struct {
+			operator rsql.Operator
+			sql      string
+		}{
+			{rsql.Equal, `WHERE "phone" IS NULL`},
+			{rsql.NotEqual, `WHERE "phone" IS NOT NULL`},
+		}
+
+		for _, tt := range tests {
+			stmt := whereStatement(t, func(query *gorm.DB) *gorm.DB {
+				return query.Where(compileExpression(&rsql.Comparison{Field: "phone", Operator: tt.operator, Values: []any{nil}}))
+			})
+
+			assert.Equal(t, tt.sql+` AND "customers"."deleted_at" IS NULL`, stmt.SQL, tt.operator)
+			assert.Empty(t, stmt.Vars, tt.operator)
+		}
+	})
+}
+
+func TestApplyCustomerFilter(t *testing.T) {
+	active := true
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	expression, err := rsql.Parse("name=like=Ana*,id=in=(1,2)", model.FilterableCustomerFields)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		filter model.CustomerFilter
+		sql    string
+		vars   []any
+	}{
+		{"Empty", model.CustomerFilter{}, `WHERE "customers"."deleted_at" IS NULL`, nil},
+		{"Include Deleted", model.CustomerFilter{IncludeDeleted: true}, "", nil},
+		{"Active", model.CustomerFilter{Active: &active}, `WHERE active = $1 AND "customers"."deleted_at" IS NULL`, []any{true}},
+		{
+			"Dates",
+			model.CustomerFilter{CreatedAfter: &date, CreatedBefore: &date, UpdatedSince: &date},
+			`WHERE created_at > $1 AND created_at < $2 AND updated_at >= $3 AND "customers"."deleted_at" IS NULL`,
+			[]any{date, date, date},
+		},
+		// O domínio é comparado sem diferenciar maiúsculas e minúsculas, com os caracteres especiais do LIKE escapados
+		{"Email Domain", model.CustomerFilter{EmailDomain: "Corp_1.com"}, `WHERE LOWER(email) LIKE $1 AND "customers"."deleted_at" IS NULL`, []any{`%@corp\_1.com`}},
+		{
+			"Phone Prefix",
+			model.CustomerFilter{PhonePrefix: "11"},
+			`WHERE regexp_replace(phone, '\D', '', 'g') LIKE $1 AND "customers"."deleted_at" IS NULL`,
+			[]any{"11%"},
+		},
+		{
+			"Expression With Other Criteria",
+			model.CustomerFilter{Active: &active, Expression: expression, IncludeDeleted: true},
+			`WHERE active = $1 AND ("name" LIKE $2 OR "id" IN ($3,$4))`,
+			[]any{true, "Ana%", int64(1), int64(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		// Subteste para cada critério do filtro, combinados com AND.
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := whereStatement(t, func(query *gorm.DB) *gorm.DB {
+				return applyCustomerFilter(query, tt.filter)
+			})
+
+			assert.Equal(t, tt.sql, stmt.SQL)
+			if len(tt.vars) == 0 {
+				assert.Empty(t, stmt.Vars)
+			} else {
+				assert.Equal(t, tt.vars, stmt.Vars)
+			}
+		})
+	}
+}
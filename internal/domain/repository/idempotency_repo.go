@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+// IdempotencyRepository define as operações do repositório de chaves de idempotência
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *model.IdempotencyRecord) (bool, error)
+	GetByKey(ctx context.Context, key string) (*model.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, headers map[string]string, body []byte) error
+	Delete(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
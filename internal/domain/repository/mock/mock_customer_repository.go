@@ -42,18 +42,18 @@ func (m *MockCustomerRepository) EXPECT() *MockCustomerRepositoryMockRecorder {
 }
 
 // Count mocks base method.
-func (m *MockCustomerRepository) Count(ctx context.Context) (int64, error) {
+func (m *MockCustomerRepository) Count(ctx context.Context, filter model.CustomerFilter) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Count", ctx)
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Count indicates an expected call of Count.
-func (mr *MockCustomerRepositoryMockRecorder) Count(ctx any) *gomock.Call {
+func (mr *MockCustomerRepositoryMockRecorder) Count(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockCustomerRepository)(nil).Count), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockCustomerRepository)(nil).Count), ctx, filter)
 }
 
 // Create mocks base method.
@@ -70,63 +70,157 @@ func (mr *MockCustomerRepositoryMockRecorder) Create(ctx, customer any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomerRepository)(nil).Create), ctx, customer)
 }
 
+// CreateBatch mocks base method.
+func (m *MockCustomerRepository) CreateBatch(ctx context.Context, customers []*model.Customer, atomic bool) ([]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, customers, atomic)
+	ret0, _ := ret[0].([]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockCustomerRepositoryMockRecorder) CreateBatch(ctx, customers, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockCustomerRepository)(nil).CreateBatch), ctx, customers, atomic)
+}
+
 // Delete mocks base method.
-func (m *MockCustomerRepository) Delete(ctx context.Context, id uint) error {
+func (m *MockCustomerRepository) Delete(ctx context.Context, id, version uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockCustomerRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockCustomerRepositoryMockRecorder) Delete(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerRepository)(nil).Delete), ctx, id, version)
+}
+
+// DeleteMany mocks base method.
+func (m *MockCustomerRepository) DeleteMany(ctx context.Context, selection model.BulkSelection, dryRun bool) (*model.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMany", ctx, selection, dryRun)
+	ret0, _ := ret[0].(*model.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMany indicates an expected call of DeleteMany.
+func (mr *MockCustomerRepositoryMockRecorder) DeleteMany(ctx, selection, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMany", reflect.TypeOf((*MockCustomerRepository)(nil).DeleteMany), ctx, selection, dryRun)
+}
+
+// Export mocks base method.
+func (m *MockCustomerRepository) Export(ctx context.Context, filter model.CustomerFilter, sort model.Sort, fn func(*model.Customer) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, filter, sort, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockCustomerRepositoryMockRecorder) Export(ctx, filter, sort, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockCustomerRepository)(nil).Export), ctx, filter, sort, fn)
 }
 
 // GetAll mocks base method.
-func (m *MockCustomerRepository) GetAll(ctx context.Context) ([]*model.Customer, error) {
+func (m *MockCustomerRepository) GetAll(ctx context.Context, filter model.CustomerFilter, sort model.Sort, pagination model.Pagination) ([]*model.Customer, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret := m.ctrl.Call(m, "GetAll", ctx, filter, sort, pagination)
+	ret0, _ := ret[0].([]*model.Customer)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCustomerRepositoryMockRecorder) GetAll(ctx, filter, sort, pagination any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCustomerRepository)(nil).GetAll), ctx, filter, sort, pagination)
+}
+
+// GetAllAfter mocks base method.
+func (m *MockCustomerRepository) GetAllAfter(ctx context.Context, filter model.CustomerFilter, cursor model.Cursor, limit int) ([]*model.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllAfter", ctx, filter, cursor, limit)
 	ret0, _ := ret[0].([]*model.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockCustomerRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
+// GetAllAfter indicates an expected call of GetAllAfter.
+func (mr *MockCustomerRepositoryMockRecorder) GetAllAfter(ctx, filter, cursor, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCustomerRepository)(nil).GetAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAfter", reflect.TypeOf((*MockCustomerRepository)(nil).GetAllAfter), ctx, filter, cursor, limit)
 }
 
 // GetByID mocks base method.
-func (m *MockCustomerRepository) GetByID(ctx context.Context, id uint) (*model.Customer, error) {
+func (m *MockCustomerRepository) GetByID(ctx context.Context, id uint, fields ...string) (*model.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	varargs := []any{ctx, id}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetByID", varargs...)
 	ret0, _ := ret[0].(*model.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockCustomerRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+func (mr *MockCustomerRepositoryMockRecorder) GetByID(ctx, id any, fields ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCustomerRepository)(nil).GetByID), ctx, id)
+	varargs := append([]any{ctx, id}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCustomerRepository)(nil).GetByID), varargs...)
 }
 
-// GetByName mocks base method.
-func (m *MockCustomerRepository) GetByName(ctx context.Context, name string) ([]*model.Customer, error) {
+// Purge mocks base method.
+func (m *MockCustomerRepository) Purge(ctx context.Context, id uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByName", ctx, name)
-	ret0, _ := ret[0].([]*model.Customer)
+	ret := m.ctrl.Call(m, "Purge", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockCustomerRepositoryMockRecorder) Purge(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockCustomerRepository)(nil).Purge), ctx, id)
+}
+
+// Restore mocks base method.
+func (m *MockCustomerRepository) Restore(ctx context.Context, id uint) (*model.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(*model.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCustomerRepositoryMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCustomerRepository)(nil).Restore), ctx, id)
+}
+
+// Search mocks base method.
+func (m *MockCustomerRepository) Search(ctx context.Context, search model.CustomerSearch, sort model.Sort, pagination model.Pagination) ([]*model.CustomerMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, search, sort, pagination)
+	ret0, _ := ret[0].([]*model.CustomerMatch)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByName indicates an expected call of GetByName.
-func (mr *MockCustomerRepositoryMockRecorder) GetByName(ctx, name any) *gomock.Call {
+// Search indicates an expected call of Search.
+func (mr *MockCustomerRepositoryMockRecorder) Search(ctx, search, sort, pagination any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockCustomerRepository)(nil).GetByName), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockCustomerRepository)(nil).Search), ctx, search, sort, pagination)
 }
 
 // Update mocks base method.
@@ -142,3 +236,33 @@ func (mr *MockCustomerRepositoryMockRecorder) Update(ctx, customer any) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerRepository)(nil).Update), ctx, customer)
 }
+
+// UpdateInTx mocks base method.
+func (m *MockCustomerRepository) UpdateInTx(ctx context.Context, id uint, apply func(*model.Customer) (*model.Customer, error)) (*model.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInTx", ctx, id, apply)
+	ret0, _ := ret[0].(*model.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateInTx indicates an expected call of UpdateInTx.
+func (mr *MockCustomerRepositoryMockRecorder) UpdateInTx(ctx, id, apply any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInTx", reflect.TypeOf((*MockCustomerRepository)(nil).UpdateInTx), ctx, id, apply)
+}
+
+// UpdateMany mocks base method.
+func (m *MockCustomerRepository) UpdateMany(ctx context.Context, selection model.BulkSelection, changes model.CustomerChanges, dryRun bool) (*model.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMany", ctx, selection, changes, dryRun)
+	ret0, _ := ret[0].(*model.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMany indicates an expected call of UpdateMany.
+func (mr *MockCustomerRepositoryMockRecorder) UpdateMany(ctx, selection, changes, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMany", reflect.TypeOf((*MockCustomerRepository)(nil).UpdateMany), ctx, selection, changes, dryRun)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/repository/segment_repo.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/repository/segment_repo.go -destination=internal/domain/repository/mock/mock_segment_repository.go -package=mock_repository
+//
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/wandermaia/customer-api/internal/domain/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSegmentRepository is a mock of SegmentRepository interface.
+type MockSegmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSegmentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSegmentRepositoryMockRecorder is the mock recorder for MockSegmentRepository.
+type MockSegmentRepositoryMockRecorder struct {
+	mock *MockSegmentRepository
+}
+
+// NewMockSegmentRepository creates a new mock instance.
+func NewMockSegmentRepository(ctrl *gomock.Controller) *MockSegmentRepository {
+	mock := &MockSegmentRepository{ctrl: ctrl}
+	mock.recorder = &MockSegmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSegmentRepository) EXPECT() *MockSegmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSegmentRepository) Create(ctx context.Context, segment *model.Segment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, segment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSegmentRepositoryMockRecorder) Create(ctx, segment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSegmentRepository)(nil).Create), ctx, segment)
+}
+
+// Delete mocks base method.
+func (m *MockSegmentRepository) Delete(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSegmentRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSegmentRepository)(nil).Delete), ctx, id)
+}
+
+// GetAll mocks base method.
+func (m *MockSegmentRepository) GetAll(ctx context.Context) ([]*model.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*model.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockSegmentRepositoryMockRecorder) GetAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSegmentRepository)(nil).GetAll), ctx)
+}
+
+// GetByID mocks base method.
+func (m *MockSegmentRepository) GetByID(ctx context.Context, id uint) (*model.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*model.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockSegmentRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSegmentRepository)(nil).GetByID), ctx, id)
+}
+
+// Update mocks base method.
+func (m *MockSegmentRepository) Update(ctx context.Context, segment *model.Segment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, segment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSegmentRepositoryMockRecorder) Update(ctx, segment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSegmentRepository)(nil).Update), ctx, segment)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresIdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresIdempotencyRepository cria uma nova instância do repositório PostgreSQL de chaves de idempotência
+func NewPostgresIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &postgresIdempotencyRepository{
+		db: db,
+	}
+}
+
+// Reserve insere a chave de idempotência caso ela não exista ou esteja expirada, em uma única instrução.
+// Retorna false quando a chave já está em uso por outra requisição.
+func (r *postgresIdempotencyRepository) Reserve(ctx context.Context, record *model.IdempotencyRecord) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"fingerprint", "status_code", "content_type", "response_body", "created_at", "expires_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "idempotency_keys.expires_at <= ?", Vars: []any{record.CreatedAt}},
+		}},
+	}).Create(record)
+	if result.Error != nil {
+		return false, translateError(result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// GetByKey busca uma chave de idempotência
+func (r *postgresIdempotencyRepository) GetByKey(ctx context.Context, key string) (*model.IdempotencyRecord, error) {
+	var record model.IdempotencyRecord
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&record).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &record, nil
+}
+
+// Complete armazena a resposta da requisição associada à chave de idempotência, com os cabeçalhos devolvidos
+// nas repetições
+func (r *postgresIdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, headers map[string]string, body []byte) error {
+	return translateError(r.db.WithContext(ctx).
+		Model(&model.IdempotencyRecord{}).
+		Where("key = ?", key).
+		// A struct aplica o serializer JSON dos cabeçalhos; Select grava também os valores vazios
+		Select("status_code", "content_type", "response_headers", "response_body").
+		Updates(&model.IdempotencyRecord{StatusCode: statusCode, ContentType: contentType, ResponseHeaders: headers, ResponseBody: body}).Error)
+}
+
+// Delete remove uma chave de idempotência
+func (r *postgresIdempotencyRepository) Delete(ctx context.Context, key string) error {
+	return translateError(r.db.WithContext(ctx).Where("key = ?", key).Delete(&model.IdempotencyRecord{}).Error)
+}
+
+// DeleteExpired remove as chaves de idempotência expiradas e retorna a quantidade removida
+func (r *postgresIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&model.IdempotencyRecord{})
+	return result.RowsAffected, translateError(result.Error)
+}
@@ -2,12 +2,21 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
 	"github.com/wandermaia/customer-api/internal/domain/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// createBatchSize é a quantidade máxima de clientes inseridos em uma única instrução
+const createBatchSize = 100
+
+// errBatchRollback desfaz a transação de uma inserção em lote atômica em que algum cliente falhou
+var errBatchRollback = errors.New("inserção em lote desfeita")
+
 type postgresCustomerRepository struct {
 	db *gorm.DB
 }
@@ -21,49 +30,384 @@ func NewPostgresCustomerRepository(db *gorm.DB) CustomerRepository {
 
 // Create insere um novo cliente no banco de dados
 func (r *postgresCustomerRepository) Create(ctx context.Context, customer *model.Customer) error {
-	return r.db.WithContext(ctx).Create(customer).Error
+	return translateError(r.db.WithContext(ctx).Create(customer).Error)
 }
 
-// GetByID busca um cliente pelo ID
-func (r *postgresCustomerRepository) GetByID(ctx context.Context, id uint) (*model.Customer, error) {
+// CreateBatch insere os clientes em lotes de até createBatchSize registros e retorna o erro de cada cliente
+// na mesma posição da lista. Quando um lote falha, seus clientes são inseridos individualmente para identificar
+// os que não puderam ser criados. Com atomic, todos os clientes são inseridos em uma única transação, desfeita se
+// qualquer um falhar; nesse caso, nenhum cliente recebe ID. Falhas de acesso ao banco interrompem a operação e
+// são retornadas como erro geral.
+func (r *postgresCustomerRepository) CreateBatch(ctx context.Context, customers []*model.Customer, atomic bool) ([]error, error) {
+	errs := make([]error, len(customers))
+	failed := false
+
+	insert := func(db *gorm.DB) error {
+		for start := 0; start < len(customers); start += createBatchSize {
+			chunk := customers[start:min(start+createBatchSize, len(customers))]
+
+			// Cada lote é inserido em sua própria transação (ou savepoint, quando atomic)
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Create(&chunk).Error
+			})
+			if err == nil {
+				continue
+			}
+			if isUnavailable(err) {
+				return err
+			}
+
+			resetIDs(chunk)
+			for i, customer := range chunk {
+				err := db.Transaction(func(tx *gorm.DB) error {
+					return tx.Create(customer).Error
+				})
+				if err != nil {
+					if isUnavailable(err) {
+						return err
+					}
+					customer.ID = 0
+					errs[start+i] = translateError(err)
+					failed = true
+				}
+			}
+		}
+
+		if atomic && failed {
+			return errBatchRollback
+		}
+		return nil
+	}
+
+	var err error
+	if atomic {
+		err = r.db.WithContext(ctx).Transaction(insert)
+	} else {
+		err = insert(r.db.WithContext(ctx))
+	}
+
+	if atomic && err != nil {
+		resetIDs(customers)
+	}
+	if err != nil && !errors.Is(err, errBatchRollback) {
+		return nil, translateError(err)
+	}
+	return errs, nil
+}
+
+// resetIDs remove os IDs atribuídos a clientes cuja inserção foi desfeita
+func resetIDs(customers []*model.Customer) {
+	for _, customer := range customers {
+		customer.ID = 0
+	}
+}
+
+// GetByID busca um cliente pelo ID, lendo somente as colunas dos campos informados e as obrigatórias,
+// ou as de todos os campos quando nenhum campo é informado
+func (r *postgresCustomerRepository) GetByID(ctx context.Context, id uint, fields ...string) (*model.Customer, error) {
 	var customer model.Customer
-	if err := r.db.WithContext(ctx).First(&customer, id).Error; err != nil {
-		return nil, err
+	if err := selectFields(r.db.WithContext(ctx), fields, nil).First(&customer, id).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return &customer, nil
 }
 
-// GetAll retorna todos os clientes
-func (r *postgresCustomerRepository) GetAll(ctx context.Context) ([]*model.Customer, error) {
+// GetAll retorna uma página ordenada de clientes que atendem ao filtro e o total de registros filtrados
+func (r *postgresCustomerRepository) GetAll(ctx context.Context, filter model.CustomerFilter, sort model.Sort, pagination model.Pagination) ([]*model.Customer, int64, error) {
+	// A consulta filtrada é usada na contagem e na leitura da página; a nova sessão evita que uma altere a outra
+	query := applyCustomerFilter(r.db.WithContext(ctx).Model(&model.Customer{}), filter).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, translateError(err)
+	}
+
 	var customers []*model.Customer
-	if err := r.db.WithContext(ctx).Find(&customers).Error; err != nil {
-		return nil, err
+	if err := applySort(selectFields(query, filter.Fields, sort), sort).
+		Offset(pagination.Offset()).
+		Limit(pagination.PageSize).
+		Find(&customers).Error; err != nil {
+		return nil, 0, translateError(err)
 	}
-	return customers, nil
+	return customers, total, nil
 }
 
-// GetByName busca clientes pelo nome
-func (r *postgresCustomerRepository) GetByName(ctx context.Context, name string) ([]*model.Customer, error) {
+// GetAllAfter retorna até limit clientes que atendem ao filtro e são posteriores ao cursor
+// na ordenação do próprio cursor
+func (r *postgresCustomerRepository) GetAllAfter(ctx context.Context, filter model.CustomerFilter, cursor model.Cursor, limit int) ([]*model.Customer, error) {
+	query := applyKeyset(applyCustomerFilter(r.db.WithContext(ctx), filter), cursor)
+
 	var customers []*model.Customer
-	if err := r.db.WithContext(ctx).Where("name ILIKE ?", "%"+name+"%").Find(&customers).Error; err != nil {
-		return nil, err
+	if err := applySort(selectFields(query, filter.Fields, cursor.Sort), cursor.Sort).Limit(limit).Find(&customers).Error; err != nil {
+		return nil, translateError(err)
 	}
 	return customers, nil
 }
 
-// Update atualiza um cliente existente
+// Search busca os clientes que atendem a todos os termos informados e retorna, para cada um, o campo de maior
+// pontuação. Nome e endereço são comparados por semelhança de trigramas (word_similarity), sem diferenciar
+// maiúsculas, minúsculas e acentos, com pontuação de ao menos search.MinScore; email e telefone, por conterem o
+// termo, com pontuação 1. A busca textual compara a consulta com a coluna search_vector, pontuada por ts_rank, e
+// retorna o trecho com os termos destacados. Sem ordenação informada, os clientes são ordenados pela pontuação,
+// da maior para a menor, e pelo id. Somente os clientes da página informada são retornados.
+func (r *postgresCustomerRepository) Search(ctx context.Context, search model.CustomerSearch, sort model.Sort, pagination model.Pagination) ([]*model.CustomerMatch, error) {
+	fields, conditions := searchCriteria(search)
+
+	var rows []customerMatchRow
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// O limite do operador <% vale somente nesta transação; com o operador, a busca usa o índice trigram
+		threshold := strconv.FormatFloat(search.MinScore, 'f', -1, 64)
+		if err := tx.Exec("SELECT set_config('pg_trgm.word_similarity_threshold', ?, true)", threshold).Error; err != nil {
+			return err
+		}
+
+		columns, join := searchColumns(search, sort), bestMatchJoin(fields)
+		query := tx.Model(&model.Customer{}).
+			Select(columns.SQL, columns.Vars...).
+			Joins(join.SQL, join.Vars...)
+		for _, condition := range conditions {
+			query = query.Where(condition)
+		}
+		if len(sort) == 0 {
+			query = query.Order("best_match.score DESC, customers.id")
+		} else {
+			query = applySort(query, sort)
+		}
+		return query.Limit(pagination.PageSize).Offset(pagination.Offset()).Find(&rows).Error
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	matches := make([]*model.CustomerMatch, len(rows))
+	for i := range rows {
+		matches[i] = &model.CustomerMatch{
+			Customer:     &rows[i].Customer,
+			MatchedField: rows[i].MatchedField,
+			Score:        rows[i].Score,
+			Headline:     rows[i].Headline,
+		}
+	}
+	return matches, nil
+}
+
+// Export percorre, na ordenação informada, todos os clientes que atendem ao filtro com um cursor do banco de dados,
+// chamando fn para cada um sem carregar o resultado em memória. O erro retornado por fn interrompe a leitura e é
+// retornado sem alteração.
+func (r *postgresCustomerRepository) Export(ctx context.Context, filter model.CustomerFilter, sort model.Sort, fn func(customer *model.Customer) error) error {
+	query := applyCustomerFilter(r.db.WithContext(ctx).Model(&model.Customer{}), filter)
+
+	rows, err := applySort(selectFields(query, filter.Fields, sort), sort).Rows()
+	if err != nil {
+		return translateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var customer model.Customer
+		if err := r.db.ScanRows(rows, &customer); err != nil {
+			return translateError(err)
+		}
+		if err := fn(&customer); err != nil {
+			return err
+		}
+	}
+	return translateError(rows.Err())
+}
+
+// Update persiste os campos editáveis do cliente e incrementa sua versão. Quando customer.Version
+// é informada, a alteração só ocorre se a versão armazenada for a mesma, retornando ErrVersionConflict
+// caso contrário, ou ErrNotFound quando o cliente não existe. O cliente é preenchido com os valores persistidos.
 func (r *postgresCustomerRepository) Update(ctx context.Context, customer *model.Customer) error {
-	return r.db.WithContext(ctx).Save(customer).Error
+	query := r.db.WithContext(ctx).Model(customer).Clauses(returningCustomer())
+	if customer.Version != 0 {
+		query = query.Where("version = ?", customer.Version)
+	}
+
+	result := query.Updates(map[string]any{
+		"name":    customer.Name,
+		"email":   customer.Email,
+		"phone":   customer.Phone,
+		"address": customer.Address,
+		"active":  customer.Active,
+		"version": gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if customer.Version == 0 {
+			return ErrNotFound
+		}
+		return r.missingOrConflict(ctx, customer.ID)
+	}
+	return nil
+}
+
+// UpdateInTx bloqueia o cliente, obtém a nova versão do registro com a função apply e persiste
+// somente as colunas alteradas, incrementando a versão, tudo em uma única transação. A função apply não deve modificar
+// o registro recebido. Qualquer erro retornado por apply desfaz a transação e é repassado sem alterações.
+// Retorna ErrNotFound quando o cliente não existe.
+func (r *postgresCustomerRepository) UpdateInTx(ctx context.Context, id uint, apply func(current *model.Customer) (*model.Customer, error)) (*model.Customer, error) {
+	var updated *model.Customer
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current model.Customer
+		if err := selectFields(tx, nil, nil).Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, id).Error; err != nil {
+			return err
+		}
+
+		result, err := apply(&current)
+		if err != nil {
+			return err
+		}
+
+		if fields := model.ChangedFields(&current, result); len(fields) > 0 {
+			result.Version = current.Version + 1
+			if err := tx.Model(result).
+				Where("version = ?", current.Version).
+				Select(append(fields, "version")).
+				Updates(result).Error; err != nil {
+				return err
+			}
+		}
+
+		updated = result
+		return nil
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return updated, nil
 }
 
-// Delete remove um cliente pelo ID
-func (r *postgresCustomerRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&model.Customer{}, id).Error
+// Delete exclui logicamente um cliente pelo ID, preenchendo deleted_at. Quando version é informada, a exclusão só ocorre
+// se a versão armazenada for a mesma, retornando ErrVersionConflict caso contrário.
+// Retorna ErrNotFound quando o cliente não existe ou já foi excluído, com ou sem versão.
+func (r *postgresCustomerRepository) Delete(ctx context.Context, id uint, version uint) error {
+	query := r.db.WithContext(ctx)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&model.Customer{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if version == 0 {
+			return ErrNotFound
+		}
+		return r.missingOrConflict(ctx, id)
+	}
+	return nil
+}
+
+// missingOrConflict explica por que uma alteração condicionada à versão não alterou nenhum registro: retorna
+// ErrNotFound quando o cliente não existe ou foi excluído e ErrVersionConflict quando ele está em outra versão
+func (r *postgresCustomerRepository) missingOrConflict(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Select("id").Take(&model.Customer{}, id).Error
+	if err == nil {
+		return ErrVersionConflict
+	}
+	return translateError(err)
+}
+
+// UpdateMany aplica as alterações aos clientes selecionados, em uma única transação, e incrementa a versão
+// dos clientes alterados. Clientes cujos valores já correspondem às alterações são contados em Matched, mas
+// não são alterados. Com dryRun, somente as contagens são calculadas.
+func (r *postgresCustomerRepository) UpdateMany(ctx context.Context, selection model.BulkSelection, changes model.CustomerChanges, dryRun bool) (*model.BulkResult, error) {
+	result := &model.BulkResult{DryRun: dryRun}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		selected := func() *gorm.DB {
+			return applyBulkSelection(tx.Model(&model.Customer{}), selection)
+		}
+
+		if err := selected().Count(&result.Matched).Error; err != nil {
+			return err
+		}
+		if dryRun {
+			return applyChangedCondition(selected(), changes).Count(&result.Affected).Error
+		}
+
+		columns := map[string]any{"version": gorm.Expr("version + 1")}
+		if changes.Active != nil {
+			columns["active"] = *changes.Active
+		}
+		updated := applyChangedCondition(selected(), changes).Updates(columns)
+		result.Affected = updated.RowsAffected
+		return updated.Error
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return result, nil
+}
+
+// DeleteMany exclui logicamente os clientes selecionados em uma única transação.
+// Com dryRun, somente a quantidade de clientes que seriam excluídos é calculada.
+func (r *postgresCustomerRepository) DeleteMany(ctx context.Context, selection model.BulkSelection, dryRun bool) (*model.BulkResult, error) {
+	result := &model.BulkResult{DryRun: dryRun}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if dryRun {
+			err := applyBulkSelection(tx.Model(&model.Customer{}), selection).Count(&result.Matched).Error
+			result.Affected = result.Matched
+			return err
+		}
+
+		deleted := applyBulkSelection(tx, selection).Delete(&model.Customer{})
+		result.Matched = deleted.RowsAffected
+		result.Affected = deleted.RowsAffected
+		return deleted.Error
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return result, nil
+}
+
+// Restore desfaz a exclusão lógica de um cliente e incrementa sua versão. Retorna ErrNotFound quando
+// o cliente não existe e ErrNotDeleted quando ele não está excluído.
+func (r *postgresCustomerRepository) Restore(ctx context.Context, id uint) (*model.Customer, error) {
+	var customer model.Customer
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := selectFields(tx.Unscoped(), nil, nil).Clauses(clause.Locking{Strength: "UPDATE"}).First(&customer, id).Error; err != nil {
+			return err
+		}
+		if !customer.IsDeleted() {
+			return ErrNotDeleted
+		}
+
+		return tx.Unscoped().Model(&customer).Clauses(returningCustomer()).Updates(map[string]any{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &customer, nil
+}
+
+// Purge remove definitivamente um cliente pelo ID, esteja ele excluído logicamente ou não.
+// Retorna ErrNotFound quando o cliente não existe.
+func (r *postgresCustomerRepository) Purge(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Unscoped().Delete(&model.Customer{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
 
-// Count retorna o número total de clientes
-func (r *postgresCustomerRepository) Count(ctx context.Context) (int64, error) {
+// Count retorna o número de clientes que atendem ao filtro
+func (r *postgresCustomerRepository) Count(ctx context.Context, filter model.CustomerFilter) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&model.Customer{}).Count(&count).Error
-	return count, err
+	err := applyCustomerFilter(r.db.WithContext(ctx).Model(&model.Customer{}), filter).Count(&count).Error
+	return count, translateError(err)
 }
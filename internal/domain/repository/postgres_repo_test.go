@@ -0,0 +1,384 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/rsql"
+)
+
+// errDryRun indica que uma instrução chegou à conexão, o que não ocorre no modo DryRun
+var errDryRun = errors.New("nenhuma instrução é executada no modo DryRun")
+
+// dryRunConn é a conexão dos testes no modo DryRun do GORM, em que as instruções SQL são somente geradas.
+// Ela existe apenas para que o repositório possa abrir e encerrar suas transações.
+type dryRunConn struct{}
+
+func (*dryRunConn) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errDryRun
+}
+
+func (*dryRunConn) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, errDryRun
+}
+
+func (*dryRunConn) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errDryRun
+}
+
+func (*dryRunConn) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	return nil
+}
+
+func (*dryRunConn) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &dryRunTx{}, nil
+}
+
+// dryRunTx é a transação aberta pela dryRunConn
+type dryRunTx struct {
+	dryRunConn
+}
+
+func (*dryRunTx) Commit() error {
+	return nil
+}
+
+func (*dryRunTx) Rollback() error {
+	return nil
+}
+
+// statement é uma instrução SQL gerada pelo GORM e os valores de seus parâmetros
+type statement struct {
+	SQL  string
+	Vars []any
+}
+
+// openDryRun abre o banco de dados no modo DryRun e retorna as instruções geradas, na ordem em que são emitidas
+func openDryRun(t *testing.T) (*gorm.DB, *[]statement) {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &dryRunConn{}}), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("abrindo o banco de dados no modo DryRun: %v", err)
+	}
+
+	var statements []statement
+	record := func(db *gorm.DB) {
+		if db.Statement.SQL.Len() > 0 {
+			statements = append(statements, statement{SQL: db.Statement.SQL.String(), Vars: db.Statement.Vars})
+		}
+	}
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("gorm:create").Register("test:record", record),
+		callbacks.Query().After("gorm:query").Register("test:record", record),
+		callbacks.Update().After("gorm:update").Register("test:record", record),
+		callbacks.Delete().After("gorm:delete").Register("test:record", record),
+		callbacks.Row().After("gorm:row").Register("test:record", record),
+		callbacks.Raw().After("gorm:raw").Register("test:record", record),
+	} {
+		if err != nil {
+			t.Fatalf("registrando o callback: %v", err)
+		}
+	}
+	return db, &statements
+}
+
+// whereStatement monta a consulta de clientes com build e retorna, sem executá-la, a instrução a partir da cláusula
+// WHERE, com os valores de seus parâmetros
+func whereStatement(t *testing.T, build func(query *gorm.DB) *gorm.DB) statement {
+	db, statements := openDryRun(t)
+	build(db.Model(&model.Customer{})).Find(&[]*model.Customer{})
+	if len(*statements) != 1 {
+		t.Fatalf("esperada uma instrução, geradas %d", len(*statements))
+	}
+
+	stmt := (*statements)[0]
+	if i := strings.Index(stmt.SQL, " WHERE "); i >= 0 {
+		stmt.SQL = stmt.SQL[i+1:]
+	} else {
+		stmt.SQL = ""
+	}
+	return stmt
+}
+
+// insertColumns é a lista de colunas das inserções de clientes; cada cliente ocupa nove parâmetros, e active é o quinto
+const insertColumns = `INSERT INTO "customers" ("name","email","phone","address","active","created_at","updated_at","version","deleted_at")`
+
+// returningColumns são as colunas de todos os campos de Customer, lidas de volta nas alterações
+const returningColumns = `"customers"."id","customers"."version","customers"."updated_at","customers"."deleted_at",` +
+	`"customers"."name","customers"."email","customers"."phone","customers"."address","customers"."active","customers"."created_at"`
+
+// selectList retorna as colunas lidas pela consulta, entre SELECT e FROM
+func selectList(sql string) string {
+	list, _, _ := strings.Cut(strings.TrimPrefix(sql, "SELECT "), " FROM ")
+	return list
+}
+
+// activeValues retorna o valor de active gravado para cada cliente de uma inserção
+func activeValues(stmt statement) []any {
+	var values []any
+	for i := 4; i < len(stmt.Vars); i += 9 {
+		values = append(values, stmt.Vars[i])
+	}
+	return values
+}
+
+func TestPostgresCustomerRepository_Create(t *testing.T) {
+	ctx := context.Background()
+
+	// Subteste para o cliente inativo: a coluna active é gravada com false, e não omitida em favor do padrão da coluna.
+	t.Run("Inactive Customer", func(t *testing.T) {
+		db, statements := openDryRun(t)
+
+		err := NewPostgresCustomerRepository(db).Create(ctx, &model.Customer{Name: "Ana Souza", Email: "ana@example.com"})
+
+		assert.NoError(t, err)
+		if assert.Len(t, *statements, 1) {
+			stmt := (*statements)[0]
+			assert.Equal(t, insertColumns+` VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING "id"`, stmt.SQL)
+			assert.Equal(t, []any{false}, activeValues(stmt))
+		}
+	})
+
+	// Subteste para o cliente ativo.
+	t.Run("Active Customer", func(t *testing.T) {
+		db, statements := openDryRun(t)
+
+		err := NewPostgresCustomerRepository(db).Create(ctx, &model.Customer{Name: "Ana Souza", Email: "ana@example.com", Active: true})
+
+		assert.NoError(t, err)
+		if assert.Len(t, *statements, 1) {
+			assert.Equal(t, []any{true}, activeValues((*statements)[0]))
+		}
+	})
+}
+
+func TestPostgresCustomerRepository_CreateBatch(t *testing.T) {
+	ctx := context.Background()
+
+	for _, atomic := range []bool{true, false} {
+		// Subteste para um lote com clientes ativos e inativos, nos dois modos: cada cliente é gravado com o próprio
+		// valor de active.
+		t.Run(map[bool]string{true: "Mixed Active All Or Nothing", false: "Mixed Active Best Effort"}[atomic], func(t *testing.T) {
+			db, statements := openDryRun(t)
+			customers := []*model.Customer{
+				{Name: "Ana Souza", Email: "ana@example.com", Active: true},
+				{Name: "Carlos Lima", Email: "carlos@example.com", Active: false},
+				{Name: "Beatriz Rocha", Email: "beatriz@example.com", Active: false},
+				{Name: "Daniel Alves", Email: "daniel@example.com", Active: true},
+			}
+
+			_, err := NewPostgresCustomerRepository(db).CreateBatch(ctx, customers, atomic)
+
+			assert.NoError(t, err)
+			inserts := 0
+			for _, stmt := range *statements {
+				if strings.HasPrefix(stmt.SQL, insertColumns) {
+					inserts++
+					assert.Equal(t, []any{true, false, false, true}, activeValues(stmt))
+				}
+			}
+			assert.Equal(t, 1, inserts)
+		})
+	}
+
+	// Subteste para um lote somente com clientes inativos.
+	t.Run("All Inactive", func(t *testing.T) {
+		db, statements := openDryRun(t)
+		customers := []*model.Customer{
+			{Name: "Carlos Lima", Email: "carlos@example.com"},
+			{Name: "Beatriz Rocha", Email: "beatriz@example.com"},
+		}
+
+		_, err := NewPostgresCustomerRepository(db).CreateBatch(ctx, customers, false)
+
+		assert.NoError(t, err)
+		if assert.Len(t, *statements, 1) {
+			assert.Equal(t, []any{false, false}, activeValues((*statements)[0]))
+		}
+	})
+}
+
+func TestPostgresCustomerRepository_Update(t *testing.T) {
+	ctx := context.Background()
+
+	// Subteste para a alteração com versão: a versão é condição da alteração e, como nenhum registro é alterado no
+	// modo DryRun, a existência do cliente é consultada para distinguir o conflito de versão do cliente inexistente.
+	t.Run("With Version", func(t *testing.T) {
+		db, statements := openDryRun(t)
+		customer := &model.Customer{ID: 7, Name: "Ana Souza", Email: "ana@example.com", Version: 2}
+
+		_ = NewPostgresCustomerRepository(db).Update(ctx, customer)
+
+		if assert.Len(t, *statements, 2) {
+			update := (*statements)[0]
+			assert.Equal(t, `UPDATE "customers" SET "active"=$1,"address"=$2,"email"=$3,"name"=$4,"phone"=$5,"version"=version + 1,"updated_at"=$6 `+
+				`WHERE version = $7 AND "customers"."deleted_at" IS NULL AND "id" = $8 RETURNING `+returningColumns, update.SQL)
+			assert.Equal(t, []any{uint(2), uint(7)}, update.Vars[6:])
+
+			assert.Equal(t, statement{
+				SQL:  `SELECT "id" FROM "customers" WHERE "customers"."id" = $1 AND "customers"."deleted_at" IS NULL LIMIT $2`,
+				Vars: []any{uint(7), 1},
+			}, (*statements)[1])
+		}
+	})
+
+	// Subteste para a alteração sem versão: nenhum registro alterado significa que o cliente não existe.
+	t.Run("Without Version", func(t *testing.T) {
+		db, statements := openDryRun(t)
+		customer := &model.Customer{ID: 7, Name: "Ana Souza", Email: "ana@example.com"}
+
+		err := NewPostgresCustomerRepository(db).Update(ctx, customer)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		if assert.Len(t, *statements, 1) {
+			assert.NotContains(t, (*statements)[0].SQL, "WHERE version")
+		}
+	})
+}
+
+func TestPostgresCustomerRepository_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	// Subteste para a exclusão com versão: a versão é condição da exclusão lógica, e a consulta de existência
+	// desconsidera os clientes já excluídos, que são informados como inexistentes e não como conflito de versão.
+	t.Run("With Version", func(t *testing.T) {
+		db, statements := openDryRun(t)
+
+		_ = NewPostgresCustomerRepository(db).Delete(ctx, 7, 2)
+
+		if assert.Len(t, *statements, 2) {
+			deletion := (*statements)[0]
+			assert.Equal(t, `UPDATE "customers" SET "deleted_at"=$1 WHERE version = $2 AND "customers"."id" = $3 AND "customers"."deleted_at" IS NULL`, deletion.SQL)
+			assert.Equal(t, []any{uint(2), uint(7)}, deletion.Vars[1:])
+
+			assert.Equal(t, statement{
+				SQL:  `SELECT "id" FROM "customers" WHERE "customers"."id" = $1 AND "customers"."deleted_at" IS NULL LIMIT $2`,
+				Vars: []any{uint(7), 1},
+			}, (*statements)[1])
+		}
+	})
+
+	// Subteste para a exclusão sem versão.
+	t.Run("Without Version", func(t *testing.T) {
+		db, statements := openDryRun(t)
+
+		err := NewPostgresCustomerRepository(db).Delete(ctx, 7, 0)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		if assert.Len(t, *statements, 1) {
+			assert.Equal(t, `UPDATE "customers" SET "deleted_at"=$1 WHERE "customers"."id" = $2 AND "customers"."deleted_at" IS NULL`, (*statements)[0].SQL)
+		}
+	})
+}
+
+func TestPostgresCustomerRepository_ReadColumns(t *testing.T) {
+	ctx := context.Background()
+	pagination := model.Pagination{Page: 1, PageSize: 10}
+	cursor := model.Cursor{Sort: model.Sort{{Field: "id"}}, Values: []any{uint(5)}}
+
+	reads := map[string]func(repo CustomerRepository) error{
+		"GetByID": func(repo CustomerRepository) error {
+			_, err := repo.GetByID(ctx, 7)
+			return err
+		},
+		"GetAll": func(repo CustomerRepository) error {
+			_, _, err := repo.GetAll(ctx, model.CustomerFilter{}, nil, pagination)
+			return err
+		},
+		"GetAllAfter": func(repo CustomerRepository) error {
+			_, err := repo.GetAllAfter(ctx, model.CustomerFilter{}, cursor, 10)
+			return err
+		},
+		"Export": func(repo CustomerRepository) error {
+			return repo.Export(ctx, model.CustomerFilter{}, nil, func(*model.Customer) error { return nil })
+		},
+		"Search": func(repo CustomerRepository) error {
+			_, err := repo.Search(ctx, model.CustomerSearch{Query: "Ana", FullText: "Ana Paulista", MinScore: 0.3}, nil, pagination)
+			return err
+		},
+		"UpdateInTx": func(repo CustomerRepository) error {
+			_, err := repo.UpdateInTx(ctx, 7, func(current *model.Customer) (*model.Customer, error) {
+				return current, nil
+			})
+			return err
+		},
+		"Restore": func(repo CustomerRepository) error {
+			_, err := repo.Restore(ctx, 7)
+			return err
+		},
+	}
+
+	for name, read := range reads {
+		// Subteste para cada leitura sem campos selecionados: são lidas as colunas de todos os campos de Customer,
+		// e não a search_vector, que a busca textual usa somente nas condições e na pontuação.
+		t.Run(name, func(t *testing.T) {
+			db, statements := openDryRun(t)
+
+			_ = read(NewPostgresCustomerRepository(db))
+
+			selects := 0
+			for _, stmt := range *statements {
+				list := selectList(stmt.SQL)
+				if !strings.HasPrefix(stmt.SQL, "SELECT ") || strings.Contains(list, "count(") || strings.Contains(list, "set_config(") {
+					continue
+				}
+				selects++
+				assert.NotContains(t, list, "*", stmt.SQL)
+				assert.NotContains(t, list, "search_vector", stmt.SQL)
+				assert.Contains(t, list, "version", stmt.SQL)
+				assert.Contains(t, list, "created_at", stmt.SQL)
+			}
+			assert.Equal(t, 1, selects)
+		})
+	}
+}
+
+func TestPostgresCustomerRepository_BulkExpression(t *testing.T) {
+	ctx := context.Background()
+	expression, err := rsql.Parse("name=like=Silva*,id=in=(1,2)", model.FilterableCustomerFields)
+	assert.NoError(t, err)
+	selection := model.BulkSelection{Filter: model.CustomerFilter{Expression: expression}}
+
+	// Subteste para a atualização em massa: a contagem e a alteração são restritas pela expressão compilada.
+	t.Run("UpdateMany", func(t *testing.T) {
+		db, statements := openDryRun(t)
+		inactive := false
+
+		_, err := NewPostgresCustomerRepository(db).UpdateMany(ctx, selection, model.CustomerChanges{Active: &inactive}, false)
+
+		assert.NoError(t, err)
+		if assert.Len(t, *statements, 2) {
+			assert.Equal(t, statement{
+				SQL:  `SELECT count(*) FROM "customers" WHERE ("name" LIKE $1 OR "id" IN ($2,$3)) AND "customers"."deleted_at" IS NULL`,
+				Vars: []any{"Silva%", int64(1), int64(2)},
+			}, (*statements)[0])
+
+			update := (*statements)[1]
+			assert.Equal(t, `UPDATE "customers" SET "active"=$1,"version"=version + 1,"updated_at"=$2 `+
+				`WHERE ("name" LIKE $3 OR "id" IN ($4,$5)) AND active <> $6 AND "customers"."deleted_at" IS NULL`, update.SQL)
+			assert.Equal(t, []any{"Silva%", int64(1), int64(2), false}, update.Vars[2:])
+		}
+	})
+
+	// Subteste para a exclusão em massa.
+	t.Run("DeleteMany", func(t *testing.T) {
+		db, statements := openDryRun(t)
+
+		_, err := NewPostgresCustomerRepository(db).DeleteMany(ctx, selection, false)
+
+		assert.NoError(t, err)
+		if assert.Len(t, *statements, 1) {
+			deletion := (*statements)[0]
+			assert.Equal(t, `UPDATE "customers" SET "deleted_at"=$1 WHERE ("name" LIKE $2 OR "id" IN ($3,$4)) AND "customers"."deleted_at" IS NULL`, deletion.SQL)
+			assert.Equal(t, []any{"Silva%", int64(1), int64(2)}, deletion.Vars[1:])
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresSegmentRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresSegmentRepository cria uma nova instância do repositório PostgreSQL de segmentos
+func NewPostgresSegmentRepository(db *gorm.DB) SegmentRepository {
+	return &postgresSegmentRepository{
+		db: db,
+	}
+}
+
+// Create insere um novo segmento no banco de dados
+func (r *postgresSegmentRepository) Create(ctx context.Context, segment *model.Segment) error {
+	return translateError(r.db.WithContext(ctx).Create(segment).Error)
+}
+
+// GetByID busca um segmento pelo ID
+func (r *postgresSegmentRepository) GetByID(ctx context.Context, id uint) (*model.Segment, error) {
+	var segment model.Segment
+	if err := r.db.WithContext(ctx).First(&segment, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &segment, nil
+}
+
+// GetAll retorna todos os segmentos, ordenados pelo nome
+func (r *postgresSegmentRepository) GetAll(ctx context.Context) ([]*model.Segment, error) {
+	var segments []*model.Segment
+	if err := r.db.WithContext(ctx).Order("name").Order("id").Find(&segments).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return segments, nil
+}
+
+// Update altera o nome e o filtro do segmento, preenchendo os demais campos com os valores armazenados.
+// Retorna ErrNotFound quando o segmento não existe.
+func (r *postgresSegmentRepository) Update(ctx context.Context, segment *model.Segment) error {
+	result := r.db.WithContext(ctx).Model(segment).Clauses(clause.Returning{}).Updates(map[string]any{
+		"name":   segment.Name,
+		"filter": segment.Filter,
+	})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete remove um segmento pelo ID. Retorna ErrNotFound quando o segmento não existe.
+func (r *postgresSegmentRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.Segment{}, id)
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
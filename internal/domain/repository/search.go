@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// textSearchConfig é a configuração de busca textual da coluna search_vector, criada na migração
+// 0005_customers_full_text_search
+const textSearchConfig = "portuguese"
+
+// searchField é um campo comparado na busca: a condição que indica a correspondência e a pontuação do cliente
+// no campo, de 0 a 1
+type searchField struct {
+	name  string
+	match clause.Expr
+	score clause.Expr
+}
+
+// trigramField compara o termo com as palavras do campo por semelhança de trigramas, sem diferenciar maiúsculas,
+// minúsculas e acentos. O limite do operador <% é a pontuação mínima da busca.
+func trigramField(field, term string) searchField {
+	return searchField{
+		name:  field,
+		match: gorm.Expr("immutable_unaccent(?) <% immutable_unaccent("+field+")", term),
+		score: gorm.Expr("word_similarity(immutable_unaccent(?), immutable_unaccent("+field+"))", term),
+	}
+}
+
+// emailField procura o termo como parte do email, sem diferenciar maiúsculas e minúsculas
+func emailField(term string) searchField {
+	return searchField{
+		name:  model.SearchFieldEmail,
+		match: gorm.Expr("email ILIKE ?", "%"+likeEscaper.Replace(term)+"%"),
+		score: gorm.Expr("1"),
+	}
+}
+
+// phoneField procura os dígitos como parte dos dígitos do telefone, ignorando a formatação
+func phoneField(digits string) searchField {
+	return searchField{
+		name:  model.SearchFieldPhone,
+		match: gorm.Expr(`regexp_replace(phone, '\D', '', 'g') LIKE ?`, "%"+digits+"%"),
+		score: gorm.Expr("1"),
+	}
+}
+
+// fullTextQuery converte a consulta, na sintaxe de buscadores da web, em tsquery
+func fullTextQuery(term string) clause.Expr {
+	return gorm.Expr("websearch_to_tsquery('"+textSearchConfig+"', ?)", term)
+}
+
+// fullTextField indica se os termos da consulta textual estão no campo. A pontuação é a de ts_rank sobre
+// search_vector, que reúne nome e endereço, normalizada para o intervalo de 0 a 1 (rank / (rank + 1)).
+func fullTextField(field, term string) searchField {
+	query := fullTextQuery(term)
+	return searchField{
+		name:  field,
+		match: gorm.Expr("to_tsvector('"+textSearchConfig+"', coalesce("+field+", '')) @@ ?", query),
+		score: gorm.Expr("ts_rank(search_vector, ?, 32)", query),
+	}
+}
+
+// searchCriteria retorna os campos comparados na busca e as condições, combinadas com AND, que os clientes
+// encontrados devem atender. O termo geral corresponde a qualquer um dos seus campos; os demais, ao próprio campo.
+func searchCriteria(search model.CustomerSearch) ([]searchField, []clause.Expr) {
+	var fields []searchField
+	var conditions []clause.Expr
+
+	add := func(group ...searchField) {
+		fields = append(fields, group...)
+		conditions = append(conditions, anyMatch(group))
+	}
+
+	if search.Query != "" {
+		group := []searchField{
+			trigramField(model.SearchFieldName, search.Query),
+			emailField(search.Query),
+			trigramField(model.SearchFieldAddress, search.Query),
+		}
+		// Somente termos com a forma de um telefone, com dígitos suficientes e sem letras, são comparados com o telefone
+		if digits := model.PhoneDigits(search.Query); len(digits) >= model.MinPhoneSearchDigits && strings.IndexFunc(search.Query, unicode.IsLetter) < 0 {
+			group = append(group, phoneField(digits))
+		}
+		add(group...)
+	}
+	if search.Name != "" {
+		add(trigramField(model.SearchFieldName, search.Name))
+	}
+	if search.Email != "" {
+		add(emailField(search.Email))
+	}
+	if search.Phone != "" {
+		add(phoneField(search.Phone))
+	}
+	if search.FullText != "" {
+		// A condição usa a coluna search_vector, indexada. Quando os termos estão distribuídos entre o nome e o
+		// endereço, nenhum dos dois corresponde sozinho e o campo encontrado é o próprio search_vector.
+		combined := searchField{
+			name:  model.SearchFieldFullText,
+			match: gorm.Expr("search_vector @@ ?", fullTextQuery(search.FullText)),
+			score: gorm.Expr("ts_rank(search_vector, ?, 32)", fullTextQuery(search.FullText)),
+		}
+		fields = append(fields,
+			fullTextField(model.SearchFieldName, search.FullText),
+			fullTextField(model.SearchFieldAddress, search.FullText),
+			combined,
+		)
+		conditions = append(conditions, combined.match)
+	}
+
+	return fields, conditions
+}
+
+// anyMatch combina com OR as condições de correspondência dos campos
+func anyMatch(fields []searchField) clause.Expr {
+	parts := make([]string, len(fields))
+	var vars []any
+	for i, field := range fields {
+		parts[i] = "(" + field.match.SQL + ")"
+		vars = append(vars, field.match.Vars...)
+	}
+	return clause.Expr{SQL: strings.Join(parts, " OR "), Vars: vars}
+}
+
+// bestMatchJoin associa a cada cliente, na relação best_match, o campo com a maior pontuação entre os que
+// correspondem ao termo procurado. Nos empates, prevalece o campo que aparece primeiro na busca.
+func bestMatchJoin(fields []searchField) clause.Expr {
+	rows := make([]string, len(fields))
+	var vars []any
+	for i, field := range fields {
+		rows[i] = "(?::int, ?::text, CASE WHEN " + field.match.SQL + " THEN (" + field.score.SQL + ")::float8 END)"
+		vars = append(vars, i, field.name)
+		vars = append(vars, field.match.Vars...)
+		vars = append(vars, field.score.Vars...)
+	}
+
+	return clause.Expr{
+		SQL: "CROSS JOIN LATERAL (SELECT field, score FROM (VALUES " + strings.Join(rows, ", ") + ") AS s(position, field, score)" +
+			" WHERE score IS NOT NULL ORDER BY score DESC, position LIMIT 1) AS best_match",
+		Vars: vars,
+	}
+}
+
+// searchColumns retorna as colunas da busca: as do cliente, limitadas aos campos selecionados e sem a search_vector,
+// as da relação best_match e, na busca textual, o trecho do nome e do endereço com os termos destacados
+func searchColumns(search model.CustomerSearch, sort model.Sort) clause.Expr {
+	selected := strings.Join(customerColumns(search.Fields, sort), ", ")
+	columns := clause.Expr{SQL: selected + ", best_match.field AS matched_field, best_match.score AS score"}
+	if search.FullText != "" {
+		columns.SQL += ", ts_headline('" + textSearchConfig + "', concat_ws(' - ', name, NULLIF(address, '')), ?) AS headline"
+		columns.Vars = append(columns.Vars, fullTextQuery(search.FullText))
+	}
+	return columns
+}
+
+// customerMatchRow recebe o cliente encontrado na busca com as colunas da relação best_match
+type customerMatchRow struct {
+	model.Customer
+	MatchedField string
+	Score        float64
+	Headline     string
+}
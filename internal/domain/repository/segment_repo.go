@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+// SegmentRepository define as operações do repositório de segmentos de clientes
+type SegmentRepository interface {
+	Create(ctx context.Context, segment *model.Segment) error
+	GetByID(ctx context.Context, id uint) (*model.Segment, error)
+	GetAll(ctx context.Context) ([]*model.Segment, error)
+	Update(ctx context.Context, segment *model.Segment) error
+	Delete(ctx context.Context, id uint) error
+}
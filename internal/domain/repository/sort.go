@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// applySort adiciona à consulta a ordenação informada. Os campos já foram
+// validados contra model.SortableCustomerFields.
+func applySort(query *gorm.DB, sort model.Sort) *gorm.DB {
+	for _, field := range sort {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: field.Field}, Desc: field.Desc})
+	}
+	return query
+}
+
+// applyKeyset restringe a consulta aos registros posteriores ao cursor na sua ordenação.
+// Para a ordenação (a, -b, id) a condição gerada é:
+// a > ? OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?)
+func applyKeyset(query *gorm.DB, cursor model.Cursor) *gorm.DB {
+	var conditions []string
+	var args []any
+
+	for i, field := range cursor.Sort {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, cursor.Sort[j].Field+" = ?")
+			args = append(args, cursor.Values[j])
+		}
+
+		operator := " > ?"
+		if field.Desc {
+			operator = " < ?"
+		}
+		parts = append(parts, field.Field+operator)
+		args = append(args, cursor.Values[i])
+
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return query.Where(strings.Join(conditions, " OR "), args...)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+func TestApplyKeyset(t *testing.T) {
+	date := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor model.Cursor
+		sql    string
+		vars   []any
+	}{
+		{
+			"Ascending",
+			model.Cursor{Sort: model.Sort{{Field: "id"}}, Values: []any{uint(5)}},
+			`WHERE (id > $1)`,
+			[]any{uint(5)},
+		},
+		{
+			"Descending",
+			model.Cursor{Sort: model.Sort{{Field: "id", Desc: true}}, Values: []any{uint(5)}},
+			`WHERE (id < $1)`,
+			[]any{uint(5)},
+		},
+		// Cada campo é comparado na sua direção, com os campos anteriores iguais aos do cursor
+		{
+			"Mixed Directions",
+			model.Cursor{Sort: model.Sort{{Field: "name"}, {Field: "created_at", Desc: true}, {Field: "id"}}, Values: []any{"Ana", date, uint(5)}},
+			`WHERE ((name > $1) OR (name = $2 AND created_at < $3) OR (name = $4 AND created_at = $5 AND id > $6))`,
+			[]any{"Ana", "Ana", date, "Ana", date, uint(5)},
+		},
+		{
+			"Descending Then Ascending",
+			model.Cursor{Sort: model.Sort{{Field: "created_at", Desc: true}, {Field: "id"}}, Values: []any{date, uint(5)}},
+			`WHERE ((created_at < $1) OR (created_at = $2 AND id > $3))`,
+			[]any{date, date, uint(5)},
+		},
+	}
+
+	for _, tt := range tests {
+		// Subteste para cada direção da ordenação do cursor.
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := whereStatement(t, func(query *gorm.DB) *gorm.DB {
+				return applyKeyset(query, tt.cursor)
+			})
+
+			assert.Equal(t, tt.sql+` AND "customers"."deleted_at" IS NULL`, stmt.SQL)
+			assert.Equal(t, tt.vars, stmt.Vars)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+// MaxBatchSize é a quantidade máxima de clientes aceita em uma criação em lote
+const MaxBatchSize = 1000
+
+var (
+	ErrInvalidBatchMode = errors.New("modo de criação em lote inválido")
+	ErrEmptyBatch       = errors.New("o lote não contém clientes")
+	ErrBatchTooLarge    = errors.New("o lote excede a quantidade máxima de clientes")
+	ErrBatchAborted     = errors.New("cliente não criado devido à falha de outro item do lote")
+)
+
+// BatchMode define o comportamento da criação em lote quando algum cliente falha
+type BatchMode string
+
+const (
+	// BatchAllOrNothing cria todos os clientes ou nenhum deles
+	BatchAllOrNothing BatchMode = "all_or_nothing"
+	// BatchBestEffort cria os clientes válidos, mesmo que outros falhem
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// ParseBatchMode converte o modo informado, usando BatchAllOrNothing quando vazio
+func ParseBatchMode(value string) (BatchMode, error) {
+	switch mode := BatchMode(value); mode {
+	case "":
+		return BatchAllOrNothing, nil
+	case BatchAllOrNothing, BatchBestEffort:
+		return mode, nil
+	default:
+		return "", ErrInvalidBatchMode
+	}
+}
+
+// BatchResult contém o resultado da criação de um cliente do lote: o cliente criado ou o erro
+type BatchResult struct {
+	Customer *model.Customer
+	Err      error
+}
+
+// CreateCustomers valida e cria os clientes em lote, retornando o resultado de cada um na mesma posição da lista.
+// No modo BatchAllOrNothing, a falha de qualquer cliente impede a criação de todos e os demais recebem
+// ErrBatchAborted. Erros que impedem o processamento do lote como um todo são retornados como erro geral.
+func (s *customerService) CreateCustomers(ctx context.Context, customers []*model.Customer, mode BatchMode) ([]BatchResult, error) {
+	if len(customers) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(customers) > MaxBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+
+	results := make([]BatchResult, len(customers))
+	failed := false
+
+	// Valida os clientes e rejeita emails repetidos dentro do próprio lote
+	emails := make(map[string]bool, len(customers))
+	for i, customer := range customers {
+		if err := customer.Validate(); err != nil {
+			results[i].Err = invalidCustomer(err)
+			failed = true
+			continue
+		}
+
+		email := strings.ToLower(customer.Email)
+		if emails[email] {
+			results[i].Err = &AlreadyExistsError{Field: "email"}
+			failed = true
+			continue
+		}
+		emails[email] = true
+	}
+
+	if mode == BatchAllOrNothing && failed {
+		return abortBatch(results), nil
+	}
+
+	// Somente os clientes válidos são enviados ao repositório
+	var valid []*model.Customer
+	var positions []int
+	for i, customer := range customers {
+		if results[i].Err == nil {
+			valid = append(valid, customer)
+			positions = append(positions, i)
+		}
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	errs, err := s.repo.CreateBatch(ctx, valid, mode == BatchAllOrNothing)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+
+	for i, err := range errs {
+		position := positions[i]
+		if err == nil {
+			results[position].Customer = valid[i]
+			continue
+		}
+
+		failed = true
+		if conflict := alreadyExists(err); conflict != nil {
+			results[position].Err = conflict
+		} else {
+			results[position].Err = databaseError(err)
+		}
+	}
+
+	if mode == BatchAllOrNothing && failed {
+		return abortBatch(results), nil
+	}
+	return results, nil
+}
+
+// abortBatch marca como não criados os clientes que não falharam em um lote desfeito
+func abortBatch(results []BatchResult) []BatchResult {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i] = BatchResult{Err: ErrBatchAborted}
+		}
+	}
+	return results
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/repository"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+)
+
+// newBatch cria um lote de clientes válidos com emails distintos
+func newBatch(size int) []*model.Customer {
+	customers := make([]*model.Customer, size)
+	for i := range customers {
+		customers[i] = &model.Customer{Name: fmt.Sprintf("Customer %d", i), Email: fmt.Sprintf("customer%d@example.com", i)}
+	}
+	return customers
+}
+
+func TestParseBatchMode(t *testing.T) {
+	t.Run("Default Mode", func(t *testing.T) {
+		mode, err := service.ParseBatchMode("")
+
+		assert.NoError(t, err)
+		assert.Equal(t, service.BatchAllOrNothing, mode)
+	})
+
+	t.Run("Best Effort", func(t *testing.T) {
+		mode, err := service.ParseBatchMode("best_effort")
+
+		assert.NoError(t, err)
+		assert.Equal(t, service.BatchBestEffort, mode)
+	})
+
+	t.Run("Invalid Mode", func(t *testing.T) {
+		_, err := service.ParseBatchMode("partial")
+
+		assert.Equal(t, service.ErrInvalidBatchMode, err)
+	})
+}
+
+func TestCustomerService_CreateCustomers(t *testing.T) {
+	ctx, customerService, mockRepo := setup(t)
+
+	t.Run("Success", func(t *testing.T) {
+		customers := newBatch(3)
+
+		// Expectativa: CreateBatch recebe todos os clientes de forma atômica e atribui os IDs.
+		mockRepo.EXPECT().CreateBatch(ctx, customers, true).DoAndReturn(
+			func(_ any, batch []*model.Customer, _ bool) ([]error, error) {
+				for i, customer := range batch {
+					customer.ID = uint(i + 1)
+				}
+				return make([]error, len(batch)), nil
+			}).Times(1)
+
+		results, err := customerService.CreateCustomers(ctx, customers, service.BatchAllOrNothing)
+
+		assert.NoError(t, err)
+		if assert.Len(t, results, 3) {
+			for i, result := range results {
+				assert.NoError(t, result.Err)
+				assert.Equal(t, uint(i+1), result.Customer.ID)
+			}
+		}
+	})
+
+	t.Run("All Or Nothing Validation Error", func(t *testing.T) {
+		customers := newBatch(3)
+		customers[1].Email = "not-an-email"
+
+		// Expectativa: nenhum cliente é enviado ao repositório quando algum é inválido.
+		mockRepo.EXPECT().CreateBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		results, err := customerService.CreateCustomers(ctx, customers, service.BatchAllOrNothing)
+
+		assert.NoError(t, err)
+		assert.ErrorIs(t, results[0].Err, service.ErrBatchAborted)
+		assert.ErrorIs(t, results[1].Err, service.ErrInvalidCustomer)
+		assert.ErrorIs(t, results[2].Err, service.ErrBatchAborted)
+	})
+
+	t.Run("Best Effort Validation Error", func(t *testing.T) {
+		customers := newBatch(3)
+		customers[1].Name = ""
+
+		// Expectativa: somente os clientes válidos são enviados ao repositório, sem atomicidade.
+		mockRepo.EXPECT().CreateBatch(ctx, []*model.Customer{customers[0], customers[2]}, false).
+			Return([]error{nil, nil}, nil).Times(1)
+
+		results, err := customerService.CreateCustomers(ctx, customers, service.BatchBestEffort)
+
+		assert.NoError(t, err)
+		assert.Equal(t, customers[0], results[0].Customer)
+		assert.ErrorIs(t, results[1].Err, service.ErrInvalidCustomer)
+		assert.Equal(t, customers[2], results[2].Customer)
+	})
+
+	t.Run("Duplicate Email In Batch", func(t *testing.T) {
+		customers := newBatch(2)
+		customers[1].Email = "CUSTOMER0@example.com"
+
+		mockRepo.EXPECT().CreateBatch(ctx, []*model.Customer{customers[0]}, false).
+			Return([]error{nil}, nil).Times(1)
+
+		results, err := customerService.CreateCustomers(ctx, customers, service.BatchBestEffort)
+
+		// Verifica que a repetição do email, sem distinção de maiúsculas, é rejeitada.
+		assert.NoError(t, err)
+		assert.NoError(t, results[0].Err)
+		var conflict *service.AlreadyExistsError
+		if assert.ErrorAs(t, results[1].Err, &conflict) {
+			assert.Equal(t, "email", conflict.Field)
+		}
+	})
+
+	t.Run("Best Effort Repository Item Error", func(t *testing.T) {
+		customers := newBatch(2)
+
+		// Expectativa: o segundo cliente viola a unicidade do email no banco de dados.
+		mockRepo.EXPECT().CreateBatch(ctx, customers, false).
+			Return([]error{nil, &repository.DuplicateError{Field: "email"}}, nil).Times(1)
+
+		results, err := customerService.CreateCustomers(ctx, customers, service.BatchBestEffort)
+
+		assert.NoError(t, err)
+		assert.Equal(t, customers[0], results[0].Customer)
+		assert.ErrorIs(t, results[1].Err, service.ErrCustomerAlreadyExists)
+	})
+
+	t.Run("All Or Nothing Repository Item Error", func(t *testing.T) {
+		customers := newBatch(2)
+
+		// Expectativa: a inserção é desfeita porque o primeiro cliente falhou.
+		mockRepo.EXPECT().CreateBatch(ctx, customers, true).
+			Return([]error{&repository.DuplicateError{Field: "email"}, nil}, nil).Times(1)
+
+		results, err := customerService.CreateCustomers(ctx, customers, service.BatchAllOrNothing)
+
+		assert.NoError(t, err)
+		assert.ErrorIs(t, results[0].Err, service.ErrCustomerAlreadyExists)
+		assert.ErrorIs(t, results[1].Err, service.ErrBatchAborted)
+		assert.Nil(t, results[1].Customer)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		customers := newBatch(2)
+		repoErr := fmt.Errorf("%w: connection reset", repository.ErrUnavailable)
+
+		mockRepo.EXPECT().CreateBatch(ctx, customers, true).Return(nil, repoErr).Times(1)
+
+		results, err := customerService.CreateCustomers(ctx, customers, service.BatchAllOrNothing)
+
+		assert.ErrorIs(t, err, service.ErrDatabaseUnavailable)
+		assert.Nil(t, results)
+	})
+
+	t.Run("Empty Batch", func(t *testing.T) {
+		results, err := customerService.CreateCustomers(ctx, nil, service.BatchAllOrNothing)
+
+		assert.Equal(t, service.ErrEmptyBatch, err)
+		assert.Nil(t, results)
+	})
+
+	t.Run("Batch Too Large", func(t *testing.T) {
+		mockRepo.EXPECT().CreateBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		results, err := customerService.CreateCustomers(ctx, newBatch(service.MaxBatchSize+1), service.BatchBestEffort)
+
+		assert.Equal(t, service.ErrBatchTooLarge, err)
+		assert.Nil(t, results)
+	})
+}
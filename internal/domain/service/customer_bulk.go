@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+var (
+	ErrInvalidBulkSelection  = errors.New("informe a lista de IDs ou ao menos um critério de filtro, mas não ambos")
+	ErrBulkSelectionTooLarge = errors.New("a lista de IDs excede a quantidade máxima de clientes")
+	ErrNoChanges             = errors.New("nenhuma alteração informada")
+)
+
+// validateBulkSelection exige que os clientes sejam selecionados por IDs ou por filtro, evitando
+// que uma seleção vazia alcance todos os clientes
+func validateBulkSelection(selection model.BulkSelection) error {
+	if selection.IsEmpty() || (len(selection.IDs) > 0 && !selection.Filter.IsEmpty()) {
+		return ErrInvalidBulkSelection
+	}
+	if len(selection.IDs) > MaxBatchSize {
+		return ErrBulkSelectionTooLarge
+	}
+	return nil
+}
+
+// UpdateCustomers aplica as alterações a todos os clientes selecionados em uma única transação.
+// Com dryRun, nada é alterado e são retornadas as contagens de clientes selecionados e que seriam alterados.
+func (s *customerService) UpdateCustomers(ctx context.Context, selection model.BulkSelection, changes model.CustomerChanges, dryRun bool) (*model.BulkResult, error) {
+	if err := validateBulkSelection(selection); err != nil {
+		return nil, err
+	}
+	if changes.IsEmpty() {
+		return nil, ErrNoChanges
+	}
+
+	result, err := s.repo.UpdateMany(ctx, selection, changes, dryRun)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+
+	return result, nil
+}
+
+// DeleteCustomers exclui logicamente todos os clientes selecionados em uma única transação.
+// Com dryRun, nada é excluído e é retornada a quantidade de clientes que seriam excluídos.
+func (s *customerService) DeleteCustomers(ctx context.Context, selection model.BulkSelection, dryRun bool) (*model.BulkResult, error) {
+	if err := validateBulkSelection(selection); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.DeleteMany(ctx, selection, dryRun)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+
+	return result, nil
+}
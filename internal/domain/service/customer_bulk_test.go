@@ -0,0 +1,174 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+	"github.com/wandermaia/customer-api/internal/rsql"
+)
+
+// bulkExpression é a expressão de filtro das operações em massa por expressão
+var bulkExpression = &rsql.Logical{Operator: rsql.Or, Operands: []rsql.Node{
+	&rsql.Comparison{Field: "name", Operator: rsql.Like, Values: []any{"Silva*"}},
+	&rsql.Comparison{Field: "email", Operator: rsql.Like, Values: []any{"*@corp.com"}},
+}}
+
+func TestCustomerService_UpdateCustomers(t *testing.T) {
+	ctx, customerService, mockRepo := setup(t)
+	inactive := false
+	changes := model.CustomerChanges{Active: &inactive}
+
+	t.Run("Success By Filter", func(t *testing.T) {
+		selection := model.BulkSelection{Filter: model.CustomerFilter{EmailDomain: "partner.com"}}
+		expected := &model.BulkResult{Matched: 12, Affected: 10}
+
+		// Expectativa: UpdateMany recebe a seleção e as alterações sem simulação.
+		mockRepo.EXPECT().UpdateMany(ctx, selection, changes, false).Return(expected, nil).Times(1)
+
+		result, err := customerService.UpdateCustomers(ctx, selection, changes, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("Success By Expression", func(t *testing.T) {
+		selection := model.BulkSelection{Filter: model.CustomerFilter{Expression: bulkExpression}}
+		expected := &model.BulkResult{Matched: 4, Affected: 4}
+
+		// Expectativa: a expressão sozinha é um critério de filtro e chega ao repositório.
+		mockRepo.EXPECT().UpdateMany(ctx, selection, changes, false).Return(expected, nil).Times(1)
+
+		result, err := customerService.UpdateCustomers(ctx, selection, changes, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("IDs And Expression", func(t *testing.T) {
+		selection := model.BulkSelection{IDs: []uint{1}, Filter: model.CustomerFilter{Expression: bulkExpression}}
+
+		result, err := customerService.UpdateCustomers(ctx, selection, changes, false)
+
+		assert.Equal(t, service.ErrInvalidBulkSelection, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		selection := model.BulkSelection{IDs: []uint{1, 2, 3}}
+		expected := &model.BulkResult{Matched: 3, Affected: 2, DryRun: true}
+
+		mockRepo.EXPECT().UpdateMany(ctx, selection, changes, true).Return(expected, nil).Times(1)
+
+		result, err := customerService.UpdateCustomers(ctx, selection, changes, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("Empty Selection", func(t *testing.T) {
+		// Expectativa: uma seleção vazia nunca alcança o repositório.
+		mockRepo.EXPECT().UpdateMany(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		result, err := customerService.UpdateCustomers(ctx, model.BulkSelection{}, changes, false)
+
+		assert.Equal(t, service.ErrInvalidBulkSelection, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("IDs And Filter", func(t *testing.T) {
+		active := true
+		selection := model.BulkSelection{IDs: []uint{1}, Filter: model.CustomerFilter{Active: &active}}
+
+		result, err := customerService.UpdateCustomers(ctx, selection, changes, false)
+
+		assert.Equal(t, service.ErrInvalidBulkSelection, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Too Many IDs", func(t *testing.T) {
+		selection := model.BulkSelection{IDs: make([]uint, service.MaxBatchSize+1)}
+
+		result, err := customerService.UpdateCustomers(ctx, selection, changes, false)
+
+		assert.Equal(t, service.ErrBulkSelectionTooLarge, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("No Changes", func(t *testing.T) {
+		selection := model.BulkSelection{IDs: []uint{1}}
+
+		result, err := customerService.UpdateCustomers(ctx, selection, model.CustomerChanges{}, false)
+
+		assert.Equal(t, service.ErrNoChanges, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		selection := model.BulkSelection{IDs: []uint{1}}
+		repoErr := errors.New("database update failed")
+
+		mockRepo.EXPECT().UpdateMany(ctx, selection, changes, false).Return(nil, repoErr).Times(1)
+
+		result, err := customerService.UpdateCustomers(ctx, selection, changes, false)
+
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.ErrorIs(t, err, repoErr)
+		assert.Nil(t, result)
+	})
+}
+
+func TestCustomerService_DeleteCustomers(t *testing.T) {
+	ctx, customerService, mockRepo := setup(t)
+
+	t.Run("Success", func(t *testing.T) {
+		selection := model.BulkSelection{IDs: []uint{1, 2}}
+		expected := &model.BulkResult{Matched: 2, Affected: 2}
+
+		// Expectativa: DeleteMany recebe a seleção sem simulação.
+		mockRepo.EXPECT().DeleteMany(ctx, selection, false).Return(expected, nil).Times(1)
+
+		result, err := customerService.DeleteCustomers(ctx, selection, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("Success By Expression", func(t *testing.T) {
+		selection := model.BulkSelection{Filter: model.CustomerFilter{Expression: bulkExpression}}
+		expected := &model.BulkResult{Matched: 4, Affected: 4, DryRun: true}
+
+		mockRepo.EXPECT().DeleteMany(ctx, selection, true).Return(expected, nil).Times(1)
+
+		result, err := customerService.DeleteCustomers(ctx, selection, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("Empty Selection", func(t *testing.T) {
+		mockRepo.EXPECT().DeleteMany(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		// O filtro IncludeDeleted sozinho não seleciona clientes.
+		result, err := customerService.DeleteCustomers(ctx, model.BulkSelection{Filter: model.CustomerFilter{IncludeDeleted: true}}, true)
+
+		assert.Equal(t, service.ErrInvalidBulkSelection, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		selection := model.BulkSelection{IDs: []uint{1}}
+		repoErr := errors.New("database delete failed")
+
+		mockRepo.EXPECT().DeleteMany(ctx, selection, true).Return(nil, repoErr).Times(1)
+
+		result, err := customerService.DeleteCustomers(ctx, selection, true)
+
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+// ExportCustomers percorre todos os clientes que atendem ao filtro, na ordenação informada ou em DefaultSort,
+// chamando fn para cada um à medida que são lidos do banco de dados. O erro retornado por fn interrompe a
+// exportação e é retornado sem alteração; as falhas do banco de dados são convertidas como nas demais operações.
+func (s *customerService) ExportCustomers(ctx context.Context, filter model.CustomerFilter, sort model.Sort, fn func(customer *model.Customer) error) error {
+	var writeErr error
+	err := s.repo.Export(ctx, filter, sort.WithTiebreaker(), func(customer *model.Customer) error {
+		writeErr = fn(customer)
+		return writeErr
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+	if err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
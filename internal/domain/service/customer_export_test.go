@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/repository"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+)
+
+func TestCustomerService_ExportCustomers(t *testing.T) {
+	ctx, customerService, mockRepo := setup(t)
+	active := true
+	filter := model.CustomerFilter{Active: &active}
+
+	// exportAll simula o cursor do repositório, chamando fn para cada cliente.
+	exportAll := func(customers []*model.Customer) func(any, model.CustomerFilter, model.Sort, func(*model.Customer) error) error {
+		return func(_ any, _ model.CustomerFilter, _ model.Sort, fn func(*model.Customer) error) error {
+			for _, customer := range customers {
+				if err := fn(customer); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		customers := newBatch(3)
+
+		// Expectativa: sem ordenação informada, a exportação usa a ordenação padrão.
+		mockRepo.EXPECT().Export(ctx, filter, model.DefaultSort, gomock.Any()).DoAndReturn(exportAll(customers)).Times(1)
+
+		var exported []*model.Customer
+		err := customerService.ExportCustomers(ctx, filter, nil, func(customer *model.Customer) error {
+			exported = append(exported, customer)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, customers, exported)
+	})
+
+	t.Run("Sort With Tiebreaker", func(t *testing.T) {
+		sort := model.Sort{{Field: "name", Desc: true}}
+
+		mockRepo.EXPECT().Export(ctx, filter, sort.WithTiebreaker(), gomock.Any()).Return(nil).Times(1)
+
+		err := customerService.ExportCustomers(ctx, filter, sort, func(*model.Customer) error { return nil })
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Write Error", func(t *testing.T) {
+		writeErr := errors.New("broken pipe")
+
+		mockRepo.EXPECT().Export(ctx, filter, model.DefaultSort, gomock.Any()).DoAndReturn(exportAll(newBatch(3))).Times(1)
+
+		calls := 0
+		err := customerService.ExportCustomers(ctx, filter, nil, func(*model.Customer) error {
+			calls++
+			return writeErr
+		})
+
+		// Verifica que a falha na escrita interrompe a exportação e não é tratada como erro do banco.
+		assert.Equal(t, writeErr, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		repoErr := fmt.Errorf("%w: connection reset", repository.ErrUnavailable)
+
+		mockRepo.EXPECT().Export(ctx, filter, model.DefaultSort, gomock.Any()).Return(repoErr).Times(1)
+
+		err := customerService.ExportCustomers(ctx, filter, nil, func(*model.Customer) error { return nil })
+
+		assert.ErrorIs(t, err, service.ErrDatabaseUnavailable)
+	})
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+// ImportBatchSize é a quantidade de clientes válidos acumulados antes de cada inserção da importação
+const ImportBatchSize = 500
+
+var ErrImportRead = errors.New("não foi possível ler o arquivo de importação")
+
+// ImportRow é uma linha do arquivo de importação: o cliente lido ou o erro que impediu sua leitura
+type ImportRow struct {
+	Line     int
+	Customer *model.Customer
+	Err      error
+}
+
+// ImportSource fornece as linhas do arquivo de importação uma a uma, retornando io.EOF ao final.
+// Outros erros interrompem a importação.
+type ImportSource interface {
+	Next() (ImportRow, error)
+}
+
+// ImportRejection indica uma linha do arquivo que não foi importada e o motivo
+type ImportRejection struct {
+	Line int
+	Err  error
+}
+
+// ImportReport contém a quantidade de clientes importados e as linhas rejeitadas, na ordem do arquivo
+type ImportReport struct {
+	Imported int
+	Rejected []ImportRejection
+}
+
+// ImportCustomers lê as linhas do arquivo, valida cada cliente e insere os válidos em lotes de até
+// ImportBatchSize, sem atomicidade: linhas inválidas ou com email já cadastrado são rejeitadas e as demais importadas.
+// Se a leitura ou o banco de dados falharem, a importação é interrompida e os lotes já inseridos são mantidos.
+func (s *customerService) ImportCustomers(ctx context.Context, source ImportSource) (*ImportReport, error) {
+	report := &ImportReport{}
+	batch := make([]*model.Customer, 0, ImportBatchSize)
+	lines := make([]int, 0, ImportBatchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		errs, err := s.repo.CreateBatch(ctx, batch, false)
+		if err != nil {
+			return databaseError(err)
+		}
+
+		for i, err := range errs {
+			if err == nil {
+				report.Imported++
+				continue
+			}
+
+			if conflict := alreadyExists(err); conflict != nil {
+				err = conflict
+			} else {
+				err = databaseError(err)
+			}
+			report.Rejected = append(report.Rejected, ImportRejection{Line: lines[i], Err: err})
+		}
+
+		batch, lines = batch[:0], lines[:0]
+		return nil
+	}
+
+	for {
+		row, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrImportRead, err)
+		}
+
+		if row.Err == nil {
+			if err := row.Customer.Validate(); err != nil {
+				row.Err = invalidCustomer(err)
+			}
+		}
+		if row.Err != nil {
+			report.Rejected = append(report.Rejected, ImportRejection{Line: row.Line, Err: row.Err})
+			continue
+		}
+
+		batch = append(batch, row.Customer)
+		lines = append(lines, row.Line)
+		if len(batch) == ImportBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	// As rejeições do banco de dados são conhecidas somente após a inserção do lote
+	sort.SliceStable(report.Rejected, func(i, j int) bool {
+		return report.Rejected[i].Line < report.Rejected[j].Line
+	})
+	return report, nil
+}
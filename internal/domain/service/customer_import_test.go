@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/repository"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+)
+
+// rowsSource fornece as linhas de uma lista, retornando err ao final quando informado
+type rowsSource struct {
+	rows []service.ImportRow
+	err  error
+}
+
+func (s *rowsSource) Next() (service.ImportRow, error) {
+	if len(s.rows) == 0 {
+		if s.err != nil {
+			return service.ImportRow{}, s.err
+		}
+		return service.ImportRow{}, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+// newRows cria linhas de importação a partir da linha 2, após o cabeçalho
+func newRows(customers []*model.Customer) []service.ImportRow {
+	rows := make([]service.ImportRow, len(customers))
+	for i, customer := range customers {
+		rows[i] = service.ImportRow{Line: i + 2, Customer: customer}
+	}
+	return rows
+}
+
+func TestCustomerService_ImportCustomers(t *testing.T) {
+	ctx, customerService, mockRepo := setup(t)
+
+	t.Run("Success", func(t *testing.T) {
+		customers := newBatch(3)
+
+		// Expectativa: os clientes válidos são inseridos sem atomicidade.
+		mockRepo.EXPECT().CreateBatch(ctx, customers, false).Return(make([]error, 3), nil).Times(1)
+
+		report, err := customerService.ImportCustomers(ctx, &rowsSource{rows: newRows(customers)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Imported)
+		assert.Empty(t, report.Rejected)
+	})
+
+	t.Run("Rejected Rows", func(t *testing.T) {
+		customers := newBatch(4)
+		customers[1].Email = "not-an-email"
+		rows := newRows(customers)
+		rows[2] = service.ImportRow{Line: 4, Err: errors.New("linha malformada")}
+
+		// Expectativa: somente as linhas válidas chegam ao repositório e a última viola a unicidade do email.
+		mockRepo.EXPECT().CreateBatch(ctx, []*model.Customer{customers[0], customers[3]}, false).
+			Return([]error{nil, &repository.DuplicateError{Field: "email"}}, nil).Times(1)
+
+		report, err := customerService.ImportCustomers(ctx, &rowsSource{rows: rows})
+
+		// Verifica as linhas rejeitadas, na ordem do arquivo, e seus motivos.
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Imported)
+		if assert.Len(t, report.Rejected, 3) {
+			assert.Equal(t, 3, report.Rejected[0].Line)
+			assert.ErrorIs(t, report.Rejected[0].Err, service.ErrInvalidCustomer)
+			assert.Equal(t, 4, report.Rejected[1].Line)
+			assert.EqualError(t, report.Rejected[1].Err, "linha malformada")
+			assert.Equal(t, 5, report.Rejected[2].Line)
+			assert.ErrorIs(t, report.Rejected[2].Err, service.ErrCustomerAlreadyExists)
+		}
+	})
+
+	t.Run("Multiple Batches", func(t *testing.T) {
+		customers := newBatch(service.ImportBatchSize + 1)
+
+		// Expectativa: um lote completo e outro com o cliente restante.
+		gomock.InOrder(
+			mockRepo.EXPECT().CreateBatch(ctx, gomock.Len(service.ImportBatchSize), false).
+				Return(make([]error, service.ImportBatchSize), nil),
+			mockRepo.EXPECT().CreateBatch(ctx, []*model.Customer{customers[service.ImportBatchSize]}, false).
+				Return([]error{nil}, nil),
+		)
+
+		report, err := customerService.ImportCustomers(ctx, &rowsSource{rows: newRows(customers)})
+
+		assert.NoError(t, err)
+		assert.Equal(t, service.ImportBatchSize+1, report.Imported)
+	})
+
+	t.Run("Read Error", func(t *testing.T) {
+		mockRepo.EXPECT().CreateBatch(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		report, err := customerService.ImportCustomers(ctx, &rowsSource{rows: newRows(newBatch(1)), err: io.ErrUnexpectedEOF})
+
+		assert.ErrorIs(t, err, service.ErrImportRead)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Nil(t, report)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		repoErr := fmt.Errorf("%w: connection reset", repository.ErrUnavailable)
+
+		mockRepo.EXPECT().CreateBatch(ctx, gomock.Any(), false).Return(nil, repoErr).Times(1)
+
+		report, err := customerService.ImportCustomers(ctx, &rowsSource{rows: newRows(newBatch(2))})
+
+		assert.ErrorIs(t, err, service.ErrDatabaseUnavailable)
+		assert.Nil(t, report)
+	})
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// customerDocument é o documento JSON sobre o qual os patches são aplicados. Ele reproduz a representação do
+// cliente na API (CustomerResponse), e não o modelo armazenado: os caminhos do patch são os mesmos campos que o
+// cliente da API lê. Somente os campos de editableFields podem ser alterados.
+type customerDocument struct {
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Phone     string     `json:"phone"`
+	Address   string     `json:"address"`
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// editableFields são os campos do documento que um patch pode alterar, os mesmos aceitos na substituição do
+// cliente (UpdateCustomerRequest). Os demais, como id, created_at, updated_at e deleted_at, e campos que não fazem
+// parte da representação, como version, são gerenciados pelo servidor.
+var editableFields = map[string]bool{
+	"name":    true,
+	"email":   true,
+	"phone":   true,
+	"address": true,
+	"active":  true,
+}
+
+// nullableFields são os campos editáveis que podem ser removidos, com null no JSON Merge Patch ou a operação remove
+// do JSON Patch, voltando ao valor vazio. Os demais são obrigatórios ou, como active, não têm valor vazio que
+// represente a ausência, e removê-los é rejeitado em vez de gravar silenciosamente o valor zero.
+var nullableFields = map[string]bool{
+	"phone":   true,
+	"address": true,
+}
+
+func newCustomerDocument(customer *model.Customer) customerDocument {
+	document := customerDocument{
+		ID:        customer.ID,
+		Name:      customer.Name,
+		Email:     customer.Email,
+		Phone:     customer.Phone,
+		Address:   customer.Address,
+		Active:    customer.Active,
+		CreatedAt: customer.CreatedAt,
+		UpdatedAt: customer.UpdatedAt,
+	}
+	if customer.IsDeleted() {
+		document.DeletedAt = &customer.DeletedAt.Time
+	}
+	return document
+}
+
+// readOnlyFieldError indica que o patch altera um campo que não pode ser editado
+func readOnlyFieldError(path string) error {
+	return fmt.Errorf("%w: o campo %s não pode ser alterado", ErrInvalidPatch, path)
+}
+
+// requiredFieldError indica que o patch remove um campo que não pode ser removido
+func requiredFieldError(path string) error {
+	return fmt.Errorf("%w: o campo %s não pode ser removido nem receber null", ErrInvalidPatch, path)
+}
+
+// checkMergePatch rejeita os documentos JSON Merge Patch que não são um objeto, que alteram campos que não
+// podem ser editados ou que removem, com null, campos que não podem ser removidos
+func checkMergePatch(patch []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil || fields == nil {
+		return ErrInvalidPatch
+	}
+	for field, value := range fields {
+		if !editableFields[field] {
+			return readOnlyFieldError("/" + field)
+		}
+		if string(value) == "null" && !nullableFields[field] {
+			return requiredFieldError("/" + field)
+		}
+	}
+	return nil
+}
+
+// checkJSONPatch rejeita as operações JSON Patch que alteram campos que não podem ser editados ou que
+// removem, inclusive com o valor null, campos que não podem ser removidos
+func checkJSONPatch(operations jsonpatch.Patch) error {
+	for _, operation := range operations {
+		var removed string
+		switch operation.Kind() {
+		case "test":
+			// test somente lê o documento e pode comparar qualquer campo
+			continue
+		case "move":
+			// move também remove o campo de origem, que precisa ser editável e poder ser removido
+			from, err := operation.From()
+			if err != nil {
+				return ErrInvalidPatch
+			}
+			if !isEditablePath(from) {
+				return readOnlyFieldError(from)
+			}
+			removed = from
+		}
+
+		// O destino de add, replace, copy e move e o campo de remove precisam ser editáveis; a origem de copy
+		// somente é lida e pode ser qualquer campo
+		path, err := operation.Path()
+		if err != nil {
+			return ErrInvalidPatch
+		}
+		if !isEditablePath(path) {
+			return readOnlyFieldError(path)
+		}
+		switch operation.Kind() {
+		case "remove":
+			removed = path
+		case "add", "replace":
+			// Um valor null também apagaria o campo
+			if value, err := operation.ValueInterface(); err == nil && value == nil {
+				removed = path
+			}
+		}
+
+		if removed != "" && !nullableFields[removed[1:]] {
+			return requiredFieldError(removed)
+		}
+	}
+	return nil
+}
+
+// isEditablePath indica se o caminho JSON Pointer aponta para um campo editável do documento
+func isEditablePath(path string) bool {
+	return len(path) > 1 && path[0] == '/' && editableFields[path[1:]]
+}
+
+// patchedCustomer converte o documento resultante do patch no cliente atual com os campos editáveis alterados,
+// validando o resultado
+func patchedCustomer(current *model.Customer, patched []byte) (*model.Customer, error) {
+	var document customerDocument
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, ErrInvalidPatch
+	}
+
+	updated := *current
+	updated.Name = document.Name
+	updated.Email = document.Email
+	updated.Phone = document.Phone
+	updated.Address = document.Address
+	updated.Active = document.Active
+
+	if err := updated.Validate(); err != nil {
+		return nil, invalidCustomer(err)
+	}
+	return &updated, nil
+}
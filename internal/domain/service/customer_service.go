@@ -2,27 +2,131 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/wandermaia/customer-api/internal/domain/model"
 	"github.com/wandermaia/customer-api/internal/domain/repository"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/go-playground/validator/v10"
 )
 
 var (
-	ErrInvalidCustomer   = errors.New("dados do cliente inválidos")
-	ErrCustomerNotFound  = errors.New("cliente não encontrado")
-	ErrDatabaseOperation = errors.New("erro na operação do banco de dados")
+	ErrInvalidCustomer       = errors.New("dados do cliente inválidos")
+	ErrCustomerNotFound      = errors.New("cliente não encontrado")
+	ErrDatabaseOperation     = errors.New("erro na operação do banco de dados")
+	ErrDatabaseUnavailable   = errors.New("banco de dados indisponível")
+	ErrInvalidCursor         = errors.New("cursor de paginação inválido")
+	ErrInvalidPatch          = errors.New("documento de patch inválido")
+	ErrPatchTestFailed       = errors.New("operação test do patch não foi satisfeita")
+	ErrVersionConflict       = errors.New("o cliente foi alterado desde a versão informada")
+	ErrCustomerAlreadyExists = errors.New("já existe um cliente com os mesmos dados")
+	ErrCustomerNotDeleted    = errors.New("o cliente não está excluído")
+	ErrInvalidMinScore       = errors.New("a pontuação mínima deve ser maior que 0 e no máximo 1")
+	ErrEmptySearch           = errors.New("nenhum termo de busca informado")
+)
+
+// AlreadyExistsError indica o campo cujo valor já pertence a outro cliente.
+// É equivalente a ErrCustomerAlreadyExists em comparações com errors.Is.
+type AlreadyExistsError struct {
+	Field string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return ErrCustomerAlreadyExists.Error()
+}
+
+func (e *AlreadyExistsError) Unwrap() error {
+	return ErrCustomerAlreadyExists
+}
+
+// ValidationError contém os campos do cliente que não passaram na validação.
+// É equivalente a ErrInvalidCustomer em comparações com errors.Is.
+type ValidationError struct {
+	Errors validator.ValidationErrors
+}
+
+func (e *ValidationError) Error() string {
+	return ErrInvalidCustomer.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidCustomer
+}
+
+// invalidCustomer converte o erro de validação do cliente em ValidationError
+func invalidCustomer(err error) error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return &ValidationError{Errors: validationErrors}
+	}
+	return ErrInvalidCustomer
+}
+
+// alreadyExists converte a violação de unicidade do repositório em AlreadyExistsError,
+// retornando nil para os demais erros
+func alreadyExists(err error) *AlreadyExistsError {
+	var duplicate *repository.DuplicateError
+	if errors.As(err, &duplicate) {
+		return &AlreadyExistsError{Field: duplicate.Field}
+	}
+	return nil
+}
+
+// databaseError converte a falha do repositório em ErrDatabaseUnavailable, quando o banco de dados
+// não pôde ser acessado, ou em ErrDatabaseOperation, mantendo a causa original para os logs.
+// O cancelamento da requisição (context.Canceled) não é uma falha do banco de dados e é retornado sem alteração.
+func databaseError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	if errors.Is(err, repository.ErrUnavailable) {
+		return fmt.Errorf("%w: %w", ErrDatabaseUnavailable, err)
+	}
+	return fmt.Errorf("%w: %w", ErrDatabaseOperation, err)
+}
+
+// lookupError converte o erro da busca de um cliente em ErrCustomerNotFound,
+// quando o cliente não existe, ou em uma falha do banco de dados
+func lookupError(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrCustomerNotFound
+	}
+	return databaseError(err)
+}
+
+// DefaultMinScore é a pontuação mínima de semelhança usada na busca quando nenhuma é informada
+const DefaultMinScore = 0.3
+
+// PatchType identifica o formato do documento de patch
+type PatchType int
+
+const (
+	// MergePatch representa um documento JSON Merge Patch (RFC 7396)
+	MergePatch PatchType = iota
+	// JSONPatch representa um documento JSON Patch (RFC 6902)
+	JSONPatch
 )
 
 // CustomerService define as operações de serviço para clientes
 type CustomerService interface {
 	CreateCustomer(ctx context.Context, customer *model.Customer) error
-	GetCustomerByID(ctx context.Context, id uint) (*model.Customer, error)
-	GetAllCustomers(ctx context.Context) ([]*model.Customer, error)
-	GetCustomersByName(ctx context.Context, name string) ([]*model.Customer, error)
-	UpdateCustomer(ctx context.Context, customer *model.Customer) error
-	DeleteCustomer(ctx context.Context, id uint) error
-	CountCustomers(ctx context.Context) (int64, error)
+	CreateCustomers(ctx context.Context, customers []*model.Customer, mode BatchMode) ([]BatchResult, error)
+	ImportCustomers(ctx context.Context, source ImportSource) (*ImportReport, error)
+	GetCustomerByID(ctx context.Context, id uint, fields model.FieldSet) (*model.Customer, error)
+	GetAllCustomers(ctx context.Context, filter model.CustomerFilter, sort model.Sort, pagination model.Pagination) (*model.CustomerPage, error)
+	SearchCustomers(ctx context.Context, search model.CustomerSearch, sort model.Sort, pagination model.Pagination) ([]*model.CustomerMatch, error)
+	ExportCustomers(ctx context.Context, filter model.CustomerFilter, sort model.Sort, fn func(customer *model.Customer) error) error
+	UpdateCustomer(ctx context.Context, customer *model.Customer, expectedVersion uint) error
+	PatchCustomer(ctx context.Context, id uint, expectedVersion uint, patchType PatchType, patch []byte) (*model.Customer, error)
+	DeleteCustomer(ctx context.Context, id uint, expectedVersion uint) error
+	UpdateCustomers(ctx context.Context, selection model.BulkSelection, changes model.CustomerChanges, dryRun bool) (*model.BulkResult, error)
+	DeleteCustomers(ctx context.Context, selection model.BulkSelection, dryRun bool) (*model.BulkResult, error)
+	RestoreCustomer(ctx context.Context, id uint) (*model.Customer, error)
+	PurgeCustomer(ctx context.Context, id uint) error
+	CountCustomers(ctx context.Context, filter model.CustomerFilter) (int64, error)
 }
 
 type customerService struct {
@@ -39,75 +143,251 @@ func NewCustomerService(repo repository.CustomerRepository) CustomerService {
 // CreateCustomer cria um novo cliente
 func (s *customerService) CreateCustomer(ctx context.Context, customer *model.Customer) error {
 	if err := customer.Validate(); err != nil {
-		return ErrInvalidCustomer
+		return invalidCustomer(err)
 	}
 
 	if err := s.repo.Create(ctx, customer); err != nil {
-		return ErrDatabaseOperation
+		if conflict := alreadyExists(err); conflict != nil {
+			return conflict
+		}
+		return databaseError(err)
 	}
 
 	return nil
 }
 
-// GetCustomerByID busca um cliente pelo ID
-func (s *customerService) GetCustomerByID(ctx context.Context, id uint) (*model.Customer, error) {
-	customer, err := s.repo.GetByID(ctx, id)
+// GetCustomerByID busca um cliente pelo ID. Quando fields é informado, somente os campos selecionados,
+// além dos usados nos cabeçalhos de cache, são lidos.
+func (s *customerService) GetCustomerByID(ctx context.Context, id uint, fields model.FieldSet) (*model.Customer, error) {
+	customer, err := s.repo.GetByID(ctx, id, fields...)
 	if err != nil {
-		return nil, ErrCustomerNotFound
+		return nil, lookupError(err)
 	}
 
 	return customer, nil
 }
 
-// GetAllCustomers retorna todos os clientes
-func (s *customerService) GetAllCustomers(ctx context.Context) ([]*model.Customer, error) {
-	return s.repo.GetAll(ctx)
+// GetAllCustomers retorna uma página ordenada de clientes que atendem ao filtro.
+// Quando um cursor é informado, a página é obtida por keyset a partir dele, na ordenação do cursor.
+func (s *customerService) GetAllCustomers(ctx context.Context, filter model.CustomerFilter, sort model.Sort, pagination model.Pagination) (*model.CustomerPage, error) {
+	pagination = pagination.Normalize()
+
+	if pagination.Cursor != "" {
+		return s.getCustomersAfterCursor(ctx, filter, sort, pagination)
+	}
+
+	sort = sort.WithTiebreaker()
+	customers, total, err := s.repo.GetAll(ctx, filter, sort, pagination)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+
+	page := &model.CustomerPage{
+		Items:    customers,
+		Total:    &total,
+		Page:     pagination.Page,
+		PageSize: pagination.PageSize,
+	}
+	if len(customers) > 0 && int64(pagination.Offset()+len(customers)) < total {
+		page.NextCursor = model.CursorFor(customers[len(customers)-1], sort).Encode()
+	}
+
+	return page, nil
 }
 
-// GetCustomersByName busca clientes pelo nome
-func (s *customerService) GetCustomersByName(ctx context.Context, name string) ([]*model.Customer, error) {
-	if name == "" {
-		return nil, ErrInvalidCustomer
+// getCustomersAfterCursor retorna a página de clientes posterior ao cursor informado.
+// Se uma ordenação for informada, ela deve ser a mesma usada para gerar o cursor.
+func (s *customerService) getCustomersAfterCursor(ctx context.Context, filter model.CustomerFilter, sort model.Sort, pagination model.Pagination) (*model.CustomerPage, error) {
+	cursor, err := model.DecodeCursor(pagination.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if len(sort) > 0 && sort.WithTiebreaker().String() != cursor.Sort.String() {
+		return nil, ErrInvalidCursor
 	}
 
-	return s.repo.GetByName(ctx, name)
+	// Busca um registro a mais para saber se existe uma próxima página
+	customers, err := s.repo.GetAllAfter(ctx, filter, cursor, pagination.PageSize+1)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+
+	page := &model.CustomerPage{PageSize: pagination.PageSize}
+	if len(customers) > pagination.PageSize {
+		customers = customers[:pagination.PageSize]
+		page.NextCursor = model.CursorFor(customers[len(customers)-1], cursor.Sort).Encode()
+	}
+	page.Items = customers
+
+	return page, nil
+}
+
+// SearchCustomers busca os clientes que atendem a todos os termos informados, indicando em cada resultado o campo de
+// maior pontuação. Nome e endereço são comparados por semelhança, sem diferenciar maiúsculas, minúsculas e acentos,
+// com pontuação de ao menos search.MinScore, maior que 0 e no máximo 1. Sem ordenação informada, os clientes são
+// ordenados pela pontuação; caso contrário, pela ordenação informada com o id como desempate. Somente a página
+// informada é retornada, com o mesmo limite de registros por página da listagem.
+func (s *customerService) SearchCustomers(ctx context.Context, search model.CustomerSearch, sort model.Sort, pagination model.Pagination) ([]*model.CustomerMatch, error) {
+	if search.IsEmpty() {
+		return nil, ErrEmptySearch
+	}
+	// Com pontuação mínima 0, todo nome e endereço seria semelhante ao termo e a busca retornaria todos os clientes
+	if !(search.MinScore > 0 && search.MinScore <= 1) {
+		return nil, ErrInvalidMinScore
+	}
+
+	if len(sort) > 0 {
+		sort = sort.WithTiebreaker()
+	}
+	matches, err := s.repo.Search(ctx, search, sort, pagination.Normalize())
+	if err != nil {
+		return nil, databaseError(err)
+	}
+
+	return matches, nil
 }
 
-// UpdateCustomer atualiza um cliente existente
-func (s *customerService) UpdateCustomer(ctx context.Context, customer *model.Customer) error {
+// UpdateCustomer atualiza um cliente existente. Quando expectedVersion é diferente de zero,
+// a alteração só é aplicada se o cliente ainda estiver nessa versão.
+func (s *customerService) UpdateCustomer(ctx context.Context, customer *model.Customer, expectedVersion uint) error {
 	if err := customer.Validate(); err != nil {
-		return ErrInvalidCustomer
+		return invalidCustomer(err)
 	}
 
 	// Verifica se o cliente existe
 	_, err := s.repo.GetByID(ctx, customer.ID)
 	if err != nil {
-		return ErrCustomerNotFound
+		return lookupError(err)
 	}
 
+	customer.Version = expectedVersion
 	if err := s.repo.Update(ctx, customer); err != nil {
-		return ErrDatabaseOperation
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return ErrVersionConflict
+		}
+		if conflict := alreadyExists(err); conflict != nil {
+			return conflict
+		}
+		return lookupError(err)
 	}
 
 	return nil
 }
 
-// DeleteCustomer remove um cliente pelo ID
-func (s *customerService) DeleteCustomer(ctx context.Context, id uint) error {
+// PatchCustomer aplica um documento JSON Merge Patch (RFC 7396) ou JSON Patch (RFC 6902) à
+// representação do cliente na API, valida o resultado e persiste somente as colunas alteradas.
+// Patches que alteram campos gerenciados pelo servidor são rejeitados com ErrInvalidPatch.
+// Todas as operações do patch são aplicadas em uma única transação. Quando expectedVersion é
+// diferente de zero, o patch só é aplicado se o cliente ainda estiver nessa versão.
+func (s *customerService) PatchCustomer(ctx context.Context, id uint, expectedVersion uint, patchType PatchType, patch []byte) (*model.Customer, error) {
+	var operations jsonpatch.Patch
+	if patchType == JSONPatch {
+		var err error
+		if operations, err = jsonpatch.DecodePatch(patch); err != nil {
+			return nil, ErrInvalidPatch
+		}
+		if err := checkJSONPatch(operations); err != nil {
+			return nil, err
+		}
+	} else if err := checkMergePatch(patch); err != nil {
+		return nil, err
+	}
+
+	// Verifica se o cliente existe
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, lookupError(err)
+	}
+
+	customer, err := s.repo.UpdateInTx(ctx, id, func(current *model.Customer) (*model.Customer, error) {
+		if expectedVersion != 0 && current.Version != expectedVersion {
+			return nil, ErrVersionConflict
+		}
+
+		original, err := json.Marshal(newCustomerDocument(current))
+		if err != nil {
+			return nil, err
+		}
+
+		var patched []byte
+		if patchType == JSONPatch {
+			patched, err = operations.Apply(original)
+		} else {
+			patched, err = jsonpatch.MergePatch(original, patch)
+		}
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, ErrPatchTestFailed
+		}
+		if err != nil {
+			return nil, ErrInvalidPatch
+		}
+
+		return patchedCustomer(current, patched)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidPatch) || errors.Is(err, ErrPatchTestFailed) ||
+			errors.Is(err, ErrInvalidCustomer) || errors.Is(err, ErrVersionConflict) {
+			return nil, err
+		}
+		if conflict := alreadyExists(err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, lookupError(err)
+	}
+
+	return customer, nil
+}
+
+// DeleteCustomer exclui logicamente um cliente pelo ID, mantendo seu histórico. Quando expectedVersion
+// é diferente de zero, a exclusão só é aplicada se o cliente ainda estiver nessa versão.
+func (s *customerService) DeleteCustomer(ctx context.Context, id uint, expectedVersion uint) error {
 	// Verifica se o cliente existe
 	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return ErrCustomerNotFound
+		return lookupError(err)
 	}
 
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return ErrDatabaseOperation
+	if err := s.repo.Delete(ctx, id, expectedVersion); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return ErrVersionConflict
+		}
+		return lookupError(err)
 	}
 
 	return nil
 }
 
-// CountCustomers retorna o número total de clientes
-func (s *customerService) CountCustomers(ctx context.Context) (int64, error) {
-	return s.repo.Count(ctx)
+// RestoreCustomer desfaz a exclusão lógica de um cliente. O email do cliente não pode estar
+// em uso por outro cliente ativo.
+func (s *customerService) RestoreCustomer(ctx context.Context, id uint) (*model.Customer, error) {
+	customer, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotDeleted) {
+			return nil, ErrCustomerNotDeleted
+		}
+		if conflict := alreadyExists(err); conflict != nil {
+			return nil, conflict
+		}
+		return nil, lookupError(err)
+	}
+
+	return customer, nil
+}
+
+// PurgeCustomer remove definitivamente um cliente pelo ID, incluindo clientes excluídos logicamente
+func (s *customerService) PurgeCustomer(ctx context.Context, id uint) error {
+	if err := s.repo.Purge(ctx, id); err != nil {
+		return lookupError(err)
+	}
+
+	return nil
+}
+
+// CountCustomers retorna o número de clientes que atendem ao filtro; com o filtro vazio, o total de clientes
+func (s *customerService) CountCustomers(ctx context.Context, filter model.CustomerFilter) (int64, error) {
+	count, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return 0, databaseError(err)
+	}
+
+	return count, nil
 }
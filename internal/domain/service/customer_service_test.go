@@ -3,6 +3,8 @@ package service_test // Use _test package convention
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -10,6 +12,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/repository"
 	mock_repository "github.com/wandermaia/customer-api/internal/domain/repository/mock" // Import the generated mock
 	"github.com/wandermaia/customer-api/internal/domain/service"
 )
@@ -53,7 +56,14 @@ func TestCustomerService_CreateCustomer(t *testing.T) {
 
 		// Verifica se o erro retornado é o erro de validação esperado.
 		assert.Error(t, err)
-		assert.Equal(t, service.ErrInvalidCustomer, err)
+		assert.ErrorIs(t, err, service.ErrInvalidCustomer)
+
+		// Os detalhes da validação usam os nomes dos campos em JSON.
+		var validationErr *service.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			assert.Equal(t, "name", validationErr.Errors[0].Field())
+			assert.Equal(t, "required", validationErr.Errors[0].Tag())
+		}
 	})
 
 	t.Run("Repository Error", func(t *testing.T) {
@@ -67,9 +77,24 @@ func TestCustomerService_CreateCustomer(t *testing.T) {
 
 		// Verifica se o erro retornado é o erro de operação de banco de dados esperado.
 		assert.Error(t, err)
-		assert.Equal(t, service.ErrDatabaseOperation, err)
-		// Opcional: verificar se o erro original não foi perdido (se necessário)
-		// assert.True(t, errors.Is(err, service.ErrDatabaseOperation)) // Go 1.13+
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		// Verifica se o erro original não foi perdido.
+		assert.ErrorIs(t, err, repoErr)
+	})
+
+	t.Run("Already Exists", func(t *testing.T) {
+		customer := &model.Customer{Name: "Valid User", Email: "VALID@example.com"}
+
+		// Expectativa: o repositório rejeita o email já utilizado por outro cliente.
+		mockRepo.EXPECT().Create(ctx, customer).Return(&repository.DuplicateError{Field: "email"}).Times(1)
+
+		err := customerService.CreateCustomer(ctx, customer)
+
+		// Verifica se o erro indica o conflito e o campo em conflito.
+		assert.ErrorIs(t, err, service.ErrCustomerAlreadyExists)
+		var conflict *service.AlreadyExistsError
+		assert.ErrorAs(t, err, &conflict)
+		assert.Equal(t, "email", conflict.Field)
 	})
 }
 
@@ -83,27 +108,50 @@ func TestCustomerService_GetCustomerByID(t *testing.T) {
 		// Expectativa: GetByID será chamado com o ID correto e retornará o cliente.
 		mockRepo.EXPECT().GetByID(ctx, testID).Return(expectedCustomer, nil).Times(1)
 
-		customer, err := customerService.GetCustomerByID(ctx, testID)
+		customer, err := customerService.GetCustomerByID(ctx, testID, nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedCustomer, customer)
 	})
 
 	t.Run("Not Found", func(t *testing.T) {
-		// Simula o erro que o repositório retornaria se não encontrasse (pode variar)
-		// Usaremos um erro genérico aqui, pois o serviço deve traduzi-lo.
-		repoErr := errors.New("record not found") // ou gorm.ErrRecordNotFound, etc.
+		// Simula o erro que o repositório retorna quando não encontra o registro.
+		repoErr := repository.ErrNotFound
 
 		// Expectativa: GetByID será chamado, mas retornará um erro.
 		mockRepo.EXPECT().GetByID(ctx, testID).Return(nil, repoErr).Times(1)
 
-		customer, err := customerService.GetCustomerByID(ctx, testID)
+		customer, err := customerService.GetCustomerByID(ctx, testID, nil)
 
 		// Verifica se o erro retornado é o ErrCustomerNotFound esperado.
 		assert.Error(t, err)
 		assert.Equal(t, service.ErrCustomerNotFound, err)
 		assert.Nil(t, customer) // Garante que nenhum cliente foi retornado.
 	})
+
+	t.Run("Request Canceled", func(t *testing.T) {
+		// Expectativa: o cancelamento da requisição é retornado sem ser convertido em falha do banco de dados.
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(nil, fmt.Errorf("timeout: %w", context.Canceled)).Times(1)
+
+		customer, err := customerService.GetCustomerByID(ctx, testID, nil)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.NotErrorIs(t, err, service.ErrDatabaseUnavailable)
+		assert.Nil(t, customer)
+	})
+
+	t.Run("With Fields", func(t *testing.T) {
+		expectedCustomer := &model.Customer{ID: testID, Name: "Found User"}
+
+		// Expectativa: GetByID lê somente os campos selecionados.
+		mockRepo.EXPECT().GetByID(ctx, testID, "id", "name").Return(expectedCustomer, nil).Times(1)
+
+		customer, err := customerService.GetCustomerByID(ctx, testID, model.FieldSet{"id", "name"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedCustomer, customer)
+	})
 }
 
 func TestCustomerService_GetAllCustomers(t *testing.T) {
@@ -114,74 +162,245 @@ func TestCustomerService_GetAllCustomers(t *testing.T) {
 			{ID: 1, Name: "User One", Email: "one@example.com"},
 			{ID: 2, Name: "User Two", Email: "two@example.com"},
 		}
+		pagination := model.Pagination{Page: 2, PageSize: 2}
+
+		// Expectativa: GetAll será chamado com a ordenação padrão e a paginação e retornará a página de clientes.
+		mockRepo.EXPECT().GetAll(ctx, model.CustomerFilter{}, model.DefaultSort, pagination).Return(expectedCustomers, int64(5), nil).Times(1)
+
+		page, err := customerService.GetAllCustomers(ctx, model.CustomerFilter{}, nil, pagination)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedCustomers, page.Items)
+		assert.Equal(t, int64(5), *page.Total)
+		assert.Equal(t, 2, page.Page)
+		assert.Equal(t, 2, page.PageSize)
+		// Ainda existem registros após esta página, então o cursor do último item é retornado.
+		assert.Equal(t, model.CursorFor(expectedCustomers[1], model.DefaultSort).Encode(), page.NextCursor)
+	})
+
+	t.Run("Default Pagination", func(t *testing.T) {
+		// Sem parâmetros, o serviço deve aplicar a página 1 e o tamanho padrão.
+		expectedPagination := model.Pagination{Page: 1, PageSize: model.DefaultPageSize}
+		mockRepo.EXPECT().GetAll(ctx, model.CustomerFilter{}, model.DefaultSort, expectedPagination).Return([]*model.Customer{}, int64(0), nil).Times(1)
+
+		page, err := customerService.GetAllCustomers(ctx, model.CustomerFilter{}, nil, model.Pagination{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, page.Page)
+		assert.Equal(t, model.DefaultPageSize, page.PageSize)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Page Size Above Maximum", func(t *testing.T) {
+		// O tamanho da página deve ser limitado ao máximo permitido pelo servidor.
+		expectedPagination := model.Pagination{Page: 1, PageSize: model.MaxPageSize}
+		mockRepo.EXPECT().GetAll(ctx, model.CustomerFilter{}, gomock.Any(), expectedPagination).Return([]*model.Customer{}, int64(0), nil).Times(1)
+
+		page, err := customerService.GetAllCustomers(ctx, model.CustomerFilter{}, nil, model.Pagination{Page: 1, PageSize: 10000})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.MaxPageSize, page.PageSize)
+	})
+
+	t.Run("With Filter", func(t *testing.T) {
+		active := false
+		filter := model.CustomerFilter{Active: &active, EmailDomain: "example.com"}
+
+		// Expectativa: o filtro deve ser repassado ao repositório sem alterações.
+		mockRepo.EXPECT().GetAll(ctx, filter, gomock.Any(), gomock.Any()).Return([]*model.Customer{}, int64(0), nil).Times(1)
+
+		page, err := customerService.GetAllCustomers(ctx, filter, nil, model.Pagination{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, page.Items)
+	})
+
+	t.Run("With Sort", func(t *testing.T) {
+		sort := model.Sort{{Field: "created_at", Desc: true}, {Field: "name"}}
+		// O id deve ser acrescentado como critério de desempate.
+		expectedSort := model.Sort{{Field: "created_at", Desc: true}, {Field: "name"}, {Field: "id"}}
+		expectedCustomers := []*model.Customer{{ID: 7, Name: "User Seven", Email: "seven@example.com"}}
+
+		mockRepo.EXPECT().GetAll(ctx, model.CustomerFilter{}, expectedSort, gomock.Any()).Return(expectedCustomers, int64(2), nil).Times(1)
+
+		page, err := customerService.GetAllCustomers(ctx, model.CustomerFilter{}, sort, model.Pagination{PageSize: 1})
+
+		assert.NoError(t, err)
+		// O cursor retornado carrega a ordenação utilizada.
+		cursor, err := model.DecodeCursor(page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSort, cursor.Sort)
+		assert.Equal(t, []any{expectedCustomers[0].CreatedAt, "User Seven", uint(7)}, cursor.Values)
+	})
+
+	t.Run("Cursor Pagination", func(t *testing.T) {
+		createdAt := time.Date(2025, 4, 23, 15, 4, 5, 0, time.UTC)
+		cursor := model.CursorFor(&model.Customer{ID: 10, CreatedAt: createdAt}, model.DefaultSort)
+		expectedCustomers := []*model.Customer{
+			{ID: 11, Name: "User Eleven", Email: "eleven@example.com", CreatedAt: createdAt},
+			{ID: 12, Name: "User Twelve", Email: "twelve@example.com", CreatedAt: createdAt},
+			{ID: 13, Name: "User Thirteen", Email: "thirteen@example.com", CreatedAt: createdAt},
+		}
 
-		// Expectativa: GetAll será chamado e retornará a lista de clientes.
-		mockRepo.EXPECT().GetAll(ctx).Return(expectedCustomers, nil).Times(1)
+		// Expectativa: GetAllAfter será chamado com o cursor decodificado e um registro a mais que a página.
+		mockRepo.EXPECT().GetAllAfter(ctx, model.CustomerFilter{}, cursor, 3).Return(expectedCustomers, nil).Times(1)
 
-		customers, err := customerService.GetAllCustomers(ctx)
+		page, err := customerService.GetAllCustomers(ctx, model.CustomerFilter{}, nil, model.Pagination{PageSize: 2, Cursor: cursor.Encode()})
 
 		assert.NoError(t, err)
-		assert.Equal(t, expectedCustomers, customers)
+		assert.Equal(t, expectedCustomers[:2], page.Items)
+		assert.Nil(t, page.Total)
+		assert.True(t, page.IsCursorPage())
+		assert.Equal(t, model.CursorFor(expectedCustomers[1], model.DefaultSort).Encode(), page.NextCursor)
+	})
+
+	t.Run("Cursor Last Page", func(t *testing.T) {
+		cursor := model.CursorFor(&model.Customer{ID: 20, CreatedAt: time.Now().UTC()}, model.DefaultSort)
+		expectedCustomers := []*model.Customer{{ID: 21, Name: "User Twenty One", Email: "21@example.com"}}
+
+		mockRepo.EXPECT().GetAllAfter(ctx, gomock.Any(), gomock.Any(), 3).Return(expectedCustomers, nil).Times(1)
+
+		page, err := customerService.GetAllCustomers(ctx, model.CustomerFilter{}, nil, model.Pagination{PageSize: 2, Cursor: cursor.Encode()})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedCustomers, page.Items)
+		assert.Empty(t, page.NextCursor) // Não há próxima página.
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		// Expectativa: GetAllAfter NÃO deve ser chamado.
+		mockRepo.EXPECT().GetAllAfter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		page, err := customerService.GetAllCustomers(ctx, model.CustomerFilter{}, nil, model.Pagination{Cursor: "not-a-cursor"})
+
+		assert.Error(t, err)
+		assert.Equal(t, service.ErrInvalidCursor, err)
+		assert.Nil(t, page)
+	})
+
+	t.Run("Cursor With Different Sort", func(t *testing.T) {
+		cursor := model.CursorFor(&model.Customer{ID: 20}, model.DefaultSort)
+
+		// Expectativa: GetAllAfter NÃO deve ser chamado, pois a ordenação não corresponde à do cursor.
+		mockRepo.EXPECT().GetAllAfter(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		page, err := customerService.GetAllCustomers(ctx, model.CustomerFilter{}, model.Sort{{Field: "name"}}, model.Pagination{Cursor: cursor.Encode()})
+
+		assert.Equal(t, service.ErrInvalidCursor, err)
+		assert.Nil(t, page)
 	})
 
 	t.Run("Repository Error", func(t *testing.T) {
 		repoErr := errors.New("failed to fetch all")
 
 		// Expectativa: GetAll será chamado e retornará um erro.
-		mockRepo.EXPECT().GetAll(ctx).Return(nil, repoErr).Times(1)
+		mockRepo.EXPECT().GetAll(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, int64(0), repoErr).Times(1)
 
-		customers, err := customerService.GetAllCustomers(ctx)
+		page, err := customerService.GetAllCustomers(ctx, model.CustomerFilter{}, nil, model.Pagination{})
 
 		// Verifica se o erro original do repositório foi repassado.
 		assert.Error(t, err)
-		assert.Equal(t, repoErr, err) // O serviço repassa o erro diretamente aqui.
-		assert.Nil(t, customers)
+		// O serviço converte o erro em ErrDatabaseOperation, mantendo a causa original.
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.ErrorIs(t, err, repoErr)
+		assert.Nil(t, page)
 	})
 }
 
-func TestCustomerService_GetCustomersByName(t *testing.T) {
+func TestCustomerService_SearchCustomers(t *testing.T) {
 	ctx, customerService, mockRepo := setup(t)
-	searchName := "Test"
+	search := model.CustomerSearch{Name: "Test", MinScore: service.DefaultMinScore}
+	// Sem paginação informada, a busca retorna a primeira página com a quantidade padrão de registros.
+	defaultPagination := model.Pagination{Page: 1, PageSize: model.DefaultPageSize}
 
 	t.Run("Success", func(t *testing.T) {
-		expectedCustomers := []*model.Customer{
-			{ID: 1, Name: "Test User 1", Email: "test1@example.com"},
-			{ID: 3, Name: "Test User 3", Email: "test3@example.com"},
+		expectedMatches := []*model.CustomerMatch{
+			{Customer: &model.Customer{ID: 1, Name: "Test User 1", Email: "test1@example.com"}, MatchedField: model.SearchFieldName, Score: 0.8},
+			{Customer: &model.Customer{ID: 3, Name: "Test User 3", Email: "test3@example.com"}, MatchedField: model.SearchFieldName, Score: 0.5},
 		}
 
-		// Expectativa: GetByName será chamado com o nome correto.
-		mockRepo.EXPECT().GetByName(ctx, searchName).Return(expectedCustomers, nil).Times(1)
+		// Expectativa: sem ordenação informada, Search ordena pela pontuação.
+		mockRepo.EXPECT().Search(ctx, search, nil, defaultPagination).Return(expectedMatches, nil).Times(1)
+
+		matches, err := customerService.SearchCustomers(ctx, search, nil, model.Pagination{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedMatches, matches)
+	})
+
+	t.Run("Multiple Terms", func(t *testing.T) {
+		// Expectativa: o termo geral e os termos por campo são repassados juntos ao repositório.
+		multiple := model.CustomerSearch{Query: "silva", Email: "example.com", Phone: "11987", MinScore: 0.4}
+		mockRepo.EXPECT().Search(ctx, multiple, nil, defaultPagination).Return([]*model.CustomerMatch{}, nil).Times(1)
+
+		_, err := customerService.SearchCustomers(ctx, multiple, nil, model.Pagination{})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("With Sort", func(t *testing.T) {
+		// Expectativa: a ordenação informada é repassada com o id como desempate.
+		expectedSort := model.Sort{{Field: "name", Desc: true}, {Field: "id"}}
+		mockRepo.EXPECT().Search(ctx, search, expectedSort, defaultPagination).Return([]*model.CustomerMatch{}, nil).Times(1)
+
+		_, err := customerService.SearchCustomers(ctx, search, model.Sort{{Field: "name", Desc: true}}, model.Pagination{})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Page Size Limit", func(t *testing.T) {
+		// Expectativa: a página informada é repassada, com a quantidade de registros limitada ao máximo do servidor.
+		mockRepo.EXPECT().
+			Search(ctx, search, nil, model.Pagination{Page: 3, PageSize: model.MaxPageSize}).
+			Return([]*model.CustomerMatch{}, nil).
+			Times(1)
 
-		customers, err := customerService.GetCustomersByName(ctx, searchName)
+		_, err := customerService.SearchCustomers(ctx, search, nil, model.Pagination{Page: 3, PageSize: 1000})
 
 		assert.NoError(t, err)
-		assert.Equal(t, expectedCustomers, customers)
 	})
 
-	t.Run("Empty Name Error", func(t *testing.T) {
-		// Expectativa: GetByName NÃO deve ser chamado.
-		mockRepo.EXPECT().GetByName(gomock.Any(), gomock.Any()).Times(0)
+	t.Run("Invalid Min Score", func(t *testing.T) {
+		// Expectativa: Search NÃO deve ser chamado com pontuações fora do intervalo de 0 (exclusive) a 1; com 0, todos
+		// os clientes seriam retornados.
+		mockRepo.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for _, minScore := range []float64{0, -0.1, 1.5, math.NaN()} {
+			invalid := search
+			invalid.MinScore = minScore
+
+			matches, err := customerService.SearchCustomers(ctx, invalid, nil, model.Pagination{})
+
+			assert.Equal(t, service.ErrInvalidMinScore, err)
+			assert.Nil(t, matches)
+		}
+	})
+
+	t.Run("Empty Search Error", func(t *testing.T) {
+		// Expectativa: Search NÃO deve ser chamado.
+		mockRepo.EXPECT().Search(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		customers, err := customerService.GetCustomersByName(ctx, "") // Chama com nome vazio
+		matches, err := customerService.SearchCustomers(ctx, model.CustomerSearch{MinScore: service.DefaultMinScore}, nil, model.Pagination{}) // Chama sem termos de busca
 
-		// Verifica se o erro retornado é o de validação.
+		// Verifica se o erro retornado é o de busca vazia.
 		assert.Error(t, err)
-		assert.Equal(t, service.ErrInvalidCustomer, err)
-		assert.Nil(t, customers)
+		assert.Equal(t, service.ErrEmptySearch, err)
+		assert.Nil(t, matches)
 	})
 
 	t.Run("Repository Error", func(t *testing.T) {
 		repoErr := errors.New("search failed")
 
-		// Expectativa: GetByName será chamado e retornará um erro.
-		mockRepo.EXPECT().GetByName(ctx, searchName).Return(nil, repoErr).Times(1)
+		// Expectativa: Search será chamado e retornará um erro.
+		mockRepo.EXPECT().Search(ctx, search, gomock.Any(), defaultPagination).Return(nil, repoErr).Times(1)
 
-		customers, err := customerService.GetCustomersByName(ctx, searchName)
+		matches, err := customerService.SearchCustomers(ctx, search, nil, model.Pagination{})
 
 		// Verifica se o erro original do repositório foi repassado.
 		assert.Error(t, err)
-		assert.Equal(t, repoErr, err)
-		assert.Nil(t, customers)
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.ErrorIs(t, err, repoErr)
+		assert.Nil(t, matches)
 	})
 }
 
@@ -199,11 +418,40 @@ func TestCustomerService_UpdateCustomer(t *testing.T) {
 		// Expectativa 2: Update será chamado com os dados atualizados.
 		mockRepo.EXPECT().Update(ctx, customerToUpdate).Return(nil).Times(1)
 
-		err := customerService.UpdateCustomer(ctx, customerToUpdate)
+		err := customerService.UpdateCustomer(ctx, customerToUpdate, 0)
 
 		assert.NoError(t, err)
 	})
 
+	t.Run("Version Conflict", func(t *testing.T) {
+		existingCustomer := &model.Customer{ID: testID, Name: "Old Name", Email: "old@example.com", Version: 4}
+		customer := &model.Customer{ID: testID, Name: "Updated Name", Email: "updated@example.com"}
+
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existingCustomer, nil).Times(1)
+		// Expectativa: a versão esperada é repassada ao repositório, que rejeita a alteração.
+		mockRepo.EXPECT().
+			Update(ctx, customer).
+			DoAndReturn(func(ctx context.Context, c *model.Customer) error {
+				assert.Equal(t, uint(3), c.Version)
+				return repository.ErrVersionConflict
+			}).Times(1)
+
+		err := customerService.UpdateCustomer(ctx, customer, 3)
+
+		assert.Equal(t, service.ErrVersionConflict, err)
+	})
+
+	t.Run("Already Exists", func(t *testing.T) {
+		existingCustomer := &model.Customer{ID: testID, Name: "Old Name", Email: "old@example.com"}
+
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existingCustomer, nil).Times(1)
+		mockRepo.EXPECT().Update(ctx, customerToUpdate).Return(&repository.DuplicateError{Field: "email"}).Times(1)
+
+		err := customerService.UpdateCustomer(ctx, customerToUpdate, 0)
+
+		assert.ErrorIs(t, err, service.ErrCustomerAlreadyExists)
+	})
+
 	t.Run("Validation Error", func(t *testing.T) {
 		invalidCustomer := &model.Customer{ID: testID, Name: ""} // Nome inválido
 
@@ -211,21 +459,21 @@ func TestCustomerService_UpdateCustomer(t *testing.T) {
 		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
 		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
 
-		err := customerService.UpdateCustomer(ctx, invalidCustomer)
+		err := customerService.UpdateCustomer(ctx, invalidCustomer, 0)
 
 		assert.Error(t, err)
-		assert.Equal(t, service.ErrInvalidCustomer, err)
+		assert.ErrorIs(t, err, service.ErrInvalidCustomer)
 	})
 
 	t.Run("Not Found Error", func(t *testing.T) {
-		repoErr := errors.New("record not found")
+		repoErr := repository.ErrNotFound
 
 		// Expectativa: GetByID será chamado e retornará erro.
 		mockRepo.EXPECT().GetByID(ctx, testID).Return(nil, repoErr).Times(1)
 		// Expectativa: Update NÃO deve ser chamado.
 		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
 
-		err := customerService.UpdateCustomer(ctx, customerToUpdate)
+		err := customerService.UpdateCustomer(ctx, customerToUpdate, 0)
 
 		assert.Error(t, err)
 		assert.Equal(t, service.ErrCustomerNotFound, err)
@@ -240,10 +488,321 @@ func TestCustomerService_UpdateCustomer(t *testing.T) {
 		// Expectativa 2: Update será chamado, mas retornará erro.
 		mockRepo.EXPECT().Update(ctx, customerToUpdate).Return(repoErr).Times(1)
 
-		err := customerService.UpdateCustomer(ctx, customerToUpdate)
+		err := customerService.UpdateCustomer(ctx, customerToUpdate, 0)
 
 		assert.Error(t, err)
-		assert.Equal(t, service.ErrDatabaseOperation, err)
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+	})
+}
+
+func TestCustomerService_PatchCustomer(t *testing.T) {
+	ctx, customerService, mockRepo := setup(t)
+	testID := uint(1)
+	createdAt := time.Date(2025, 4, 23, 15, 4, 5, 0, time.UTC)
+
+	// existing retorna uma nova cópia do cliente armazenado para cada subteste.
+	existing := func() *model.Customer {
+		return &model.Customer{
+			ID: testID, Name: "João da Silva", Email: "joao@example.com", Phone: "(11) 98765-4321",
+			Address: "Av. Paulista, 1000", Active: true, CreatedAt: createdAt, UpdatedAt: createdAt,
+		}
+	}
+
+	// applyToExisting simula a transação do repositório, aplicando a função recebida ao cliente armazenado.
+	applyToExisting := func(ctx context.Context, id uint, apply func(*model.Customer) (*model.Customer, error)) (*model.Customer, error) {
+		return apply(existing())
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		// Expectativa: a alteração é aplicada dentro da transação do repositório.
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).DoAndReturn(applyToExisting).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(`{"active": false}`))
+
+		assert.NoError(t, err)
+		assert.False(t, customer.Active)
+		// Os demais campos são preservados.
+		assert.Equal(t, "João da Silva", customer.Name)
+		assert.Equal(t, "(11) 98765-4321", customer.Phone)
+		assert.Equal(t, "Av. Paulista, 1000", customer.Address)
+	})
+
+	t.Run("Null Removes Optional Field", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).DoAndReturn(applyToExisting).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(`{"phone": null, "address": null}`))
+
+		assert.NoError(t, err)
+		assert.Empty(t, customer.Phone)
+		assert.Empty(t, customer.Address)
+	})
+
+	t.Run("Null Required Field", func(t *testing.T) {
+		// Expectativa: null em campos que não podem ser removidos é rejeitado antes de acessar o repositório, em
+		// vez de gravar o valor zero (active false ou nome vazio).
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().UpdateInTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for path, patch := range map[string]string{
+			"/active": `{"active": null}`,
+			"/name":   `{"name": null}`,
+			"/email":  `{"email": null, "phone": "(11) 91234-5678"}`,
+		} {
+			customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(patch))
+
+			assert.ErrorIs(t, err, service.ErrInvalidPatch, patch)
+			assert.ErrorContains(t, err, path+" não pode ser removido", patch)
+			assert.Nil(t, customer)
+		}
+	})
+
+	t.Run("No Changes", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).DoAndReturn(applyToExisting).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(`{"name": "João da Silva"}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, existing(), customer)
+	})
+
+	t.Run("JSON Patch Success", func(t *testing.T) {
+		patch := `[
+			{"op": "test", "path": "/email", "value": "joao@example.com"},
+			{"op": "replace", "path": "/email", "value": "joao.silva@example.com"},
+			{"op": "remove", "path": "/phone"}
+		]`
+
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).DoAndReturn(applyToExisting).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.JSONPatch, []byte(patch))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "joao.silva@example.com", customer.Email)
+		assert.Empty(t, customer.Phone)
+		assert.Equal(t, "João da Silva", customer.Name)
+	})
+
+	t.Run("JSON Patch Test Failed", func(t *testing.T) {
+		patch := `[
+			{"op": "test", "path": "/email", "value": "outro@example.com"},
+			{"op": "replace", "path": "/email", "value": "joao.silva@example.com"}
+		]`
+
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).DoAndReturn(applyToExisting).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.JSONPatch, []byte(patch))
+
+		assert.Equal(t, service.ErrPatchTestFailed, err)
+		assert.Nil(t, customer)
+	})
+
+	t.Run("JSON Patch Invalid Document", func(t *testing.T) {
+		// Expectativa: documentos mal formados são rejeitados antes de acessar o repositório.
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().UpdateInTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for _, patch := range []string{`{"active": false}`, `[{"op": "replace"`} {
+			customer, err := customerService.PatchCustomer(ctx, testID, 0, service.JSONPatch, []byte(patch))
+
+			assert.Equal(t, service.ErrInvalidPatch, err, patch)
+			assert.Nil(t, customer)
+		}
+	})
+
+	t.Run("JSON Patch Server Managed Paths", func(t *testing.T) {
+		// Expectativa: operações sobre campos gerenciados pelo servidor ou fora da representação do cliente são
+		// rejeitadas antes de acessar o repositório.
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().UpdateInTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for path, patch := range map[string]string{
+			"/id":                `[{"op": "move", "from": "/id", "path": "/name"}]`,
+			"/inexistente/campo": `[{"op": "replace", "path": "/inexistente/campo", "value": 1}]`,
+			"/created_at":        `[{"op": "copy", "from": "/name", "path": "/created_at"}]`,
+			"/deleted_at":        `[{"op": "add", "path": "/deleted_at", "value": "2025-05-01T10:00:00Z"}]`,
+			"/version":           `[{"op": "replace", "path": "/version", "value": 9}]`,
+			"/name/0":            `[{"op": "add", "path": "/name/0", "value": "x"}]`,
+		} {
+			customer, err := customerService.PatchCustomer(ctx, testID, 0, service.JSONPatch, []byte(patch))
+
+			assert.ErrorIs(t, err, service.ErrInvalidPatch, patch)
+			assert.ErrorContains(t, err, path, patch)
+			assert.Nil(t, customer)
+		}
+	})
+
+	t.Run("JSON Patch Remove Required Field", func(t *testing.T) {
+		// Expectativa: remover, diretamente, com o valor null ou movendo o valor para outro campo, um campo que não
+		// pode ser removido é rejeitado antes de acessar o repositório.
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().UpdateInTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for path, patch := range map[string]string{
+			"/active": `[{"op": "remove", "path": "/active"}]`,
+			"/name":   `[{"op": "replace", "path": "/name", "value": null}]`,
+			"/email":  `[{"op": "move", "from": "/email", "path": "/address"}]`,
+		} {
+			customer, err := customerService.PatchCustomer(ctx, testID, 0, service.JSONPatch, []byte(patch))
+
+			assert.ErrorIs(t, err, service.ErrInvalidPatch, patch)
+			assert.ErrorContains(t, err, path+" não pode ser removido", patch)
+			assert.Nil(t, customer)
+		}
+	})
+
+	t.Run("JSON Patch Test Server Managed Field", func(t *testing.T) {
+		// Expectativa: a operação test pode comparar os campos somente leitura da representação.
+		patch := `[
+			{"op": "test", "path": "/id", "value": 1},
+			{"op": "test", "path": "/created_at", "value": "2025-04-23T15:04:05Z"},
+			{"op": "replace", "path": "/name", "value": "João Silva"}
+		]`
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).DoAndReturn(applyToExisting).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.JSONPatch, []byte(patch))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "João Silva", customer.Name)
+	})
+
+	t.Run("JSON Patch Invalid Value", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).DoAndReturn(applyToExisting).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.JSONPatch, []byte(`[{"op": "replace", "path": "/active", "value": "sim"}]`))
+
+		assert.Equal(t, service.ErrInvalidPatch, err)
+		assert.Nil(t, customer)
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).DoAndReturn(applyToExisting).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(`{"email": "not-an-email"}`))
+
+		assert.ErrorIs(t, err, service.ErrInvalidCustomer)
+		assert.Nil(t, customer)
+
+		var validationErr *service.ValidationError
+		if assert.ErrorAs(t, err, &validationErr) {
+			assert.Equal(t, "email", validationErr.Errors[0].Field())
+			assert.Equal(t, "email", validationErr.Errors[0].Tag())
+		}
+	})
+
+	t.Run("Invalid Patch", func(t *testing.T) {
+		// Expectativa: documentos que não são um objeto JSON são rejeitados antes de acessar o repositório.
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().UpdateInTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for _, patch := range []string{`{"invalid`, `[{"op": "remove", "path": "/phone"}]`, `null`} {
+			customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(patch))
+
+			assert.Equal(t, service.ErrInvalidPatch, err, patch)
+			assert.Nil(t, customer)
+		}
+	})
+
+	t.Run("Server Managed Fields", func(t *testing.T) {
+		// Expectativa: campos gerenciados pelo servidor, mesmo com o valor atual, e campos fora da representação do
+		// cliente são rejeitados antes de acessar o repositório.
+		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().UpdateInTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for path, patch := range map[string]string{
+			"/id":         `{"id": 1}`,
+			"/created_at": `{"created_at": "2020-01-01T00:00:00Z"}`,
+			"/updated_at": `{"updated_at": "2020-01-01T00:00:00Z", "active": false}`,
+			"/deleted_at": `{"deleted_at": "2020-01-01T00:00:00Z"}`,
+			"/version":    `{"version": 9, "active": false}`,
+		} {
+			customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(patch))
+
+			assert.ErrorIs(t, err, service.ErrInvalidPatch, patch)
+			assert.ErrorContains(t, err, path, patch)
+			assert.Nil(t, customer)
+		}
+	})
+
+	t.Run("Invalid Value", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).DoAndReturn(applyToExisting).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(`{"active": "sim"}`))
+
+		assert.Equal(t, service.ErrInvalidPatch, err)
+		assert.Nil(t, customer)
+	})
+
+	t.Run("Version Conflict", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		// O cliente armazenado está na versão 2, mas a requisição espera a versão 1.
+		mockRepo.EXPECT().
+			UpdateInTx(ctx, testID, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id uint, apply func(*model.Customer) (*model.Customer, error)) (*model.Customer, error) {
+				current := existing()
+				current.Version = 2
+				return apply(current)
+			}).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 1, service.MergePatch, []byte(`{"active": false}`))
+
+		assert.Equal(t, service.ErrVersionConflict, err)
+		assert.Nil(t, customer)
+	})
+
+	t.Run("Keeps Version", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().
+			UpdateInTx(ctx, testID, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id uint, apply func(*model.Customer) (*model.Customer, error)) (*model.Customer, error) {
+				current := existing()
+				current.Version = 2
+				return apply(current)
+			}).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 2, service.MergePatch, []byte(`{"active": false}`))
+
+		// A versão não faz parte do documento de patch; o repositório é quem a incrementa.
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), customer.Version)
+	})
+
+	t.Run("Not Found Error", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(nil, repository.ErrNotFound).Times(1)
+		mockRepo.EXPECT().UpdateInTx(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(`{"active": false}`))
+
+		assert.Equal(t, service.ErrCustomerNotFound, err)
+		assert.Nil(t, customer)
+	})
+
+	t.Run("Already Exists", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).Return(nil, &repository.DuplicateError{Field: "email"}).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(`{"email": "taken@example.com"}`))
+
+		assert.ErrorIs(t, err, service.ErrCustomerAlreadyExists)
+		assert.Nil(t, customer)
+	})
+
+	t.Run("Repository Update Error", func(t *testing.T) {
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existing(), nil).Times(1)
+		mockRepo.EXPECT().UpdateInTx(ctx, testID, gomock.Any()).Return(nil, errors.New("database update failed")).Times(1)
+
+		customer, err := customerService.PatchCustomer(ctx, testID, 0, service.MergePatch, []byte(`{"active": false}`))
+
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.Nil(t, customer)
 	})
 }
 
@@ -257,27 +816,39 @@ func TestCustomerService_DeleteCustomer(t *testing.T) {
 		// Expectativa 1: GetByID será chamado para verificar existência.
 		mockRepo.EXPECT().GetByID(ctx, testID).Return(existingCustomer, nil).Times(1)
 		// Expectativa 2: Delete será chamado com o ID correto.
-		mockRepo.EXPECT().Delete(ctx, testID).Return(nil).Times(1)
+		mockRepo.EXPECT().Delete(ctx, testID, uint(0)).Return(nil).Times(1)
 
-		err := customerService.DeleteCustomer(ctx, testID)
+		err := customerService.DeleteCustomer(ctx, testID, 0)
 
 		assert.NoError(t, err)
 	})
 
 	t.Run("Not Found Error", func(t *testing.T) {
-		repoErr := errors.New("record not found")
+		repoErr := repository.ErrNotFound
 
 		// Expectativa: GetByID será chamado e retornará erro.
 		mockRepo.EXPECT().GetByID(ctx, testID).Return(nil, repoErr).Times(1)
 		// Expectativa: Delete NÃO deve ser chamado.
-		mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+		mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		err := customerService.DeleteCustomer(ctx, testID)
+		err := customerService.DeleteCustomer(ctx, testID, 0)
 
 		assert.Error(t, err)
 		assert.Equal(t, service.ErrCustomerNotFound, err)
 	})
 
+	t.Run("Version Conflict", func(t *testing.T) {
+		existingCustomer := &model.Customer{ID: testID, Name: "To Delete", Email: "delete@example.com", Version: 2}
+
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existingCustomer, nil).Times(1)
+		// Expectativa: a versão esperada é repassada ao repositório, que rejeita a remoção.
+		mockRepo.EXPECT().Delete(ctx, testID, uint(1)).Return(repository.ErrVersionConflict).Times(1)
+
+		err := customerService.DeleteCustomer(ctx, testID, 1)
+
+		assert.Equal(t, service.ErrVersionConflict, err)
+	})
+
 	t.Run("Repository Delete Error", func(t *testing.T) {
 		existingCustomer := &model.Customer{ID: testID, Name: "To Delete", Email: "delete@example.com"}
 		repoErr := errors.New("database delete failed")
@@ -285,12 +856,131 @@ func TestCustomerService_DeleteCustomer(t *testing.T) {
 		// Expectativa 1: GetByID será chamado e retornará sucesso.
 		mockRepo.EXPECT().GetByID(ctx, testID).Return(existingCustomer, nil).Times(1)
 		// Expectativa 2: Delete será chamado, mas retornará erro.
-		mockRepo.EXPECT().Delete(ctx, testID).Return(repoErr).Times(1)
+		mockRepo.EXPECT().Delete(ctx, testID, uint(0)).Return(repoErr).Times(1)
 
-		err := customerService.DeleteCustomer(ctx, testID)
+		err := customerService.DeleteCustomer(ctx, testID, 0)
 
 		assert.Error(t, err)
-		assert.Equal(t, service.ErrDatabaseOperation, err)
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+	})
+
+	t.Run("Removed Concurrently", func(t *testing.T) {
+		existingCustomer := &model.Customer{ID: testID, Name: "To Delete", Email: "delete@example.com"}
+
+		// Expectativa: o cliente é removido por outra requisição entre a busca e a remoção.
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existingCustomer, nil).Times(1)
+		mockRepo.EXPECT().Delete(ctx, testID, uint(0)).Return(repository.ErrNotFound).Times(1)
+
+		err := customerService.DeleteCustomer(ctx, testID, 0)
+
+		assert.Equal(t, service.ErrCustomerNotFound, err)
+	})
+
+	t.Run("Removed Concurrently With Version", func(t *testing.T) {
+		existingCustomer := &model.Customer{ID: testID, Name: "To Delete", Email: "delete@example.com", Version: 3}
+
+		// Expectativa: com a versão informada, o repositório distingue o cliente removido do conflito de versão.
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(existingCustomer, nil).Times(1)
+		mockRepo.EXPECT().Delete(ctx, testID, uint(3)).Return(fmt.Errorf("%w: record not found", repository.ErrNotFound)).Times(1)
+
+		err := customerService.DeleteCustomer(ctx, testID, 3)
+
+		assert.Equal(t, service.ErrCustomerNotFound, err)
+	})
+
+	t.Run("Database Unavailable", func(t *testing.T) {
+		repoErr := fmt.Errorf("%w: dial tcp: connection refused", repository.ErrUnavailable)
+
+		// Expectativa: a falha de conexão não é confundida com cliente inexistente.
+		mockRepo.EXPECT().GetByID(ctx, testID).Return(nil, repoErr).Times(1)
+		mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := customerService.DeleteCustomer(ctx, testID, 0)
+
+		assert.ErrorIs(t, err, service.ErrDatabaseUnavailable)
+		assert.NotErrorIs(t, err, service.ErrCustomerNotFound)
+		assert.ErrorIs(t, err, repoErr)
+	})
+}
+
+func TestCustomerService_RestoreCustomer(t *testing.T) {
+	ctx, customerService, mockRepo := setup(t)
+	testID := uint(1)
+
+	t.Run("Success", func(t *testing.T) {
+		restored := &model.Customer{ID: testID, Name: "Restored", Email: "restored@example.com", Version: 3}
+
+		// Expectativa: Restore será chamado e retornará o cliente restaurado.
+		mockRepo.EXPECT().Restore(ctx, testID).Return(restored, nil).Times(1)
+
+		customer, err := customerService.RestoreCustomer(ctx, testID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, restored, customer)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockRepo.EXPECT().Restore(ctx, testID).Return(nil, repository.ErrNotFound).Times(1)
+
+		customer, err := customerService.RestoreCustomer(ctx, testID)
+
+		assert.Equal(t, service.ErrCustomerNotFound, err)
+		assert.Nil(t, customer)
+	})
+
+	t.Run("Not Deleted", func(t *testing.T) {
+		mockRepo.EXPECT().Restore(ctx, testID).Return(nil, repository.ErrNotDeleted).Times(1)
+
+		customer, err := customerService.RestoreCustomer(ctx, testID)
+
+		assert.Equal(t, service.ErrCustomerNotDeleted, err)
+		assert.Nil(t, customer)
+	})
+
+	t.Run("Already Exists", func(t *testing.T) {
+		// Expectativa: o email do cliente já pertence a outro cliente ativo.
+		mockRepo.EXPECT().Restore(ctx, testID).Return(nil, &repository.DuplicateError{Field: "email"}).Times(1)
+
+		customer, err := customerService.RestoreCustomer(ctx, testID)
+
+		assert.ErrorIs(t, err, service.ErrCustomerAlreadyExists)
+		var conflict *service.AlreadyExistsError
+		if assert.ErrorAs(t, err, &conflict) {
+			assert.Equal(t, "email", conflict.Field)
+		}
+		assert.Nil(t, customer)
+	})
+}
+
+func TestCustomerService_PurgeCustomer(t *testing.T) {
+	ctx, customerService, mockRepo := setup(t)
+	testID := uint(1)
+
+	t.Run("Success", func(t *testing.T) {
+		// Expectativa: Purge será chamado com o ID correto.
+		mockRepo.EXPECT().Purge(ctx, testID).Return(nil).Times(1)
+
+		err := customerService.PurgeCustomer(ctx, testID)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockRepo.EXPECT().Purge(ctx, testID).Return(repository.ErrNotFound).Times(1)
+
+		err := customerService.PurgeCustomer(ctx, testID)
+
+		assert.Equal(t, service.ErrCustomerNotFound, err)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		repoErr := errors.New("database delete failed")
+		mockRepo.EXPECT().Purge(ctx, testID).Return(repoErr).Times(1)
+
+		err := customerService.PurgeCustomer(ctx, testID)
+
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.ErrorIs(t, err, repoErr)
 	})
 }
 
@@ -301,9 +991,9 @@ func TestCustomerService_CountCustomers(t *testing.T) {
 		expectedCount := int64(42)
 
 		// Expectativa: Count será chamado e retornará a contagem.
-		mockRepo.EXPECT().Count(ctx).Return(expectedCount, nil).Times(1)
+		mockRepo.EXPECT().Count(ctx, model.CustomerFilter{}).Return(expectedCount, nil).Times(1)
 
-		count, err := customerService.CountCustomers(ctx)
+		count, err := customerService.CountCustomers(ctx, model.CustomerFilter{})
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedCount, count)
@@ -313,13 +1003,14 @@ func TestCustomerService_CountCustomers(t *testing.T) {
 		repoErr := errors.New("failed to count")
 
 		// Expectativa: Count será chamado e retornará um erro.
-		mockRepo.EXPECT().Count(ctx).Return(int64(0), repoErr).Times(1)
+		mockRepo.EXPECT().Count(ctx, model.CustomerFilter{}).Return(int64(0), repoErr).Times(1)
 
-		count, err := customerService.CountCustomers(ctx)
+		count, err := customerService.CountCustomers(ctx, model.CustomerFilter{})
 
 		// Verifica se o erro original do repositório foi repassado.
 		assert.Error(t, err)
-		assert.Equal(t, repoErr, err)
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.ErrorIs(t, err, repoErr)
 		assert.Equal(t, int64(0), count) // Verifica se a contagem retornada é 0 em caso de erro.
 	})
 }
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/repository"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("chave de idempotência já utilizada com outra requisição")
+	ErrIdempotencyKeyInProgress = errors.New("requisição com a mesma chave de idempotência em andamento")
+)
+
+// IdempotencyService define as operações de controle de chaves de idempotência
+type IdempotencyService interface {
+	Begin(ctx context.Context, key, fingerprint string) (*model.IdempotencyRecord, error)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, headers map[string]string, body []byte) error
+	Release(ctx context.Context, key string) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	repo repository.IdempotencyRepository
+	ttl  time.Duration
+}
+
+// NewIdempotencyService cria uma nova instância do serviço de idempotência.
+// As chaves expiram após o tempo ttl contado a partir da requisição original.
+func NewIdempotencyService(repo repository.IdempotencyRepository, ttl time.Duration) IdempotencyService {
+	return &idempotencyService{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// Begin reserva a chave para a requisição identificada por fingerprint. Retorna nil quando a chave
+// foi reservada e a requisição deve ser processada, ou o registro da requisição original quando
+// se trata de uma repetição. A reserva deve ser encerrada com Complete ou Release.
+func (s *idempotencyService) Begin(ctx context.Context, key, fingerprint string) (*model.IdempotencyRecord, error) {
+	now := time.Now()
+	record := &model.IdempotencyRecord{Key: key, Fingerprint: fingerprint, CreatedAt: now, ExpiresAt: now.Add(s.ttl)}
+
+	reserved, err := s.repo.Reserve(ctx, record)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	existing, err := s.repo.GetByKey(ctx, key)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.IsComplete() {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return existing, nil
+}
+
+// Complete armazena a resposta da requisição que reservou a chave, com os cabeçalhos a devolver nas repetições
+func (s *idempotencyService) Complete(ctx context.Context, key string, statusCode int, contentType string, headers map[string]string, body []byte) error {
+	if err := s.repo.Complete(ctx, key, statusCode, contentType, headers, body); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// Release libera a chave reservada, permitindo que a requisição seja repetida
+func (s *idempotencyService) Release(ctx context.Context, key string) error {
+	if err := s.repo.Delete(ctx, key); err != nil {
+		return databaseError(err)
+	}
+	return nil
+}
+
+// PurgeExpired remove as chaves expiradas e retorna a quantidade removida
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	count, err := s.repo.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return 0, databaseError(err)
+	}
+	return count, nil
+}
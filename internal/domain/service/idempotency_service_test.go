@@ -0,0 +1,142 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	mock_repository "github.com/wandermaia/customer-api/internal/domain/repository/mock"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+)
+
+// Helper para configurar o mock e o serviço de idempotência para cada teste
+func setupIdempotency(t *testing.T, ttl time.Duration) (context.Context, service.IdempotencyService, *mock_repository.MockIdempotencyRepository) {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mock_repository.NewMockIdempotencyRepository(ctrl)
+	idempotencyService := service.NewIdempotencyService(mockRepo, ttl)
+
+	return context.Background(), idempotencyService, mockRepo
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	ttl := 24 * time.Hour
+	ctx, idempotencyService, mockRepo := setupIdempotency(t, ttl)
+	key := "6f1c2a8e-3b7d-4e5f-9a0b-1c2d3e4f5a6b"
+	fingerprint := "fingerprint"
+
+	t.Run("Reserves New Key", func(t *testing.T) {
+		// Expectativa: a chave é reservada com a impressão digital e a validade configurada.
+		mockRepo.EXPECT().
+			Reserve(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, record *model.IdempotencyRecord) (bool, error) {
+				assert.Equal(t, key, record.Key)
+				assert.Equal(t, fingerprint, record.Fingerprint)
+				assert.False(t, record.IsComplete())
+				assert.Equal(t, ttl, record.ExpiresAt.Sub(record.CreatedAt))
+				return true, nil
+			}).Times(1)
+		// Expectativa: a chave recém-reservada não é consultada.
+		mockRepo.EXPECT().GetByKey(gomock.Any(), gomock.Any()).Times(0)
+
+		record, err := idempotencyService.Begin(ctx, key, fingerprint)
+
+		assert.NoError(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("Replays Completed Request", func(t *testing.T) {
+		stored := &model.IdempotencyRecord{Key: key, Fingerprint: fingerprint, StatusCode: 201, ResponseBody: []byte(`{"id":1}`)}
+
+		mockRepo.EXPECT().Reserve(ctx, gomock.Any()).Return(false, nil).Times(1)
+		mockRepo.EXPECT().GetByKey(ctx, key).Return(stored, nil).Times(1)
+
+		record, err := idempotencyService.Begin(ctx, key, fingerprint)
+
+		assert.NoError(t, err)
+		assert.Equal(t, stored, record)
+	})
+
+	t.Run("Key Reused With Different Request", func(t *testing.T) {
+		stored := &model.IdempotencyRecord{Key: key, Fingerprint: "other", StatusCode: 201}
+
+		mockRepo.EXPECT().Reserve(ctx, gomock.Any()).Return(false, nil).Times(1)
+		mockRepo.EXPECT().GetByKey(ctx, key).Return(stored, nil).Times(1)
+
+		record, err := idempotencyService.Begin(ctx, key, fingerprint)
+
+		assert.Equal(t, service.ErrIdempotencyKeyReused, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("Original Request In Progress", func(t *testing.T) {
+		stored := &model.IdempotencyRecord{Key: key, Fingerprint: fingerprint}
+
+		mockRepo.EXPECT().Reserve(ctx, gomock.Any()).Return(false, nil).Times(1)
+		mockRepo.EXPECT().GetByKey(ctx, key).Return(stored, nil).Times(1)
+
+		record, err := idempotencyService.Begin(ctx, key, fingerprint)
+
+		assert.Equal(t, service.ErrIdempotencyKeyInProgress, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		mockRepo.EXPECT().Reserve(ctx, gomock.Any()).Return(false, errors.New("connection refused")).Times(1)
+
+		record, err := idempotencyService.Begin(ctx, key, fingerprint)
+
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.Nil(t, record)
+	})
+}
+
+func TestIdempotencyService_CompleteAndRelease(t *testing.T) {
+	ctx, idempotencyService, mockRepo := setupIdempotency(t, time.Hour)
+	key := "6f1c2a8e-3b7d-4e5f-9a0b-1c2d3e4f5a6b"
+
+	t.Run("Complete", func(t *testing.T) {
+		body := []byte(`{"id":1}`)
+		headers := map[string]string{"ETag": `"1"`}
+		mockRepo.EXPECT().Complete(ctx, key, 201, "application/json", headers, body).Return(nil).Times(1)
+
+		err := idempotencyService.Complete(ctx, key, 201, "application/json", headers, body)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		mockRepo.EXPECT().Delete(ctx, key).Return(nil).Times(1)
+
+		err := idempotencyService.Release(ctx, key)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Purge Expired", func(t *testing.T) {
+		mockRepo.EXPECT().
+			DeleteExpired(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, now time.Time) (int64, error) {
+				assert.WithinDuration(t, time.Now(), now, time.Minute)
+				return 3, nil
+			}).Times(1)
+
+		count, err := idempotencyService.PurgeExpired(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		mockRepo.EXPECT().Delete(ctx, key).Return(errors.New("connection refused")).Times(1)
+
+		err := idempotencyService.Release(ctx, key)
+
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+	})
+}
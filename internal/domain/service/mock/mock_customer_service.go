@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	model "github.com/wandermaia/customer-api/internal/domain/model"
+	service "github.com/wandermaia/customer-api/internal/domain/service"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,18 +43,18 @@ func (m *MockCustomerService) EXPECT() *MockCustomerServiceMockRecorder {
 }
 
 // CountCustomers mocks base method.
-func (m *MockCustomerService) CountCustomers(ctx context.Context) (int64, error) {
+func (m *MockCustomerService) CountCustomers(ctx context.Context, filter model.CustomerFilter) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CountCustomers", ctx)
+	ret := m.ctrl.Call(m, "CountCustomers", ctx, filter)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CountCustomers indicates an expected call of CountCustomers.
-func (mr *MockCustomerServiceMockRecorder) CountCustomers(ctx any) *gomock.Call {
+func (mr *MockCustomerServiceMockRecorder) CountCustomers(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCustomers", reflect.TypeOf((*MockCustomerService)(nil).CountCustomers), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCustomers", reflect.TypeOf((*MockCustomerService)(nil).CountCustomers), ctx, filter)
 }
 
 // CreateCustomer mocks base method.
@@ -70,75 +71,193 @@ func (mr *MockCustomerServiceMockRecorder) CreateCustomer(ctx, customer any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomer", reflect.TypeOf((*MockCustomerService)(nil).CreateCustomer), ctx, customer)
 }
 
+// CreateCustomers mocks base method.
+func (m *MockCustomerService) CreateCustomers(ctx context.Context, customers []*model.Customer, mode service.BatchMode) ([]service.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCustomers", ctx, customers, mode)
+	ret0, _ := ret[0].([]service.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCustomers indicates an expected call of CreateCustomers.
+func (mr *MockCustomerServiceMockRecorder) CreateCustomers(ctx, customers, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomers", reflect.TypeOf((*MockCustomerService)(nil).CreateCustomers), ctx, customers, mode)
+}
+
 // DeleteCustomer mocks base method.
-func (m *MockCustomerService) DeleteCustomer(ctx context.Context, id uint) error {
+func (m *MockCustomerService) DeleteCustomer(ctx context.Context, id, expectedVersion uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCustomer", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteCustomer", ctx, id, expectedVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteCustomer indicates an expected call of DeleteCustomer.
-func (mr *MockCustomerServiceMockRecorder) DeleteCustomer(ctx, id any) *gomock.Call {
+func (mr *MockCustomerServiceMockRecorder) DeleteCustomer(ctx, id, expectedVersion any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomer", reflect.TypeOf((*MockCustomerService)(nil).DeleteCustomer), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomer", reflect.TypeOf((*MockCustomerService)(nil).DeleteCustomer), ctx, id, expectedVersion)
+}
+
+// DeleteCustomers mocks base method.
+func (m *MockCustomerService) DeleteCustomers(ctx context.Context, selection model.BulkSelection, dryRun bool) (*model.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCustomers", ctx, selection, dryRun)
+	ret0, _ := ret[0].(*model.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCustomers indicates an expected call of DeleteCustomers.
+func (mr *MockCustomerServiceMockRecorder) DeleteCustomers(ctx, selection, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomers", reflect.TypeOf((*MockCustomerService)(nil).DeleteCustomers), ctx, selection, dryRun)
+}
+
+// ExportCustomers mocks base method.
+func (m *MockCustomerService) ExportCustomers(ctx context.Context, filter model.CustomerFilter, sort model.Sort, fn func(*model.Customer) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCustomers", ctx, filter, sort, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportCustomers indicates an expected call of ExportCustomers.
+func (mr *MockCustomerServiceMockRecorder) ExportCustomers(ctx, filter, sort, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCustomers", reflect.TypeOf((*MockCustomerService)(nil).ExportCustomers), ctx, filter, sort, fn)
 }
 
 // GetAllCustomers mocks base method.
-func (m *MockCustomerService) GetAllCustomers(ctx context.Context) ([]*model.Customer, error) {
+func (m *MockCustomerService) GetAllCustomers(ctx context.Context, filter model.CustomerFilter, sort model.Sort, pagination model.Pagination) (*model.CustomerPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllCustomers", ctx)
-	ret0, _ := ret[0].([]*model.Customer)
+	ret := m.ctrl.Call(m, "GetAllCustomers", ctx, filter, sort, pagination)
+	ret0, _ := ret[0].(*model.CustomerPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllCustomers indicates an expected call of GetAllCustomers.
-func (mr *MockCustomerServiceMockRecorder) GetAllCustomers(ctx any) *gomock.Call {
+func (mr *MockCustomerServiceMockRecorder) GetAllCustomers(ctx, filter, sort, pagination any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCustomers", reflect.TypeOf((*MockCustomerService)(nil).GetAllCustomers), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllCustomers", reflect.TypeOf((*MockCustomerService)(nil).GetAllCustomers), ctx, filter, sort, pagination)
 }
 
 // GetCustomerByID mocks base method.
-func (m *MockCustomerService) GetCustomerByID(ctx context.Context, id uint) (*model.Customer, error) {
+func (m *MockCustomerService) GetCustomerByID(ctx context.Context, id uint, fields model.FieldSet) (*model.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomerByID", ctx, id)
+	ret := m.ctrl.Call(m, "GetCustomerByID", ctx, id, fields)
 	ret0, _ := ret[0].(*model.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCustomerByID indicates an expected call of GetCustomerByID.
-func (mr *MockCustomerServiceMockRecorder) GetCustomerByID(ctx, id any) *gomock.Call {
+func (mr *MockCustomerServiceMockRecorder) GetCustomerByID(ctx, id, fields any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerByID", reflect.TypeOf((*MockCustomerService)(nil).GetCustomerByID), ctx, id, fields)
+}
+
+// ImportCustomers mocks base method.
+func (m *MockCustomerService) ImportCustomers(ctx context.Context, source service.ImportSource) (*service.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCustomers", ctx, source)
+	ret0, _ := ret[0].(*service.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCustomers indicates an expected call of ImportCustomers.
+func (mr *MockCustomerServiceMockRecorder) ImportCustomers(ctx, source any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCustomers", reflect.TypeOf((*MockCustomerService)(nil).ImportCustomers), ctx, source)
+}
+
+// PatchCustomer mocks base method.
+func (m *MockCustomerService) PatchCustomer(ctx context.Context, id, expectedVersion uint, patchType service.PatchType, patch []byte) (*model.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchCustomer", ctx, id, expectedVersion, patchType, patch)
+	ret0, _ := ret[0].(*model.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchCustomer indicates an expected call of PatchCustomer.
+func (mr *MockCustomerServiceMockRecorder) PatchCustomer(ctx, id, expectedVersion, patchType, patch any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerByID", reflect.TypeOf((*MockCustomerService)(nil).GetCustomerByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCustomer", reflect.TypeOf((*MockCustomerService)(nil).PatchCustomer), ctx, id, expectedVersion, patchType, patch)
 }
 
-// GetCustomersByName mocks base method.
-func (m *MockCustomerService) GetCustomersByName(ctx context.Context, name string) ([]*model.Customer, error) {
+// PurgeCustomer mocks base method.
+func (m *MockCustomerService) PurgeCustomer(ctx context.Context, id uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomersByName", ctx, name)
-	ret0, _ := ret[0].([]*model.Customer)
+	ret := m.ctrl.Call(m, "PurgeCustomer", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeCustomer indicates an expected call of PurgeCustomer.
+func (mr *MockCustomerServiceMockRecorder) PurgeCustomer(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeCustomer", reflect.TypeOf((*MockCustomerService)(nil).PurgeCustomer), ctx, id)
+}
+
+// RestoreCustomer mocks base method.
+func (m *MockCustomerService) RestoreCustomer(ctx context.Context, id uint) (*model.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCustomer", ctx, id)
+	ret0, _ := ret[0].(*model.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCustomersByName indicates an expected call of GetCustomersByName.
-func (mr *MockCustomerServiceMockRecorder) GetCustomersByName(ctx, name any) *gomock.Call {
+// RestoreCustomer indicates an expected call of RestoreCustomer.
+func (mr *MockCustomerServiceMockRecorder) RestoreCustomer(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomersByName", reflect.TypeOf((*MockCustomerService)(nil).GetCustomersByName), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCustomer", reflect.TypeOf((*MockCustomerService)(nil).RestoreCustomer), ctx, id)
+}
+
+// SearchCustomers mocks base method.
+func (m *MockCustomerService) SearchCustomers(ctx context.Context, search model.CustomerSearch, sort model.Sort, pagination model.Pagination) ([]*model.CustomerMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCustomers", ctx, search, sort, pagination)
+	ret0, _ := ret[0].([]*model.CustomerMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCustomers indicates an expected call of SearchCustomers.
+func (mr *MockCustomerServiceMockRecorder) SearchCustomers(ctx, search, sort, pagination any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCustomers", reflect.TypeOf((*MockCustomerService)(nil).SearchCustomers), ctx, search, sort, pagination)
 }
 
 // UpdateCustomer mocks base method.
-func (m *MockCustomerService) UpdateCustomer(ctx context.Context, customer *model.Customer) error {
+func (m *MockCustomerService) UpdateCustomer(ctx context.Context, customer *model.Customer, expectedVersion uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateCustomer", ctx, customer)
+	ret := m.ctrl.Call(m, "UpdateCustomer", ctx, customer, expectedVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateCustomer indicates an expected call of UpdateCustomer.
-func (mr *MockCustomerServiceMockRecorder) UpdateCustomer(ctx, customer any) *gomock.Call {
+func (mr *MockCustomerServiceMockRecorder) UpdateCustomer(ctx, customer, expectedVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomer", reflect.TypeOf((*MockCustomerService)(nil).UpdateCustomer), ctx, customer, expectedVersion)
+}
+
+// UpdateCustomers mocks base method.
+func (m *MockCustomerService) UpdateCustomers(ctx context.Context, selection model.BulkSelection, changes model.CustomerChanges, dryRun bool) (*model.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCustomers", ctx, selection, changes, dryRun)
+	ret0, _ := ret[0].(*model.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCustomers indicates an expected call of UpdateCustomers.
+func (mr *MockCustomerServiceMockRecorder) UpdateCustomers(ctx, selection, changes, dryRun any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomer", reflect.TypeOf((*MockCustomerService)(nil).UpdateCustomer), ctx, customer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomers", reflect.TypeOf((*MockCustomerService)(nil).UpdateCustomers), ctx, selection, changes, dryRun)
 }
@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/domain/service/segment_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/domain/service/segment_service.go -destination=internal/domain/service/mock/mock_segment_service.go -package=mock_service
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/wandermaia/customer-api/internal/domain/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSegmentService is a mock of SegmentService interface.
+type MockSegmentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSegmentServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSegmentServiceMockRecorder is the mock recorder for MockSegmentService.
+type MockSegmentServiceMockRecorder struct {
+	mock *MockSegmentService
+}
+
+// NewMockSegmentService creates a new mock instance.
+func NewMockSegmentService(ctrl *gomock.Controller) *MockSegmentService {
+	mock := &MockSegmentService{ctrl: ctrl}
+	mock.recorder = &MockSegmentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSegmentService) EXPECT() *MockSegmentServiceMockRecorder {
+	return m.recorder
+}
+
+// CountSegmentCustomers mocks base method.
+func (m *MockSegmentService) CountSegmentCustomers(ctx context.Context, id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSegmentCustomers", ctx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSegmentCustomers indicates an expected call of CountSegmentCustomers.
+func (mr *MockSegmentServiceMockRecorder) CountSegmentCustomers(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSegmentCustomers", reflect.TypeOf((*MockSegmentService)(nil).CountSegmentCustomers), ctx, id)
+}
+
+// CreateSegment mocks base method.
+func (m *MockSegmentService) CreateSegment(ctx context.Context, segment *model.Segment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSegment", ctx, segment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSegment indicates an expected call of CreateSegment.
+func (mr *MockSegmentServiceMockRecorder) CreateSegment(ctx, segment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSegment", reflect.TypeOf((*MockSegmentService)(nil).CreateSegment), ctx, segment)
+}
+
+// DeleteSegment mocks base method.
+func (m *MockSegmentService) DeleteSegment(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSegment", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSegment indicates an expected call of DeleteSegment.
+func (mr *MockSegmentServiceMockRecorder) DeleteSegment(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSegment", reflect.TypeOf((*MockSegmentService)(nil).DeleteSegment), ctx, id)
+}
+
+// GetAllSegments mocks base method.
+func (m *MockSegmentService) GetAllSegments(ctx context.Context) ([]*model.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllSegments", ctx)
+	ret0, _ := ret[0].([]*model.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllSegments indicates an expected call of GetAllSegments.
+func (mr *MockSegmentServiceMockRecorder) GetAllSegments(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSegments", reflect.TypeOf((*MockSegmentService)(nil).GetAllSegments), ctx)
+}
+
+// GetSegmentByID mocks base method.
+func (m *MockSegmentService) GetSegmentByID(ctx context.Context, id uint) (*model.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegmentByID", ctx, id)
+	ret0, _ := ret[0].(*model.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegmentByID indicates an expected call of GetSegmentByID.
+func (mr *MockSegmentServiceMockRecorder) GetSegmentByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentByID", reflect.TypeOf((*MockSegmentService)(nil).GetSegmentByID), ctx, id)
+}
+
+// GetSegmentCustomers mocks base method.
+func (m *MockSegmentService) GetSegmentCustomers(ctx context.Context, id uint, fields model.FieldSet, sort model.Sort, pagination model.Pagination) (*model.CustomerPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegmentCustomers", ctx, id, fields, sort, pagination)
+	ret0, _ := ret[0].(*model.CustomerPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegmentCustomers indicates an expected call of GetSegmentCustomers.
+func (mr *MockSegmentServiceMockRecorder) GetSegmentCustomers(ctx, id, fields, sort, pagination any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentCustomers", reflect.TypeOf((*MockSegmentService)(nil).GetSegmentCustomers), ctx, id, fields, sort, pagination)
+}
+
+// UpdateSegment mocks base method.
+func (m *MockSegmentService) UpdateSegment(ctx context.Context, segment *model.Segment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSegment", ctx, segment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSegment indicates an expected call of UpdateSegment.
+func (mr *MockSegmentServiceMockRecorder) UpdateSegment(ctx, segment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSegment", reflect.TypeOf((*MockSegmentService)(nil).UpdateSegment), ctx, segment)
+}
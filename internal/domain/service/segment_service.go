@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/repository"
+)
+
+var (
+	ErrSegmentNotFound = errors.New("segmento não encontrado")
+	ErrInvalidSegment  = errors.New("dados do segmento inválidos")
+)
+
+// SegmentService define as operações de serviço para segmentos de clientes
+type SegmentService interface {
+	CreateSegment(ctx context.Context, segment *model.Segment) error
+	GetSegmentByID(ctx context.Context, id uint) (*model.Segment, error)
+	GetAllSegments(ctx context.Context) ([]*model.Segment, error)
+	UpdateSegment(ctx context.Context, segment *model.Segment) error
+	DeleteSegment(ctx context.Context, id uint) error
+	GetSegmentCustomers(ctx context.Context, id uint, fields model.FieldSet, sort model.Sort, pagination model.Pagination) (*model.CustomerPage, error)
+	CountSegmentCustomers(ctx context.Context, id uint) (int64, error)
+}
+
+type segmentService struct {
+	repo repository.SegmentRepository
+	// customers avalia os segmentos com a mesma listagem, paginação e contagem do serviço de clientes
+	customers CustomerService
+}
+
+// NewSegmentService cria uma nova instância do serviço de segmentos. Os clientes de cada segmento
+// são obtidos do serviço de clientes.
+func NewSegmentService(repo repository.SegmentRepository, customers CustomerService) SegmentService {
+	return &segmentService{
+		repo:      repo,
+		customers: customers,
+	}
+}
+
+// invalidSegment indica que o segmento não passou na validação, mantendo os erros dos campos
+// (validator.ValidationErrors) ou da expressão de filtro (*rsql.Error)
+func invalidSegment(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidSegment, err)
+}
+
+// segmentLookupError converte o erro da busca de um segmento em ErrSegmentNotFound,
+// quando o segmento não existe, ou em uma falha do banco de dados
+func segmentLookupError(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrSegmentNotFound
+	}
+	return databaseError(err)
+}
+
+// validateSegment valida os campos e a expressão de filtro do segmento
+func validateSegment(segment *model.Segment) error {
+	if err := segment.Validate(); err != nil {
+		return invalidSegment(err)
+	}
+	if _, err := segment.CustomerFilter(); err != nil {
+		return invalidSegment(err)
+	}
+	return nil
+}
+
+// CreateSegment cria um novo segmento
+func (s *segmentService) CreateSegment(ctx context.Context, segment *model.Segment) error {
+	if err := validateSegment(segment); err != nil {
+		return err
+	}
+
+	if err := s.repo.Create(ctx, segment); err != nil {
+		return databaseError(err)
+	}
+
+	return nil
+}
+
+// GetSegmentByID busca um segmento pelo ID
+func (s *segmentService) GetSegmentByID(ctx context.Context, id uint) (*model.Segment, error) {
+	segment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, segmentLookupError(err)
+	}
+
+	return segment, nil
+}
+
+// GetAllSegments retorna todos os segmentos
+func (s *segmentService) GetAllSegments(ctx context.Context) ([]*model.Segment, error) {
+	segments, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, databaseError(err)
+	}
+
+	return segments, nil
+}
+
+// UpdateSegment altera o nome e o filtro de um segmento existente
+func (s *segmentService) UpdateSegment(ctx context.Context, segment *model.Segment) error {
+	if err := validateSegment(segment); err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(ctx, segment); err != nil {
+		return segmentLookupError(err)
+	}
+
+	return nil
+}
+
+// DeleteSegment remove um segmento pelo ID. Os clientes do segmento não são alterados.
+func (s *segmentService) DeleteSegment(ctx context.Context, id uint) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return segmentLookupError(err)
+	}
+
+	return nil
+}
+
+// GetSegmentCustomers retorna uma página ordenada dos clientes que atendem ao filtro do segmento,
+// com a mesma paginação por página ou cursor de GetAllCustomers. Quando fields é informado, somente os campos
+// selecionados são lidos.
+func (s *segmentService) GetSegmentCustomers(ctx context.Context, id uint, fields model.FieldSet, sort model.Sort, pagination model.Pagination) (*model.CustomerPage, error) {
+	filter, err := s.segmentFilter(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	filter.Fields = fields
+
+	return s.customers.GetAllCustomers(ctx, filter, sort, pagination)
+}
+
+// CountSegmentCustomers retorna o número de clientes que atendem ao filtro do segmento
+func (s *segmentService) CountSegmentCustomers(ctx context.Context, id uint) (int64, error) {
+	filter, err := s.segmentFilter(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.customers.CountCustomers(ctx, filter)
+}
+
+// segmentFilter busca o segmento e converte sua expressão no filtro de clientes. Expressões armazenadas que
+// deixaram de ser válidas, como as que usam um campo removido do filtro, retornam ErrInvalidSegment.
+func (s *segmentService) segmentFilter(ctx context.Context, id uint) (model.CustomerFilter, error) {
+	segment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return model.CustomerFilter{}, segmentLookupError(err)
+	}
+
+	filter, err := segment.CustomerFilter()
+	if err != nil {
+		return model.CustomerFilter{}, invalidSegment(err)
+	}
+	return filter, nil
+}
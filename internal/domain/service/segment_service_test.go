@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/repository"
+	mock_repository "github.com/wandermaia/customer-api/internal/domain/repository/mock"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+	mock_service "github.com/wandermaia/customer-api/internal/domain/service/mock"
+	"github.com/wandermaia/customer-api/internal/rsql"
+)
+
+// Helper para configurar os mocks e o serviço de segmentos para cada teste
+func setupSegments(t *testing.T) (context.Context, service.SegmentService, *mock_repository.MockSegmentRepository, *mock_service.MockCustomerService) {
+	ctrl := gomock.NewController(t)
+
+	mockSegmentRepo := mock_repository.NewMockSegmentRepository(ctrl)
+	mockCustomerService := mock_service.NewMockCustomerService(ctrl)
+	segmentService := service.NewSegmentService(mockSegmentRepo, mockCustomerService)
+
+	return context.Background(), segmentService, mockSegmentRepo, mockCustomerService
+}
+
+func TestSegmentService_CreateSegment(t *testing.T) {
+	ctx, segmentService, mockSegmentRepo, _ := setupSegments(t)
+
+	t.Run("Success", func(t *testing.T) {
+		segment := &model.Segment{Name: "Ativos da Corp", Filter: "active==true;email=ilike=*@corp.com"}
+
+		// Expectativa: o segmento válido é gravado com a expressão como foi informada.
+		mockSegmentRepo.EXPECT().Create(ctx, segment).Return(nil).Times(1)
+
+		err := segmentService.CreateSegment(ctx, segment)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid Filter Expression", func(t *testing.T) {
+		segment := &model.Segment{Name: "Ativos", Filter: "password==123"}
+
+		// Expectativa: a expressão inválida não é gravada.
+		mockSegmentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		err := segmentService.CreateSegment(ctx, segment)
+
+		assert.ErrorIs(t, err, service.ErrInvalidSegment)
+		var parseErr *rsql.Error
+		if assert.ErrorAs(t, err, &parseErr) {
+			assert.Equal(t, 1, parseErr.Position)
+		}
+	})
+
+	t.Run("Validation Error", func(t *testing.T) {
+		segment := &model.Segment{Name: "AB", Filter: "active==true"}
+
+		mockSegmentRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		err := segmentService.CreateSegment(ctx, segment)
+
+		assert.ErrorIs(t, err, service.ErrInvalidSegment)
+		var validationErrors validator.ValidationErrors
+		if assert.ErrorAs(t, err, &validationErrors) {
+			assert.Equal(t, "name", validationErrors[0].Field())
+		}
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		repoErr := errors.New("db error")
+		mockSegmentRepo.EXPECT().Create(ctx, gomock.Any()).Return(repoErr).Times(1)
+
+		err := segmentService.CreateSegment(ctx, &model.Segment{Name: "Ativos", Filter: "active==true"})
+
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.ErrorIs(t, err, repoErr)
+	})
+}
+
+func TestSegmentService_UpdateSegment(t *testing.T) {
+	ctx, segmentService, mockSegmentRepo, _ := setupSegments(t)
+
+	t.Run("Success", func(t *testing.T) {
+		segment := &model.Segment{ID: 1, Name: "Inativos", Filter: "active==false"}
+		mockSegmentRepo.EXPECT().Update(ctx, segment).Return(nil).Times(1)
+
+		err := segmentService.UpdateSegment(ctx, segment)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockSegmentRepo.EXPECT().Update(ctx, gomock.Any()).Return(repository.ErrNotFound).Times(1)
+
+		err := segmentService.UpdateSegment(ctx, &model.Segment{ID: 2, Name: "Inativos", Filter: "active==false"})
+
+		assert.Equal(t, service.ErrSegmentNotFound, err)
+	})
+}
+
+func TestSegmentService_DeleteSegment(t *testing.T) {
+	ctx, segmentService, mockSegmentRepo, _ := setupSegments(t)
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockSegmentRepo.EXPECT().Delete(ctx, uint(2)).Return(repository.ErrNotFound).Times(1)
+
+		err := segmentService.DeleteSegment(ctx, 2)
+
+		assert.Equal(t, service.ErrSegmentNotFound, err)
+	})
+}
+
+func TestSegmentService_GetSegmentCustomers(t *testing.T) {
+	ctx, segmentService, mockSegmentRepo, mockCustomerService := setupSegments(t)
+
+	t.Run("Evaluates Stored Filter", func(t *testing.T) {
+		segment := &model.Segment{ID: 1, Name: "Ativos", Filter: "active==true"}
+		expectedPage := &model.CustomerPage{Items: []*model.Customer{{ID: 1, Name: "Ana", Active: true}}}
+		expectedFilter := model.CustomerFilter{
+			Expression: &rsql.Comparison{Field: "active", Operator: rsql.Equal, Values: []any{true}},
+			Fields:     model.FieldSet{"id", "name"},
+		}
+		pagination := model.Pagination{Page: 2, PageSize: 10}
+
+		mockSegmentRepo.EXPECT().GetByID(ctx, uint(1)).Return(segment, nil).Times(1)
+		// Expectativa: os clientes são listados pelo serviço de clientes com a expressão do segmento, os campos,
+		// a ordenação e a paginação informados.
+		mockCustomerService.EXPECT().
+			GetAllCustomers(ctx, expectedFilter, model.Sort{{Field: "name"}}, pagination).
+			Return(expectedPage, nil).
+			Times(1)
+
+		page, err := segmentService.GetSegmentCustomers(ctx, 1, model.FieldSet{"id", "name"}, model.Sort{{Field: "name"}}, pagination)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPage, page)
+	})
+
+	t.Run("Segment Not Found", func(t *testing.T) {
+		mockSegmentRepo.EXPECT().GetByID(ctx, uint(2)).Return(nil, repository.ErrNotFound).Times(1)
+		mockCustomerService.EXPECT().GetAllCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		page, err := segmentService.GetSegmentCustomers(ctx, 2, nil, nil, model.Pagination{})
+
+		assert.Equal(t, service.ErrSegmentNotFound, err)
+		assert.Nil(t, page)
+	})
+
+	t.Run("Stored Filter No Longer Valid", func(t *testing.T) {
+		segment := &model.Segment{ID: 3, Name: "Antigo", Filter: "document==123"}
+		mockSegmentRepo.EXPECT().GetByID(ctx, uint(3)).Return(segment, nil).Times(1)
+
+		_, err := segmentService.GetSegmentCustomers(ctx, 3, nil, nil, model.Pagination{})
+
+		assert.ErrorIs(t, err, service.ErrInvalidSegment)
+	})
+}
+
+func TestSegmentService_CountSegmentCustomers(t *testing.T) {
+	ctx, segmentService, mockSegmentRepo, mockCustomerService := setupSegments(t)
+
+	t.Run("Success", func(t *testing.T) {
+		segment := &model.Segment{ID: 1, Name: "Corp", Filter: "email=ilike=*@corp.com"}
+		expectedFilter := model.CustomerFilter{Expression: &rsql.Comparison{Field: "email", Operator: rsql.ILike, Values: []any{"*@corp.com"}}}
+
+		mockSegmentRepo.EXPECT().GetByID(ctx, uint(1)).Return(segment, nil).Times(1)
+		// Expectativa: a contagem usa a expressão do segmento.
+		mockCustomerService.EXPECT().CountCustomers(ctx, expectedFilter).Return(int64(7), nil).Times(1)
+
+		count, err := segmentService.CountSegmentCustomers(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), count)
+	})
+
+	t.Run("Customer Service Error", func(t *testing.T) {
+		serviceErr := fmt.Errorf("%w: %w", service.ErrDatabaseOperation, errors.New("failed to count"))
+		mockSegmentRepo.EXPECT().GetByID(ctx, uint(1)).Return(&model.Segment{ID: 1, Name: "Corp", Filter: "active==true"}, nil).Times(1)
+		mockCustomerService.EXPECT().CountCustomers(ctx, gomock.Any()).Return(int64(0), serviceErr).Times(1)
+
+		count, err := segmentService.CountSegmentCustomers(ctx, 1)
+
+		assert.ErrorIs(t, err, service.ErrDatabaseOperation)
+		assert.Equal(t, int64(0), count)
+	})
+}
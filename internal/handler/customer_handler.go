@@ -1,15 +1,27 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
 
 	"github.com/wandermaia/customer-api/internal/domain/model"
 	"github.com/wandermaia/customer-api/internal/domain/service"
+	"github.com/wandermaia/customer-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Tipos de mídia aceitos pelo endpoint PATCH
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
 type CustomerHandler struct {
 	service service.CustomerService
 }
@@ -21,197 +33,878 @@ func NewCustomerHandler(service service.CustomerService) *CustomerHandler {
 	}
 }
 
-// RegisterRoutes registra as rotas do handler no router do Gin
-func (h *CustomerHandler) RegisterRoutes(router *gin.Engine) {
+// RegisterRoutes registra as rotas do handler no router do Gin. createMiddleware é executado somente na criação
+// de um cliente (POST /api/customers), como o middleware de idempotência.
+func (h *CustomerHandler) RegisterRoutes(router *gin.Engine, createMiddleware ...gin.HandlerFunc) {
 	customers := router.Group("/api/customers")
 	{
-		customers.POST("", h.CreateCustomer)
-		customers.GET("", h.GetAllCustomers)
-		customers.GET("/count", h.CountCustomers)
-		customers.GET("/:id", h.GetCustomerByID)
-		customers.GET("/search", h.GetCustomersByName)
-		customers.PUT("/:id", h.UpdateCustomer)
-		customers.DELETE("/:id", h.DeleteCustomer)
+		// A importação e a exportação usam formatos próprios, CSV e NDJSON
+		customers.POST("/import", h.ImportCustomers)
+		customers.GET("/export", h.ExportCustomers)
+	}
+
+	// As demais rotas negociam a representação pelos cabeçalhos Accept e Content-Type
+	negotiated := customers.Group("", negotiateContent)
+	{
+		negotiated.POST("", append(createMiddleware, h.CreateCustomer)...)
+		negotiated.POST("/batch", h.CreateCustomers)
+		negotiated.GET("", h.GetAllCustomers)
+		negotiated.GET("/count", h.CountCustomers)
+		negotiated.GET("/:id", h.GetCustomerByID)
+		negotiated.GET("/search", h.SearchCustomers)
+		negotiated.PUT("/:id", h.UpdateCustomer)
+		negotiated.PATCH("/:id", h.PatchCustomer)
+		negotiated.DELETE("/:id", h.DeleteCustomer)
+		negotiated.PATCH("/bulk", h.UpdateCustomers)
+		negotiated.DELETE("/bulk", h.DeleteCustomers)
+		negotiated.POST("/:id/restore", h.RestoreCustomer)
+		negotiated.DELETE("/:id/purge", h.PurgeCustomer)
 	}
 }
 
 // CreateCustomer cria um novo cliente
 // @Summary Criar um novo cliente
-// @Description Cria um novo cliente com os dados fornecidos
+// @Description Cria um novo cliente com os dados fornecidos.
+// @Description Com o cabeçalho Idempotency-Key, repetições da mesma requisição retornam a resposta original sem criar outro cliente.
+// @Description Um email já utilizado por outro cliente, sem distinção entre maiúsculas e minúsculas, retorna 409 com o campo em conflito.
 // @Tags customers
-// @Accept json
-// @Produce json
-// @Param customer body model.Customer true "Dados do cliente"
-// @Success 201 {object} model.Customer
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param Idempotency-Key header string false "Chave que identifica a requisição para repetições seguras"
+// @Param customer body CreateCustomerRequest true "Dados do cliente"
+// @Success 201 {object} CustomerResponse
+// @Header 201 {string} ETag "Versão do cliente"
+// @Header 201 {string} Idempotent-Replayed "Presente quando a resposta é a da requisição original"
+// @Failure 400 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 409 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Failure 422 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
 // @Router /customers [post]
 func (h *CustomerHandler) CreateCustomer(c *gin.Context) {
-	var customer model.Customer
-	if err := c.ShouldBindJSON(&customer); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+	var request CreateCustomerRequest
+	if err := bindBody(c, &request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	customer := request.toModel()
+	if err := h.service.CreateCustomer(c.Request.Context(), customer); err != nil {
+		if errors.Is(err, service.ErrInvalidCustomer) {
+			respondInvalidCustomer(c, err)
+			return
+		}
+		var conflict *service.AlreadyExistsError
+		if errors.As(err, &conflict) {
+			respondAlreadyExists(c, conflict)
+			return
+		}
+		respondServerError(c, err, "Erro ao criar cliente")
+		return
+	}
+
+	setETag(c, customer)
+	respond(c, http.StatusCreated, newCustomerResponse(customer))
+}
+
+// CreateCustomers cria clientes em lote
+// @Summary Criar clientes em lote
+// @Description Valida e cria até 1000 clientes, inseridos em lotes, e retorna 207 com o resultado de cada cliente na ordem enviada.
+// @Description No modo all_or_nothing (padrão), a falha de qualquer cliente impede a criação de todos e os demais recebem o status 424.
+// @Description No modo best_effort, os clientes válidos são criados mesmo que outros falhem.
+// @Tags customers
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param batch body BatchCreateRequest true "Clientes e modo de criação"
+// @Success 207 {object} BatchCreateResponse
+// @Failure 400 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 413 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /customers/batch [post]
+func (h *CustomerHandler) CreateCustomers(c *gin.Context) {
+	var request BatchCreateRequest
+	if err := bindBody(c, &request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	mode, err := service.ParseBatchMode(request.Mode)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err := h.service.CreateCustomer(c.Request.Context(), &customer); err != nil {
-		if err == service.ErrInvalidCustomer {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	results, err := h.service.CreateCustomers(c.Request.Context(), request.toModels(), mode)
+	if err != nil {
+		if errors.Is(err, service.ErrEmptyBatch) {
+			respondProblem(c, http.StatusBadRequest, err.Error())
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar cliente"})
+		if errors.Is(err, service.ErrBatchTooLarge) {
+			respondProblem(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("%s (%d)", err.Error(), service.MaxBatchSize))
+			return
+		}
+		respondServerError(c, err, "Erro ao criar clientes em lote")
 		return
 	}
 
-	c.JSON(http.StatusCreated, customer)
+	response := BatchCreateResponse{Mode: string(mode), Results: make([]BatchItemResponse, len(results))}
+	for i, result := range results {
+		item := BatchItemResponse{Index: i, Status: http.StatusCreated}
+		if result.Err != nil {
+			item.Error = batchItemProblem(c, i, result.Err)
+			item.Status = item.Error.Status
+			response.Failed++
+		} else {
+			customer := newCustomerResponse(result.Customer)
+			item.Customer = &customer
+			response.Created++
+		}
+		response.Results[i] = item
+	}
+
+	respond(c, http.StatusMultiStatus, response)
+}
+
+// ImportCustomers importa clientes de um arquivo CSV
+// @Summary Importar clientes de CSV
+// @Description Importa os clientes de um arquivo CSV cujo cabeçalho indica as colunas, em qualquer ordem: name e email (obrigatórias), phone, address e active (padrão true).
+// @Description Cada linha é validada e as válidas são inseridas em lotes; linhas inválidas ou com email já cadastrado são rejeitadas sem impedir a importação das demais.
+// @Description A resposta é um relatório CSV das linhas rejeitadas, com o número da linha no arquivo e o motivo.
+// @Tags customers
+// @Accept text/csv
+// @Produce text/csv
+// @Param file body string true "Arquivo CSV com linha de cabeçalho"
+// @Success 200 {file} file "Relatório das linhas rejeitadas (line,reason)"
+// @Header 200 {int} X-Imported-Count "Quantidade de clientes importados"
+// @Header 200 {int} X-Rejected-Count "Quantidade de linhas rejeitadas"
+// @Failure 400 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /customers/import [post]
+func (h *CustomerHandler) ImportCustomers(c *gin.Context) {
+	if c.ContentType() != csvContentType {
+		respondProblem(c, http.StatusUnsupportedMediaType, "Content-Type não suportado, utilize "+csvContentType)
+		return
+	}
+
+	source, err := newCSVImportSource(c.Request.Body)
+	if err != nil {
+		var header *headerError
+		if errors.As(err, &header) {
+			problem := utils.NewProblem(http.StatusBadRequest, err.Error())
+			problem.AllowedValues = importColumns
+			utils.RespondProblem(c, problem)
+			return
+		}
+		if errors.Is(err, errMissingHeader) {
+			respondProblem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondProblem(c, http.StatusBadRequest, "Cabeçalho do arquivo CSV inválido")
+		return
+	}
+
+	report, err := h.service.ImportCustomers(c.Request.Context(), source)
+	if err != nil {
+		if errors.Is(err, service.ErrImportRead) {
+			respondProblem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao importar clientes")
+		return
+	}
+
+	c.Header("Content-Type", csvContentType+"; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="import-report.csv"`)
+	c.Header("X-Imported-Count", strconv.Itoa(report.Imported))
+	c.Header("X-Rejected-Count", strconv.Itoa(len(report.Rejected)))
+	c.Status(http.StatusOK)
+	if err := writeImportReport(c.Writer, report.Rejected); err != nil {
+		log.Printf("%s %s: erro ao escrever o relatório da importação: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
 }
 
 // GetCustomerByID busca um cliente pelo ID
 // @Summary Buscar cliente por ID
-// @Description Retorna os dados de um cliente específico com base no ID
+// @Description Retorna os dados de um cliente específico com base no ID.
+// @Description Com os cabeçalhos If-None-Match ou If-Modified-Since, retorna 304 quando o cliente não foi alterado.
 // @Tags customers
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param id path int true "ID do Cliente"
-// @Success 200 {object} model.Customer
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param fields query string false "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos"
+// @Param If-None-Match header string false "ETag recebida anteriormente"
+// @Param If-Modified-Since header string false "Valor de Last-Modified recebido anteriormente"
+// @Success 200 {object} CustomerResponse
+// @Success 304 "Not Modified"
+// @Header 200 {string} ETag "Versão e representação do cliente (ex: 4-json)"
+// @Header 200 {string} Last-Modified "Data da última alteração do cliente"
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
 // @Router /customers/{id} [get]
 func (h *CustomerHandler) GetCustomerByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	fields, err := model.ParseFields(c.Query("fields"))
+	if err != nil {
+		respondInvalidFields(c, err)
 		return
 	}
 
-	customer, err := h.service.GetCustomerByID(c.Request.Context(), uint(id))
+	customer, err := h.service.GetCustomerByID(c.Request.Context(), uint(id), fields)
 	if err != nil {
-		if err == service.ErrCustomerNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar cliente"})
+		respondServerError(c, err, "Erro ao buscar cliente")
 		return
 	}
 
-	c.JSON(http.StatusOK, customer)
+	if notModified(c, customerETag(c, customer, fields), customer.UpdatedAt) {
+		return
+	}
+	respond(c, http.StatusOK, withFields(newCustomerResponse(customer), fields))
 }
 
-// GetAllCustomers retorna todos os clientes
-// @Summary Listar todos os clientes
-// @Description Retorna uma lista com todos os clientes cadastrados
+// GetAllCustomers retorna os clientes de forma paginada
+// @Summary Listar clientes
+// @Description Retorna uma página de clientes cadastrados, ordenados por data de criação. Os filtros informados são combinados com AND. Os links para as páginas seguinte e anterior são enviados no cabeçalho Link.
+// @Description Para percorrer toda a base de forma estável, utilize o cursor retornado em next_cursor.
+// @Description O parâmetro filter aceita expressões RSQL sobre os campos id, name, email, phone, address, active, created_at e updated_at, com os operadores ==, !=, =lt= (<), =le= (<=), =gt= (>), =ge= (>=), =in=, =out=, =like= e =ilike=; nos dois últimos, * representa qualquer sequência de caracteres.
+// @Description Valores com espaços ou caracteres reservados devem ficar entre aspas. Expressões inválidas retornam 400 com a posição do erro em position.
+// @Description Com o cabeçalho If-None-Match, retorna 304 quando o conteúdo da página não mudou. If-Modified-Since considera apenas a alteração mais recente entre os clientes da página e não detecta exclusões.
 // @Tags customers
 // @Accept json
-// @Produce json
-// @Success 200 {array} model.Customer
-// @Failure 500 {object} map[string]string
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param page query int false "Número da página" default(1)
+// @Param page_size query int false "Quantidade de registros por página (máximo 100)" default(20)
+// @Param cursor query string false "Cursor opaco retornado em next_cursor (paginação por keyset)"
+// @Param sort query string false "Campos de ordenação separados por vírgula; prefixo - para ordem decrescente (ex: -created_at,name)"
+// @Param active query bool false "Filtra clientes ativos ou inativos"
+// @Param created_after query string false "Criados após a data (RFC 3339 ou AAAA-MM-DD)"
+// @Param created_before query string false "Criados antes da data (RFC 3339 ou AAAA-MM-DD)"
+// @Param updated_since query string false "Atualizados desde a data (RFC 3339 ou AAAA-MM-DD)"
+// @Param email_domain query string false "Domínio do email (ex: example.com)"
+// @Param phone_prefix query string false "Prefixo numérico do telefone (ex: 11)"
+// @Param filter query string false "Expressão de filtro RSQL: comparações unidas por ; (AND) e , (OR), com parênteses (ex: active==true;(name=like=Silva*,email=like=*@corp.com))"
+// @Param include_deleted query bool false "Inclui os clientes excluídos logicamente (uso administrativo)"
+// @Param fields query string false "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos"
+// @Param If-None-Match header string false "ETag recebida anteriormente"
+// @Param If-Modified-Since header string false "Valor de Last-Modified recebido anteriormente"
+// @Success 200 {object} CustomerPageResponse
+// @Success 304 "Not Modified"
+// @Header 200 {string} Link "Links para as páginas seguinte e anterior (RFC 8288)"
+// @Header 200 {string} ETag "Hash do conteúdo da página"
+// @Header 200 {string} Last-Modified "Data da alteração mais recente entre os clientes da página"
+// @Failure 400 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
 // @Router /customers [get]
 func (h *CustomerHandler) GetAllCustomers(c *gin.Context) {
-	customers, err := h.service.GetAllCustomers(c.Request.Context())
+	pagination, err := parsePagination(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar clientes"})
+		respondProblem(c, http.StatusBadRequest, "Parâmetros de paginação inválidos")
+		return
+	}
+
+	filter, err := parseCustomerFilter(c)
+	if err != nil {
+		respondInvalidFilter(c, err)
+		return
+	}
+
+	if filter.Fields, err = model.ParseFields(c.Query("fields")); err != nil {
+		respondInvalidFields(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, customers)
+	sort, err := model.ParseSort(c.Query("sort"))
+	if err != nil {
+		respondInvalidSort(c, err, model.SortableCustomerFields)
+		return
+	}
+
+	page, err := h.service.GetAllCustomers(c.Request.Context(), filter, sort, pagination)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			respondProblem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao buscar clientes")
+		return
+	}
+
+	body, err := encodeResponse(c, withFields(newCustomerPageResponse(page), filter.Fields))
+	if err != nil {
+		respondServerError(c, err, "Erro ao buscar clientes")
+		return
+	}
+
+	setLinkHeader(c, page)
+	if notModified(c, contentETag(body), page.LastModified()) {
+		return
+	}
+	c.Data(http.StatusOK, negotiatedRepresentation(c).contentType, body)
+}
+
+// ExportCustomers exporta todos os clientes que atendem aos filtros em CSV ou NDJSON
+// @Summary Exportar clientes
+// @Description Exporta todos os clientes que atendem aos filtros, sem paginação, no formato informado (csv, padrão, ou ndjson).
+// @Description Os clientes são lidos do banco de dados com um cursor e escritos na resposta à medida que são lidos, com uso de memória constante.
+// @Description Os filtros e a ordenação são os mesmos da listagem.
+// @Tags customers
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "Formato da exportação" Enums(csv, ndjson)
+// @Param sort query string false "Campos de ordenação separados por vírgula; prefixo - para ordem decrescente (ex: -created_at,name)"
+// @Param active query bool false "Filtra clientes ativos ou inativos"
+// @Param created_after query string false "Criados após a data (RFC 3339 ou AAAA-MM-DD)"
+// @Param created_before query string false "Criados antes da data (RFC 3339 ou AAAA-MM-DD)"
+// @Param updated_since query string false "Atualizados desde a data (RFC 3339 ou AAAA-MM-DD)"
+// @Param email_domain query string false "Domínio do email (ex: example.com)"
+// @Param phone_prefix query string false "Prefixo numérico do telefone (ex: 11)"
+// @Param filter query string false "Expressão de filtro RSQL: comparações unidas por ; (AND) e , (OR), com parênteses (ex: active==true;(name=like=Silva*,email=like=*@corp.com))"
+// @Param include_deleted query bool false "Inclui os clientes excluídos logicamente (uso administrativo)"
+// @Param fields query string false "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos"
+// @Success 200 {file} file "Clientes exportados"
+// @Failure 400 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /customers/export [get]
+func (h *CustomerHandler) ExportCustomers(c *gin.Context) {
+	filter, err := parseCustomerFilter(c)
+	if err != nil {
+		respondInvalidFilter(c, err)
+		return
+	}
+
+	if filter.Fields, err = model.ParseFields(c.Query("fields")); err != nil {
+		respondInvalidFields(c, err)
+		return
+	}
+
+	writer, err := newCustomerWriter(c.Query("format"), c.Writer, filter.Fields)
+	if err != nil {
+		problem := utils.NewProblem(http.StatusBadRequest, err.Error())
+		problem.AllowedValues = exportFormats
+		utils.RespondProblem(c, problem)
+		return
+	}
+
+	sort, err := model.ParseSort(c.Query("sort"))
+	if err != nil {
+		respondInvalidSort(c, err, model.SortableCustomerFields)
+		return
+	}
+
+	// A resposta só é iniciada com o primeiro cliente lido, para que falhas na consulta ainda possam ser
+	// respondidas como problema
+	started := false
+	begin := func() error {
+		started = true
+		c.Header("Content-Type", writer.ContentType())
+		c.Header("Content-Disposition", `attachment; filename="customers.`+writer.Extension()+`"`)
+		c.Status(http.StatusOK)
+		return writer.Begin()
+	}
+
+	exported := 0
+	err = h.service.ExportCustomers(c.Request.Context(), filter, sort, func(customer *model.Customer) error {
+		if !started {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(newCustomerResponse(customer)); err != nil {
+			return err
+		}
+
+		exported++
+		if exported%exportFlushInterval == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		err = begin()
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if err != nil {
+		if !started {
+			respondServerError(c, err, "Erro ao exportar clientes")
+			return
+		}
+		// Com a resposta já iniciada, a falha não pode mais ser informada ao cliente, que recebe o conteúdo incompleto.
+		// O cliente que cancelou a requisição não é uma falha do servidor.
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		log.Printf("%s %s: exportação interrompida após %d clientes: %v", c.Request.Method, c.Request.URL.Path, exported, err)
+	}
 }
 
-// GetCustomersByName busca clientes pelo nome
-// @Summary Buscar clientes por nome
-// @Description Retorna uma lista de clientes que correspondem ao nome fornecido
+// SearchCustomers busca clientes pelo nome, email, telefone ou endereço, ou por busca textual
+// @Summary Buscar clientes
+// @Description Retorna os clientes que atendem a todos os termos informados; ao menos um de q, name, email, phone ou fulltext é obrigatório.
+// @Description O parâmetro q é procurado no nome, no email, no endereço e, quando tem a forma de um telefone com ao menos 3 dígitos, nos dígitos do telefone.
+// @Description Nome e endereço são comparados por semelhança de trigramas, de 0 a 1, sem diferenciar maiúsculas, minúsculas e acentos (ex: Joao encontra João); somente pontuações de ao menos min_score são consideradas.
+// @Description Email e telefone são encontrados quando contêm o termo, com pontuação 1; o telefone é comparado apenas pelos dígitos, ignorando a formatação.
+// @Description O parâmetro fulltext faz uma busca textual em português no nome e no endereço, com a sintaxe de websearch_to_tsquery ("frases entre aspas", OR e -exclusão), comparando os radicais das palavras.
+// @Description Seus resultados são pontuados por ts_rank, de 0 a 1, e trazem em headline o trecho do nome e do endereço com os termos destacados entre <b> e </b>.
+// @Description Cada resultado indica em matched_field o campo de maior pontuação. Sem o parâmetro sort, os clientes são ordenados da maior para a menor pontuação.
+// @Description Os resultados são paginados por page e page_size, com no máximo 100 clientes por página; a paginação por cursor não é aceita.
 // @Tags customers
 // @Accept json
-// @Produce json
-// @Param name query string true "Nome do cliente"
-// @Success 200 {array} model.Customer
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param q query string false "Termo procurado no nome, email, telefone e endereço"
+// @Param name query string false "Nome do cliente"
+// @Param email query string false "Parte do email do cliente"
+// @Param phone query string false "Parte do telefone do cliente, com ao menos 3 dígitos; somente os dígitos são considerados"
+// @Param fulltext query string false "Busca textual no nome e no endereço, na sintaxe de websearch_to_tsquery"
+// @Param min_score query number false "Pontuação mínima de semelhança do nome e do endereço, maior que 0 e no máximo 1" default(0.3)
+// @Param sort query string false "Campos de ordenação separados por vírgula; prefixo - para ordem decrescente (ex: name,-created_at)"
+// @Param fields query string false "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos"
+// @Param page query int false "Número da página" default(1)
+// @Param page_size query int false "Quantidade de registros por página (máximo 100)" default(20)
+// @Success 200 {array} CustomerSearchResult
+// @Failure 400 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
 // @Router /customers/search [get]
-func (h *CustomerHandler) GetCustomersByName(c *gin.Context) {
-	name := c.Query("name")
-	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Nome não fornecido"})
+func (h *CustomerHandler) SearchCustomers(c *gin.Context) {
+	search, err := parseCustomerSearch(c)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if search.Fields, err = model.ParseFields(c.Query("fields")); err != nil {
+		respondInvalidFields(c, err)
 		return
 	}
 
-	customers, err := h.service.GetCustomersByName(c.Request.Context(), name)
+	sort, err := model.ParseSort(c.Query("sort"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar clientes"})
+		respondInvalidSort(c, err, model.SortableCustomerFields)
+		return
+	}
+
+	pagination, err := parsePagination(c)
+	if err != nil || pagination.Cursor != "" {
+		respondProblem(c, http.StatusBadRequest, errInvalidPagination.Error())
+		return
+	}
+
+	matches, err := h.service.SearchCustomers(c.Request.Context(), search, sort, pagination)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMinScore) {
+			respondProblem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao buscar clientes")
 		return
 	}
 
-	c.JSON(http.StatusOK, customers)
+	respond(c, http.StatusOK, withFields(newCustomerSearchResults(matches), search.Fields))
 }
 
 // UpdateCustomer atualiza um cliente existente
 // @Summary Atualizar cliente
-// @Description Atualiza os dados de um cliente existente
+// @Description Atualiza os dados de um cliente existente. Com o cabeçalho If-Match, a alteração só é aplicada se o cliente ainda estiver na versão informada.
+// @Description Um email já utilizado por outro cliente retorna 409 com o campo em conflito.
 // @Tags customers
-// @Accept json
-// @Produce json
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param id path int true "ID do Cliente"
-// @Param customer body model.Customer true "Dados atualizados do cliente"
-// @Success 200 {object} model.Customer
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Param If-Match header string false "ETag da versão esperada do cliente"
+// @Param customer body UpdateCustomerRequest true "Dados atualizados do cliente"
+// @Success 200 {object} CustomerResponse
+// @Header 200 {string} ETag "Nova versão do cliente"
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 409 {object} utils.Problem
+// @Failure 412 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
 // @Router /customers/{id} [put]
 func (h *CustomerHandler) UpdateCustomer(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
 		return
 	}
 
-	var customer model.Customer
-	if err := c.ShouldBindJSON(&customer); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		respondIfMatchError(c, err)
 		return
 	}
 
-	customer.ID = uint(id)
+	var request UpdateCustomerRequest
+	if err := bindBody(c, &request); err != nil {
+		respondBindError(c, err)
+		return
+	}
 
-	if err := h.service.UpdateCustomer(c.Request.Context(), &customer); err != nil {
-		if err == service.ErrInvalidCustomer {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	customer := request.toModel(uint(id))
+	if err := h.service.UpdateCustomer(c.Request.Context(), customer, version); err != nil {
+		if errors.Is(err, service.ErrInvalidCustomer) {
+			respondInvalidCustomer(c, err)
+			return
+		}
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			respondProblem(c, http.StatusPreconditionFailed, err.Error())
 			return
 		}
-		if err == service.ErrCustomerNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		var conflict *service.AlreadyExistsError
+		if errors.As(err, &conflict) {
+			respondAlreadyExists(c, conflict)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar cliente"})
+		respondServerError(c, err, "Erro ao atualizar cliente")
 		return
 	}
 
-	c.JSON(http.StatusOK, customer)
+	setETag(c, customer)
+	respond(c, http.StatusOK, newCustomerResponse(customer))
 }
 
-// DeleteCustomer remove um cliente pelo ID
+// PatchCustomer atualiza parcialmente um cliente existente
+// @Summary Atualizar cliente parcialmente
+// @Description Aplica um JSON Merge Patch (RFC 7396) ou um JSON Patch (RFC 6902) à representação do cliente retornada pela API, conforme o Content-Type.
+// @Description Somente name, email, phone, address e active podem ser alterados; patches sobre id, created_at, updated_at, deleted_at ou campos fora da representação retornam 400. A operação test pode comparar qualquer campo.
+// @Description No Merge Patch, somente os campos presentes no documento são alterados; phone e address com valor null são removidos, e null em name, email ou active retorna 400, assim como a operação remove sobre eles no JSON Patch.
+// @Description No JSON Patch, as operações são aplicadas em uma única transação e uma operação test não satisfeita retorna 409.
+// @Description Um email já utilizado por outro cliente também retorna 409, com o campo em conflito.
+// @Tags customers
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "ID do Cliente"
+// @Param If-Match header string false "ETag da versão esperada do cliente"
+// @Param patch body object true "Documento JSON Merge Patch ou lista de operações JSON Patch"
+// @Success 200 {object} CustomerResponse
+// @Header 200 {string} ETag "Nova versão do cliente"
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 409 {object} utils.Problem
+// @Failure 412 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /customers/{id} [patch]
+func (h *CustomerHandler) PatchCustomer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		respondIfMatchError(c, err)
+		return
+	}
+
+	var patchType service.PatchType
+	switch c.ContentType() {
+	case mergePatchContentType:
+		patchType = service.MergePatch
+	case jsonPatchContentType:
+		patchType = service.JSONPatch
+	default:
+		respondProblem(c, http.StatusUnsupportedMediaType, "Content-Type não suportado, utilize "+mergePatchContentType+" ou "+jsonPatchContentType)
+		return
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Dados inválidos")
+		return
+	}
+
+	customer, err := h.service.PatchCustomer(c.Request.Context(), uint(id), version, patchType, patch)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPatch) {
+			respondProblem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCustomer) {
+			respondInvalidCustomer(c, err)
+			return
+		}
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrPatchTestFailed) {
+			respondProblem(c, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			respondProblem(c, http.StatusPreconditionFailed, err.Error())
+			return
+		}
+		var conflict *service.AlreadyExistsError
+		if errors.As(err, &conflict) {
+			respondAlreadyExists(c, conflict)
+			return
+		}
+		respondServerError(c, err, "Erro ao atualizar cliente")
+		return
+	}
+
+	setETag(c, customer)
+	respond(c, http.StatusOK, newCustomerResponse(customer))
+}
+
+// DeleteCustomer exclui logicamente um cliente pelo ID
 // @Summary Excluir cliente
-// @Description Remove um cliente do sistema com base no ID
+// @Description Exclui logicamente um cliente com base no ID. O cliente deixa de ser retornado pela API, mas seu histórico é mantido e ele pode ser restaurado.
+// @Description Com o cabeçalho If-Match, a exclusão só é aplicada se o cliente ainda estiver na versão informada.
 // @Tags customers
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param id path int true "ID do Cliente"
+// @Param If-Match header string false "ETag da versão esperada do cliente"
 // @Success 204 "No Content"
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 412 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
 // @Router /customers/{id} [delete]
 func (h *CustomerHandler) DeleteCustomer(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		respondIfMatchError(c, err)
+		return
+	}
+
+	if err := h.service.DeleteCustomer(c.Request.Context(), uint(id), version); err != nil {
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			respondProblem(c, http.StatusPreconditionFailed, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao excluir cliente")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// UpdateCustomers altera campos de vários clientes de uma vez
+// @Summary Atualizar clientes em massa
+// @Description Aplica as alterações de set aos clientes selecionados pela lista de IDs (até 1000) ou pelo filtro, em uma única transação.
+// @Description O filtro aceita, em expression, uma expressão RSQL como a do parâmetro filter da listagem; expressões inválidas retornam 400 com a posição do erro em position.
+// @Description Clientes cujos valores já correspondem às alterações são contados em matched, mas não são alterados.
+// @Description Com dry_run=true, nada é alterado e a resposta traz as contagens que a operação teria.
+// @Tags customers
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param dry_run query bool false "Simula a operação sem alterar os clientes"
+// @Param bulk body BulkUpdateRequest true "Seleção dos clientes e campos alterados"
+// @Success 200 {object} BulkResultResponse
+// @Failure 400 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /customers/bulk [patch]
+func (h *CustomerHandler) UpdateCustomers(c *gin.Context) {
+	dryRun, err := parseDryRun(c)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request BulkUpdateRequest
+	if err := bindBody(c, &request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	selection, changes, err := request.toModel()
+	if err != nil {
+		respondInvalidFilter(c, err)
+		return
+	}
+
+	result, err := h.service.UpdateCustomers(c.Request.Context(), selection, changes, dryRun)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBulkSelection) || errors.Is(err, service.ErrBulkSelectionTooLarge) ||
+			errors.Is(err, service.ErrNoChanges) {
+			respondProblem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao atualizar clientes")
+		return
+	}
+
+	respond(c, http.StatusOK, newBulkResultResponse(result))
+}
+
+// DeleteCustomers exclui logicamente vários clientes de uma vez
+// @Summary Excluir clientes em massa
+// @Description Exclui logicamente os clientes selecionados pela lista de IDs (até 1000) ou pelo filtro, em uma única transação.
+// @Description O filtro aceita, em expression, uma expressão RSQL como a do parâmetro filter da listagem; expressões inválidas retornam 400 com a posição do erro em position.
+// @Description Com dry_run=true, nada é excluído e a resposta traz a quantidade de clientes que seriam excluídos.
+// @Tags customers
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param dry_run query bool false "Simula a operação sem excluir os clientes"
+// @Param bulk body BulkDeleteRequest true "Seleção dos clientes"
+// @Success 200 {object} BulkResultResponse
+// @Failure 400 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /customers/bulk [delete]
+func (h *CustomerHandler) DeleteCustomers(c *gin.Context) {
+	dryRun, err := parseDryRun(c)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var request BulkDeleteRequest
+	if err := bindBody(c, &request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	selection, err := request.toModel()
+	if err != nil {
+		respondInvalidFilter(c, err)
+		return
+	}
+
+	result, err := h.service.DeleteCustomers(c.Request.Context(), selection, dryRun)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBulkSelection) || errors.Is(err, service.ErrBulkSelectionTooLarge) {
+			respondProblem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao excluir clientes")
+		return
+	}
+
+	respond(c, http.StatusOK, newBulkResultResponse(result))
+}
+
+// RestoreCustomer desfaz a exclusão lógica de um cliente
+// @Summary Restaurar cliente
+// @Description Restaura um cliente excluído logicamente. Retorna 409 quando o cliente não está excluído ou quando seu email já é utilizado por outro cliente.
+// @Tags customers
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "ID do Cliente"
+// @Success 200 {object} CustomerResponse
+// @Header 200 {string} ETag "Nova versão do cliente"
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 409 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /customers/{id}/restore [post]
+func (h *CustomerHandler) RestoreCustomer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	customer, err := h.service.RestoreCustomer(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrCustomerNotDeleted) {
+			respondProblem(c, http.StatusConflict, err.Error())
+			return
+		}
+		var conflict *service.AlreadyExistsError
+		if errors.As(err, &conflict) {
+			respondAlreadyExists(c, conflict)
+			return
+		}
+		respondServerError(c, err, "Erro ao restaurar cliente")
+		return
+	}
+
+	setETag(c, customer)
+	respond(c, http.StatusOK, newCustomerResponse(customer))
+}
+
+// PurgeCustomer remove definitivamente um cliente pelo ID
+// @Summary Remover cliente definitivamente
+// @Description Remove definitivamente um cliente, excluído logicamente ou não, e todo o seu histórico. Esta operação administrativa não pode ser desfeita.
+// @Tags customers
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "ID do Cliente"
+// @Success 204 "No Content"
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /customers/{id}/purge [delete]
+func (h *CustomerHandler) PurgeCustomer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
 		return
 	}
 
-	if err := h.service.DeleteCustomer(c.Request.Context(), uint(id)); err != nil {
-		if err == service.ErrCustomerNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	if err := h.service.PurgeCustomer(c.Request.Context(), uint(id)); err != nil {
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao excluir cliente"})
+		respondServerError(c, err, "Erro ao remover cliente definitivamente")
 		return
 	}
 
@@ -223,16 +916,18 @@ func (h *CustomerHandler) DeleteCustomer(c *gin.Context) {
 // @Description Retorna o número total de clientes cadastrados no sistema
 // @Tags customers
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/yaml,application/msgpack
 // @Success 200 {object} utils.CountResponse
-// @Failure 500 {object} utils.ErrorResponse
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
 // @Router /customers/count [get]
 func (h *CustomerHandler) CountCustomers(c *gin.Context) {
-	count, err := h.service.CountCustomers(c.Request.Context())
+	count, err := h.service.CountCustomers(c.Request.Context(), model.CustomerFilter{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao contar clientes"})
+		respondServerError(c, err, "Erro ao contar clientes")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"count": count})
+	respond(c, http.StatusOK, utils.CountResponse{Count: count})
 }
@@ -8,46 +8,73 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
+	"github.com/ugorji/go/codec"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 
 	"github.com/wandermaia/customer-api/internal/domain/model"
+	mock_repository "github.com/wandermaia/customer-api/internal/domain/repository/mock"
 	"github.com/wandermaia/customer-api/internal/domain/service"
 	mock_service "github.com/wandermaia/customer-api/internal/domain/service/mock" // Import the generated mock
 	"github.com/wandermaia/customer-api/internal/handler"
+	"github.com/wandermaia/customer-api/internal/rsql"
+	"github.com/wandermaia/customer-api/internal/utils"
 )
 
 // setupTestRouter cria e configura um novo Gin engine em modo de teste.
-// Ele instancia o CustomerHandler com o mock do serviço fornecido,
+// Ele instancia o CustomerHandler com o serviço fornecido, em geral um mock,
 // registra as rotas do handler nesse engine e retorna o engine e um ResponseRecorder
 // para capturar as respostas das requisições simuladas.
-func setupTestRouter(t *testing.T, mockService *mock_service.MockCustomerService) (*gin.Engine, *httptest.ResponseRecorder) {
-	gin.SetMode(gin.TestMode)                                  // Garante que o Gin opere em modo de teste (menos verbose)
-	router := gin.New()                                        // Cria um novo engine Gin sem middlewares padrão
-	recorder := httptest.NewRecorder()                         // Cria um gravador para capturar a resposta HTTP
-	customerHandler := handler.NewCustomerHandler(mockService) // Cria o handler com o serviço mockado
-	customerHandler.RegisterRoutes(router)                     // Registra as rotas da API no router
+func setupTestRouter(t *testing.T, customerService service.CustomerService) (*gin.Engine, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)                                      // Garante que o Gin opere em modo de teste (menos verbose)
+	router := gin.New()                                            // Cria um novo engine Gin sem middlewares padrão
+	recorder := httptest.NewRecorder()                             // Cria um gravador para capturar a resposta HTTP
+	customerHandler := handler.NewCustomerHandler(customerService) // Cria o handler com o serviço informado, em geral mockado
+	customerHandler.RegisterRoutes(router)                         // Registra as rotas da API no router
 	return router, recorder
 }
 
+// int64Ptr retorna um ponteiro para o valor informado, usado nos envelopes de paginação.
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
 // performRequest simula o envio de uma requisição HTTP para o router de teste.
 // Cria uma nova requisição HTTP com o método, caminho e corpo (opcional) fornecidos.
 // Se um corpo for fornecido, define o cabeçalho Content-Type como application/json.
 // Executa a requisição contra o router, e a resposta é capturada pelo recorder.
 func performRequest(router *gin.Engine, recorder *httptest.ResponseRecorder, method, path string, body []byte) {
+	performRequestWithHeaders(router, recorder, method, path, body, nil)
+}
+
+// performRequestWithHeaders funciona como performRequest, mas permite definir cabeçalhos
+// adicionais na requisição. Um Content-Type informado substitui o application/json padrão.
+func performRequestWithHeaders(router *gin.Engine, recorder *httptest.ResponseRecorder, method, path string, body []byte, headers map[string]string) {
 	// Cria a requisição HTTP. Ignora o erro pois os parâmetros são controlados pelo teste.
 	req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
 	if body != nil {
 		// Define o tipo de conteúdo se houver corpo na requisição
 		req.Header.Set("Content-Type", "application/json")
 	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 	// Envia a requisição para o router, que a direciona para o handler apropriado.
 	// A resposta do handler é escrita no recorder.
 	router.ServeHTTP(recorder, req)
@@ -56,6 +83,31 @@ func performRequest(router *gin.Engine, recorder *httptest.ResponseRecorder, met
 // TestCustomerHandler_CreateCustomer testa o endpoint POST /api/customers.
 // Ele verifica os cenários de sucesso na criação de um cliente, falha por JSON inválido,
 // falha devido a erro de validação retornado pelo serviço e falha por erro interno do serviço.
+// TestCustomerHandler_RegisterRoutes verifica que os middlewares de criação, como o de idempotência, são executados
+// somente em POST /api/customers, e não nas demais rotas POST, como as que leem o corpo em fluxo.
+func TestCustomerHandler_RegisterRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockService := mock_service.NewMockCustomerService(gomock.NewController(t))
+	// A restauração não tem corpo e chega ao serviço
+	mockService.EXPECT().RestoreCustomer(gomock.Any(), uint(1)).Return(nil, service.ErrCustomerNotFound).Times(1)
+
+	var paths []string
+	handler.NewCustomerHandler(mockService).
+		RegisterRoutes(router, func(c *gin.Context) {
+			paths = append(paths, c.Request.URL.Path)
+			c.AbortWithStatus(http.StatusTeapot)
+		})
+
+	for _, path := range []string{"/api/customers", "/api/customers/batch", "/api/customers/import", "/api/customers/1/restore"} {
+		recorder := httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPost, path, []byte("{}"), map[string]string{"Content-Type": "text/plain"})
+	}
+
+	assert.Equal(t, []string{"/api/customers"}, paths)
+}
+
 func TestCustomerHandler_CreateCustomer(t *testing.T) {
 	// Cria o controlador do Gomock para gerenciar o ciclo de vida dos mocks.
 	mockCtrl := gomock.NewController(t)
@@ -102,6 +154,34 @@ func TestCustomerHandler_CreateCustomer(t *testing.T) {
 		assert.Equal(t, customerOutput, createdCustomer)               // Compara o cliente retornado com o esperado.
 	})
 
+	// Subteste para o cenário em que o corpo informa campos gerenciados pelo servidor.
+	t.Run("Server Managed Fields Ignored", func(t *testing.T) {
+		body := []byte(`{"id": 99, "name": "Test User", "email": "test@example.com", "created_at": "2020-01-01T00:00:00Z", "updated_at": "2020-01-01T00:00:00Z"}`)
+
+		// Define a expectativa: o serviço recebe o cliente sem id e datas, e ativo por padrão.
+		mockService.EXPECT().
+			CreateCustomer(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, c *model.Customer) error {
+				assert.Zero(t, c.ID)
+				assert.True(t, c.CreatedAt.IsZero())
+				assert.True(t, c.UpdatedAt.IsZero())
+				assert.True(t, c.Active)
+				c.ID = 1
+				return nil
+			}).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers", body)
+
+		// Verifica o status 201 e o ID atribuído pelo servidor na resposta.
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var created handler.CustomerResponse
+		err := json.Unmarshal(recorder.Body.Bytes(), &created)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), created.ID)
+		assert.True(t, created.Active)
+	})
+
 	// Subteste para o cenário de JSON inválido na requisição.
 	t.Run("Invalid JSON", func(t *testing.T) {
 		recorder = httptest.NewRecorder()
@@ -155,6 +235,222 @@ func TestCustomerHandler_CreateCustomer(t *testing.T) {
 		// Verifica se a mensagem de erro genérica do handler está na resposta.
 		assert.Contains(t, recorder.Body.String(), "Erro ao criar cliente")
 	})
+
+	// Subteste para o cenário de email já utilizado por outro cliente.
+	t.Run("Already Exists", func(t *testing.T) {
+		customerInput := model.Customer{Name: "Test User", Email: "TEST@example.com"}
+		customerJSON, _ := json.Marshal(customerInput)
+
+		// Define a expectativa: CreateCustomer será chamado e retornará o conflito no campo email.
+		mockService.EXPECT().
+			CreateCustomer(gomock.Any(), gomock.Any()).
+			Return(&service.AlreadyExistsError{Field: "email"}).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers", customerJSON)
+
+		// Verifica o status 409 Conflict e o campo em conflito no corpo problem+json.
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, utils.ProblemContentType, recorder.Header().Get("Content-Type"))
+		var problem utils.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, problem.Status)
+		assert.Equal(t, service.ErrCustomerAlreadyExists.Error(), problem.Detail)
+		assert.Equal(t, "/api/customers", problem.Instance)
+		if assert.Len(t, problem.Errors, 1) {
+			assert.Equal(t, "email", problem.Errors[0].Field)
+			assert.Equal(t, "unique", problem.Errors[0].Rule)
+		}
+	})
+
+	// Subteste para o cenário onde o serviço retorna os detalhes da validação de cada campo.
+	t.Run("Validation Error Details", func(t *testing.T) {
+		customerInput := model.Customer{Email: "not-an-email"}
+		customerJSON, _ := json.Marshal(customerInput)
+
+		// Gera os erros de validação reais do modelo para que o serviço simulado os retorne.
+		validationErrs := customerInput.Validate().(validator.ValidationErrors)
+		mockService.EXPECT().
+			CreateCustomer(gomock.Any(), gomock.Any()).
+			Return(&service.ValidationError{Errors: validationErrs}).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers", customerJSON)
+
+		// Verifica o status 400 e um item em errors[] para cada campo inválido.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, utils.ProblemContentType, recorder.Header().Get("Content-Type"))
+		var problem utils.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		assert.Equal(t, "about:blank", problem.Type)
+		assert.Equal(t, http.StatusText(http.StatusBadRequest), problem.Title)
+		if assert.Len(t, problem.Errors, 2) {
+			assert.Equal(t, utils.FieldError{Field: "name", Rule: "required", Message: "campo obrigatório"}, problem.Errors[0])
+			assert.Equal(t, "email", problem.Errors[1].Field)
+			assert.Equal(t, "email", problem.Errors[1].Rule)
+		}
+	})
+
+	// Subteste para o cenário onde um campo do corpo tem o tipo errado.
+	t.Run("Invalid Field Type", func(t *testing.T) {
+		mockService.EXPECT().CreateCustomer(gomock.Any(), gomock.Any()).Times(0)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers", []byte(`{"name": 123}`))
+
+		// Verifica o status 400 e o campo com o tipo inválido.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var problem utils.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		if assert.Len(t, problem.Errors, 1) {
+			assert.Equal(t, "name", problem.Errors[0].Field)
+			assert.Equal(t, "type", problem.Errors[0].Rule)
+		}
+	})
+}
+
+// TestCustomerHandler_CreateCustomers testa o endpoint POST /api/customers/batch.
+// Verifica a resposta multi-status com o resultado de cada cliente, o modo padrão,
+// modos inválidos, lotes vazios ou grandes demais e o erro interno do serviço.
+func TestCustomerHandler_CreateCustomers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+
+	// Subteste para o cenário best_effort com clientes criados e com falhas.
+	t.Run("Multi Status", func(t *testing.T) {
+		body := []byte(`{"mode": "best_effort", "customers": [
+			{"name": "First User", "email": "first@example.com"},
+			{"name": "", "email": "second@example.com"},
+			{"name": "Third User", "email": "taken@example.com"}
+		]}`)
+		validationErrs := (&model.Customer{Email: "second@example.com"}).Validate().(validator.ValidationErrors)
+
+		// Define a expectativa: o serviço recebe os três clientes no modo best_effort.
+		mockService.EXPECT().
+			CreateCustomers(gomock.Any(), gomock.Any(), service.BatchBestEffort).
+			DoAndReturn(func(ctx context.Context, customers []*model.Customer, mode service.BatchMode) ([]service.BatchResult, error) {
+				assert.Len(t, customers, 3)
+				customers[0].ID = 10
+				return []service.BatchResult{
+					{Customer: customers[0]},
+					{Err: &service.ValidationError{Errors: validationErrs}},
+					{Err: &service.AlreadyExistsError{Field: "email"}},
+				}, nil
+			}).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers/batch", body)
+
+		// Verifica o status 207 e o resultado de cada cliente na ordem enviada.
+		assert.Equal(t, http.StatusMultiStatus, recorder.Code)
+		var response handler.BatchCreateResponse
+		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "best_effort", response.Mode)
+		assert.Equal(t, 1, response.Created)
+		assert.Equal(t, 2, response.Failed)
+		if assert.Len(t, response.Results, 3) {
+			assert.Equal(t, http.StatusCreated, response.Results[0].Status)
+			assert.Equal(t, uint(10), response.Results[0].Customer.ID)
+			assert.Nil(t, response.Results[0].Error)
+
+			assert.Equal(t, 1, response.Results[1].Index)
+			assert.Equal(t, http.StatusBadRequest, response.Results[1].Status)
+			assert.Equal(t, "name", response.Results[1].Error.Errors[0].Field)
+
+			assert.Equal(t, http.StatusConflict, response.Results[2].Status)
+			assert.Equal(t, "unique", response.Results[2].Error.Errors[0].Rule)
+		}
+	})
+
+	// Subteste para o modo padrão, em que a falha de um cliente impede a criação dos demais.
+	t.Run("All Or Nothing Aborted", func(t *testing.T) {
+		body := []byte(`{"customers": [{"name": "First User", "email": "first@example.com"}, {"name": "Second User", "email": "taken@example.com"}]}`)
+
+		mockService.EXPECT().
+			CreateCustomers(gomock.Any(), gomock.Any(), service.BatchAllOrNothing).
+			Return([]service.BatchResult{
+				{Err: service.ErrBatchAborted},
+				{Err: &service.AlreadyExistsError{Field: "email"}},
+			}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers/batch", body)
+
+		// Verifica que o cliente não criado por causa do outro recebe o status 424.
+		assert.Equal(t, http.StatusMultiStatus, recorder.Code)
+		var response handler.BatchCreateResponse
+		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "all_or_nothing", response.Mode)
+		assert.Equal(t, 0, response.Created)
+		if assert.Len(t, response.Results, 2) {
+			assert.Equal(t, http.StatusFailedDependency, response.Results[0].Status)
+			assert.Equal(t, http.StatusConflict, response.Results[1].Status)
+		}
+	})
+
+	// Subteste para o cenário de modo de criação inválido.
+	t.Run("Invalid Mode", func(t *testing.T) {
+		mockService.EXPECT().CreateCustomers(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers/batch", []byte(`{"mode": "partial", "customers": []}`))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrInvalidBatchMode.Error())
+	})
+
+	// Subteste para o cenário de lote vazio.
+	t.Run("Empty Batch", func(t *testing.T) {
+		mockService.EXPECT().
+			CreateCustomers(gomock.Any(), gomock.Any(), service.BatchAllOrNothing).
+			Return(nil, service.ErrEmptyBatch).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers/batch", []byte(`{"customers": []}`))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	// Subteste para o cenário de lote acima do limite.
+	t.Run("Batch Too Large", func(t *testing.T) {
+		mockService.EXPECT().
+			CreateCustomers(gomock.Any(), gomock.Any(), service.BatchAllOrNothing).
+			Return(nil, service.ErrBatchTooLarge).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers/batch", []byte(`{"customers": [{"name": "First User"}]}`))
+
+		// Verifica o status 413 e o limite informado no detalhe.
+		assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), strconv.Itoa(service.MaxBatchSize))
+	})
+
+	// Subteste para o cenário de erro interno do serviço.
+	t.Run("Service Internal Error", func(t *testing.T) {
+		mockService.EXPECT().
+			CreateCustomers(gomock.Any(), gomock.Any(), service.BatchAllOrNothing).
+			Return(nil, errors.New("database insert failed")).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers/batch", []byte(`{"customers": [{"name": "First User"}]}`))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Erro ao criar clientes em lote")
+	})
 }
 
 // TestCustomerHandler_GetCustomerByID testa o endpoint GET /api/customers/{id}.
@@ -174,28 +470,46 @@ func TestCustomerHandler_GetCustomerByID(t *testing.T) {
 	// Subteste para o cenário de sucesso.
 	t.Run("Success", func(t *testing.T) {
 		// Define o cliente esperado que o serviço retornará.
-		expectedCustomer := model.Customer{ID: testID, Name: "Found User", Email: "found@example.com"}
+		expectedCustomer := model.Customer{ID: testID, Name: "Found User", Email: "found@example.com", Version: 4}
 
 		// Define a expectativa: GetCustomerByID será chamado com o ID correto e retornará o cliente.
 		mockService.EXPECT().
-			GetCustomerByID(gomock.Any(), testID). // Espera o ID específico.
-			Return(&expectedCustomer, nil).        // Retorna o cliente encontrado (como ponteiro) e nenhum erro.
+			GetCustomerByID(gomock.Any(), testID, nil). // Espera o ID específico.
+			Return(&expectedCustomer, nil).             // Retorna o cliente encontrado (como ponteiro) e nenhum erro.
 			Times(1)
 
 		recorder = httptest.NewRecorder()
 		// Executa a requisição GET para o ID específico.
 		performRequest(router, recorder, http.MethodGet, "/api/customers/"+testIDStr, nil)
 
-		// Verifica o status 200 OK.
+		// Verifica o status 200 OK e a ETag com a versão do cliente.
 		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"4-json"`, recorder.Header().Get("ETag"))
 
-		// Verifica o corpo da resposta.
+		// Verifica o corpo da resposta. A versão não faz parte do corpo.
 		var foundCustomer model.Customer
 		err := json.Unmarshal(recorder.Body.Bytes(), &foundCustomer)
 		assert.NoError(t, err)
+		expectedCustomer.Version = 0
 		assert.Equal(t, expectedCustomer, foundCustomer) // Compara o cliente da resposta com o esperado.
 	})
 
+	// Subteste para o cenário com campos selecionados: os campos não pedidos são omitidos do corpo.
+	t.Run("With Fields", func(t *testing.T) {
+		mockService.EXPECT().
+			GetCustomerByID(gomock.Any(), testID, model.FieldSet{"id", "name"}).
+			Return(&model.Customer{ID: testID, Name: "Found User", Version: 4}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/"+testIDStr+"?fields=id,name", nil)
+
+		// A ETag continua sendo a versão do cliente.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"4-json-id.name"`, recorder.Header().Get("ETag"))
+		assert.JSONEq(t, `{"id":123,"name":"Found User"}`, recorder.Body.String())
+	})
+
 	// Subteste para o cenário de formato de ID inválido na URL.
 	t.Run("Invalid ID Format", func(t *testing.T) {
 		recorder = httptest.NewRecorder()
@@ -208,11 +522,79 @@ func TestCustomerHandler_GetCustomerByID(t *testing.T) {
 		assert.Contains(t, recorder.Body.String(), "ID inválido")
 	})
 
+	// Subteste para requisições condicionais cuja representação não mudou.
+	t.Run("Not Modified", func(t *testing.T) {
+		updatedAt := time.Date(2025, 4, 23, 15, 4, 5, 0, time.UTC)
+		storedCustomer := &model.Customer{ID: testID, Name: "Found User", Email: "found@example.com", UpdatedAt: updatedAt, Version: 4}
+
+		for _, headers := range []map[string]string{
+			{"If-None-Match": `"4-json"`},
+			{"If-None-Match": `"3-json", W/"4-json"`},
+			{"If-Modified-Since": updatedAt.Format(http.TimeFormat)},
+			{"If-Modified-Since": updatedAt.Add(time.Hour).Format(http.TimeFormat)},
+		} {
+			mockService.EXPECT().GetCustomerByID(gomock.Any(), testID, nil).Return(storedCustomer, nil).Times(1)
+
+			recorder = httptest.NewRecorder()
+			performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers/"+testIDStr, nil, headers)
+
+			// Verifica o status 304 Not Modified, sem corpo e com os validadores da representação.
+			assert.Equal(t, http.StatusNotModified, recorder.Code, headers)
+			assert.Empty(t, recorder.Body.String())
+			assert.Equal(t, `"4-json"`, recorder.Header().Get("ETag"))
+			assert.Equal(t, updatedAt.Format(http.TimeFormat), recorder.Header().Get("Last-Modified"))
+		}
+	})
+
+	// Subteste para requisições condicionais cuja representação mudou.
+	t.Run("Modified", func(t *testing.T) {
+		updatedAt := time.Date(2025, 4, 23, 15, 4, 5, 0, time.UTC)
+		storedCustomer := &model.Customer{ID: testID, Name: "Found User", Email: "found@example.com", UpdatedAt: updatedAt, Version: 4}
+
+		for _, headers := range []map[string]string{
+			{"If-None-Match": `"3"`},
+			{"If-Modified-Since": updatedAt.Add(-time.Second).Format(http.TimeFormat)},
+			// If-Modified-Since é ignorado quando If-None-Match é informado.
+			{"If-None-Match": `"3"`, "If-Modified-Since": updatedAt.Format(http.TimeFormat)},
+		} {
+			mockService.EXPECT().GetCustomerByID(gomock.Any(), testID, nil).Return(storedCustomer, nil).Times(1)
+
+			recorder = httptest.NewRecorder()
+			performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers/"+testIDStr, nil, headers)
+
+			assert.Equal(t, http.StatusOK, recorder.Code, headers)
+			assert.Contains(t, recorder.Body.String(), "Found User")
+		}
+	})
+
+	// Subteste para a ETag de outra representação: cada formato e cada seleção de campos tem sua própria ETag
+	// forte, e a ETag de uma representação não valida as demais.
+	t.Run("Other Representation", func(t *testing.T) {
+		storedCustomer := &model.Customer{ID: testID, Name: "Found User", Version: 4}
+
+		for _, request := range []struct {
+			path    string
+			headers map[string]string
+			etag    string
+		}{
+			{"/api/customers/" + testIDStr, map[string]string{"Accept": "application/xml", "If-None-Match": `"4-json"`}, `"4-xml"`},
+			{"/api/customers/" + testIDStr + "?fields=name,id", map[string]string{"If-None-Match": `"4-json"`}, `"4-json-id.name"`},
+		} {
+			mockService.EXPECT().GetCustomerByID(gomock.Any(), testID, gomock.Any()).Return(storedCustomer, nil).Times(1)
+
+			recorder = httptest.NewRecorder()
+			performRequestWithHeaders(router, recorder, http.MethodGet, request.path, nil, request.headers)
+
+			assert.Equal(t, http.StatusOK, recorder.Code, request.path)
+			assert.Equal(t, request.etag, recorder.Header().Get("ETag"), request.path)
+		}
+	})
+
 	// Subteste para o cenário onde o cliente não é encontrado pelo serviço.
 	t.Run("Not Found", func(t *testing.T) {
 		// Define a expectativa: GetCustomerByID será chamado, mas retornará ErrCustomerNotFound.
 		mockService.EXPECT().
-			GetCustomerByID(gomock.Any(), testID).
+			GetCustomerByID(gomock.Any(), testID, nil).
 			Return(nil, service.ErrCustomerNotFound). // Retorna nil para cliente e o erro específico.
 			Times(1)
 
@@ -231,7 +613,7 @@ func TestCustomerHandler_GetCustomerByID(t *testing.T) {
 
 		// Define a expectativa: GetCustomerByID será chamado e retornará um erro interno.
 		mockService.EXPECT().
-			GetCustomerByID(gomock.Any(), testID).
+			GetCustomerByID(gomock.Any(), testID, nil).
 			Return(nil, serviceErr). // Retorna nil para cliente e o erro genérico.
 			Times(1)
 
@@ -242,11 +624,53 @@ func TestCustomerHandler_GetCustomerByID(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
 		// Verifica a mensagem de erro genérica do handler.
 		assert.Contains(t, recorder.Body.String(), "Erro ao buscar cliente")
+		// A causa do erro não é exposta ao cliente.
+		assert.NotContains(t, recorder.Body.String(), serviceErr.Error())
+	})
+
+	// Subteste para o cenário em que o banco de dados está indisponível.
+	t.Run("Database Unavailable", func(t *testing.T) {
+		serviceErr := fmt.Errorf("%w: dial tcp: connection refused", service.ErrDatabaseUnavailable)
+
+		mockService.EXPECT().
+			GetCustomerByID(gomock.Any(), testID, nil).
+			Return(nil, serviceErr).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/"+testIDStr, nil)
+
+		// Verifica o status 503 Service Unavailable e o cabeçalho Retry-After.
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.NotEmpty(t, recorder.Header().Get("Retry-After"))
+		assert.Contains(t, recorder.Body.String(), service.ErrDatabaseUnavailable.Error())
+		assert.NotContains(t, recorder.Body.String(), "connection refused")
+	})
+
+	// Subteste para o cenário em que o cliente cancela a requisição: não é uma falha do servidor, e a causa não é
+	// registrada como erro.
+	t.Run("Request Canceled", func(t *testing.T) {
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		defer log.SetOutput(os.Stderr)
+
+		mockService.EXPECT().
+			GetCustomerByID(gomock.Any(), testID, nil).
+			Return(nil, fmt.Errorf("timeout: %w", context.Canceled)).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/"+testIDStr, nil)
+
+		assert.Equal(t, 499, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+		assert.Empty(t, logs.String())
 	})
 }
 
 // TestCustomerHandler_GetAllCustomers testa o endpoint GET /api/customers.
-// Verifica o cenário de sucesso ao buscar todos os clientes e o cenário de erro interno do serviço.
+// Verifica o cenário de sucesso com o envelope paginado e os cabeçalhos Link,
+// a falha por parâmetros de paginação inválidos e o cenário de erro interno do serviço.
 func TestCustomerHandler_GetAllCustomers(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
@@ -256,272 +680,1749 @@ func TestCustomerHandler_GetAllCustomers(t *testing.T) {
 
 	// Subteste para o cenário de sucesso.
 	t.Run("Success", func(t *testing.T) {
-		// Define a lista esperada de clientes (como slice de ponteiros, conforme a assinatura do serviço).
-		expectedCustomers := []*model.Customer{
-			{ID: 1, Name: "User One", Email: "one@example.com"},
-			{ID: 2, Name: "User Two", Email: "two@example.com"},
+		// Define a página esperada de clientes (como slice de ponteiros, conforme a assinatura do serviço).
+		expectedPage := &model.CustomerPage{
+			Items: []*model.Customer{
+				{ID: 3, Name: "User Three", Email: "three@example.com"},
+				{ID: 4, Name: "User Four", Email: "four@example.com"},
+			},
+			Total:      int64Ptr(6),
+			Page:       2,
+			PageSize:   2,
+			NextCursor: "next-cursor",
 		}
 
-		// Define a expectativa: GetAllCustomers será chamado e retornará a lista de clientes.
+		// Define a expectativa: GetAllCustomers será chamado com a paginação da query string.
 		mockService.EXPECT().
-			GetAllCustomers(gomock.Any()).
-			Return(expectedCustomers, nil). // Retorna o slice de ponteiros e nenhum erro.
+			GetAllCustomers(gomock.Any(), model.CustomerFilter{}, nil, model.Pagination{Page: 2, PageSize: 2}).
+			Return(expectedPage, nil). // Retorna a página e nenhum erro.
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		performRequest(router, recorder, http.MethodGet, "/api/customers", nil)
+		performRequest(router, recorder, http.MethodGet, "/api/customers?page=2&page_size=2", nil)
 
 		// Verifica o status 200 OK.
 		assert.Equal(t, http.StatusOK, recorder.Code)
 
-		// Verifica o corpo da resposta.
-		// Assume que o handler serializa para um slice de valores no JSON.
-		var customers []model.Customer
-		err := json.Unmarshal(recorder.Body.Bytes(), &customers)
+		// Verifica os links para as páginas seguinte e anterior.
+		link := recorder.Header().Get("Link")
+		assert.Contains(t, link, `</api/customers?page=3&page_size=2>; rel="next"`)
+		assert.Contains(t, link, `</api/customers?page=1&page_size=2>; rel="prev"`)
+
+		// Verifica o corpo da resposta (envelope com itens, total, página e tamanho da página).
+		var page model.CustomerPage
+		err := json.Unmarshal(recorder.Body.Bytes(), &page)
 		assert.NoError(t, err)
+		assert.Equal(t, *expectedPage, page) // Compara o envelope da resposta com o esperado.
+	})
 
-		// Cria um slice de valores esperado para comparação com o resultado do JSON.
-		expectedValues := []model.Customer{
-			{ID: 1, Name: "User One", Email: "one@example.com"},
-			{ID: 2, Name: "User Two", Email: "two@example.com"},
+	// Subteste para o cenário de requisição condicional com a ETag do conteúdo da página.
+	t.Run("Not Modified", func(t *testing.T) {
+		updatedAt := time.Date(2025, 4, 23, 15, 4, 5, 0, time.UTC)
+		expectedPage := &model.CustomerPage{
+			Items: []*model.Customer{
+				{ID: 1, Name: "User One", Email: "one@example.com", UpdatedAt: updatedAt.Add(-time.Hour)},
+				{ID: 2, Name: "User Two", Email: "two@example.com", UpdatedAt: updatedAt},
+			},
+			Total:    int64Ptr(2),
+			Page:     1,
+			PageSize: 20,
 		}
-		assert.Equal(t, expectedValues, customers) // Compara a lista da resposta com os valores esperados.
+
+		mockService.EXPECT().
+			GetAllCustomers(gomock.Any(), model.CustomerFilter{}, nil, model.Pagination{}).
+			Return(expectedPage, nil).
+			Times(3)
+
+		// A primeira requisição retorna a página com a ETag calculada a partir do conteúdo.
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		etag := recorder.Header().Get("ETag")
+		assert.True(t, strings.HasPrefix(etag, `W/"`), etag)
+		// Last-Modified corresponde à alteração mais recente entre os clientes da página.
+		assert.Equal(t, updatedAt.Format(http.TimeFormat), recorder.Header().Get("Last-Modified"))
+
+		// Com a ETag recebida, a mesma página resulta em 304 sem corpo.
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers", nil, map[string]string{"If-None-Match": etag})
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+
+		// O mesmo vale para If-Modified-Since com a data de Last-Modified.
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers", nil, map[string]string{"If-Modified-Since": updatedAt.Format(http.TimeFormat)})
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
 	})
 
-	// Subteste para o cenário de erro interno do serviço.
-	t.Run("Service Internal Error", func(t *testing.T) {
-		serviceErr := errors.New("failed to fetch all") // Erro genérico simulado.
+	// Subteste para o cenário em que o conteúdo da página mudou desde a ETag informada.
+	t.Run("Modified", func(t *testing.T) {
+		expectedPage := &model.CustomerPage{
+			Items:    []*model.Customer{{ID: 1, Name: "User One", Email: "one@example.com"}},
+			Total:    int64Ptr(1),
+			Page:     1,
+			PageSize: 20,
+		}
 
-		// Define a expectativa: GetAllCustomers será chamado e retornará um erro interno.
 		mockService.EXPECT().
-			GetAllCustomers(gomock.Any()).
-			Return(nil, serviceErr). // Retorna nil para a lista e o erro genérico.
+			GetAllCustomers(gomock.Any(), model.CustomerFilter{}, nil, model.Pagination{}).
+			Return(expectedPage, nil).
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		performRequest(router, recorder, http.MethodGet, "/api/customers", nil)
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers", nil, map[string]string{"If-None-Match": `W/"outra-versao"`})
 
-		// Verifica o status 500 Internal Server Error.
-		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-		// Verifica a mensagem de erro genérica do handler.
-		assert.Contains(t, recorder.Body.String(), "Erro ao buscar clientes")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "User One")
 	})
-}
 
-// TestCustomerHandler_GetCustomersByName testa o endpoint GET /api/customers/search?name={name}.
-// Verifica os cenários de sucesso na busca por nome, falha se o nome não for fornecido
-// e falha por erro interno do serviço.
-func TestCustomerHandler_GetCustomersByName(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
+	// Subteste para o cenário de última página, que não deve conter link para a próxima.
+	t.Run("Last Page", func(t *testing.T) {
+		expectedPage := &model.CustomerPage{
+			Items:    []*model.Customer{{ID: 1, Name: "User One", Email: "one@example.com"}},
+			Total:    int64Ptr(1),
+			Page:     1,
+			PageSize: 20,
+		}
 
-	mockService := mock_service.NewMockCustomerService(mockCtrl)
-	router, recorder := setupTestRouter(t, mockService)
+		mockService.EXPECT().
+			GetAllCustomers(gomock.Any(), model.CustomerFilter{}, nil, model.Pagination{}).
+			Return(expectedPage, nil).
+			Times(1)
 
-	searchName := "Test" // Nome a ser buscado.
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers", nil)
 
-	// Subteste para o cenário de sucesso.
-	t.Run("Success", func(t *testing.T) {
-		// Define a lista esperada de clientes encontrados (como slice de ponteiros).
-		expectedCustomers := []*model.Customer{
-			{ID: 1, Name: "Test User 1", Email: "test1@example.com"},
-			{ID: 3, Name: "Test User 3", Email: "test3@example.com"},
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("Link"))
+	})
+
+	// Subteste para o cenário de paginação por cursor.
+	t.Run("Cursor Pagination", func(t *testing.T) {
+		expectedPage := &model.CustomerPage{
+			Items:      []*model.Customer{{ID: 5, Name: "User Five", Email: "five@example.com"}},
+			PageSize:   1,
+			NextCursor: "cursor-6",
 		}
 
-		// Define a expectativa: GetCustomersByName será chamado com o nome correto e retornará a lista.
+		// Define a expectativa: GetAllCustomers será chamado com o cursor da query string.
 		mockService.EXPECT().
-			GetCustomersByName(gomock.Any(), searchName). // Espera o nome específico.
-			Return(expectedCustomers, nil).               // Retorna a lista encontrada e nenhum erro.
+			GetAllCustomers(gomock.Any(), model.CustomerFilter{}, nil, model.Pagination{PageSize: 1, Cursor: "cursor-5"}).
+			Return(expectedPage, nil).
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		// Executa a requisição GET com o parâmetro de query 'name'.
-		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName, nil)
+		performRequest(router, recorder, http.MethodGet, "/api/customers?cursor=cursor-5&page_size=1", nil)
 
-		// Verifica o status 200 OK.
 		assert.Equal(t, http.StatusOK, recorder.Code)
+		// O link para a próxima página deve usar o next_cursor retornado.
+		assert.Equal(t, `</api/customers?cursor=cursor-6&page_size=1>; rel="next"`, recorder.Header().Get("Link"))
 
-		// Verifica o corpo da resposta. Assume que o handler retorna ponteiros ou valores que podem ser comparados diretamente.
-		// Ajuste o tipo de 'customers' se necessário (para []model.Customer).
-		var customers []*model.Customer
-		err := json.Unmarshal(recorder.Body.Bytes(), &customers)
+		// Na paginação por cursor, page e total não são retornados.
+		var body map[string]any
+		err := json.Unmarshal(recorder.Body.Bytes(), &body)
 		assert.NoError(t, err)
-		// testify/assert.Equal pode comparar slices de ponteiros por valor dos elementos apontados.
-		assert.Equal(t, expectedCustomers, customers)
+		assert.Equal(t, "cursor-6", body["next_cursor"])
+		assert.NotContains(t, body, "page")
+		assert.NotContains(t, body, "total")
 	})
 
-	// Subteste para o cenário onde o parâmetro 'name' não é fornecido.
-	t.Run("Name Not Provided", func(t *testing.T) {
+	// Subteste para o cenário de cursor inválido.
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		mockService.EXPECT().
+			GetAllCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrInvalidCursor).
+			Times(1)
+
 		recorder = httptest.NewRecorder()
-		// Executa a requisição sem o parâmetro 'name'.
-		performRequest(router, recorder, http.MethodGet, "/api/customers/search", nil)
+		performRequest(router, recorder, http.MethodGet, "/api/customers?cursor=invalid", nil)
 
 		// Verifica o status 400 Bad Request.
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
-		// Verifica a mensagem de erro específica.
-		assert.Contains(t, recorder.Body.String(), "Nome não fornecido")
+		assert.Contains(t, recorder.Body.String(), service.ErrInvalidCursor.Error())
 	})
 
-	// Subteste para o cenário de erro interno do serviço.
-	t.Run("Service Internal Error", func(t *testing.T) {
-		serviceErr := errors.New("search failed") // Erro genérico simulado.
+	// Subteste para o cenário com filtros na query string.
+	t.Run("With Filters", func(t *testing.T) {
+		active := true
+		createdAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		updatedSince := time.Date(2025, 4, 23, 15, 4, 5, 0, time.UTC)
+		expectedFilter := model.CustomerFilter{
+			Active:         &active,
+			CreatedAfter:   &createdAfter,
+			UpdatedSince:   &updatedSince,
+			EmailDomain:    "example.com",
+			PhonePrefix:    "11",
+			IncludeDeleted: true,
+		}
 
-		// Define a expectativa: GetCustomersByName será chamado e retornará um erro interno.
+		// Define a expectativa: GetAllCustomers será chamado com o filtro convertido da query string.
 		mockService.EXPECT().
-			GetCustomersByName(gomock.Any(), searchName).
-			Return(nil, serviceErr). // Retorna nil para a lista e o erro genérico.
+			GetAllCustomers(gomock.Any(), expectedFilter, nil, model.Pagination{}).
+			Return(&model.CustomerPage{Items: []*model.Customer{}, Total: int64Ptr(0), Page: 1, PageSize: 20}, nil).
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName, nil)
+		performRequest(router, recorder, http.MethodGet,
+			"/api/customers?active=true&created_after=2025-01-01&updated_since=2025-04-23T15:04:05Z&email_domain=@example.com&phone_prefix=(11)&include_deleted=true", nil)
 
-		// Verifica o status 500 Internal Server Error.
-		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-		// Verifica a mensagem de erro genérica do handler.
-		assert.Contains(t, recorder.Body.String(), "Erro ao buscar clientes")
+		assert.Equal(t, http.StatusOK, recorder.Code)
 	})
-}
-
-// TestCustomerHandler_UpdateCustomer testa o endpoint PUT /api/customers/{id}.
-// Verifica os cenários de sucesso na atualização, falha por ID inválido, JSON inválido,
-// erro de validação do serviço, erro de cliente não encontrado pelo serviço e erro interno do serviço.
-func TestCustomerHandler_UpdateCustomer(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	defer mockCtrl.Finish()
-
-	mockService := mock_service.NewMockCustomerService(mockCtrl)
-	router, recorder := setupTestRouter(t, mockService)
-
-	testID := uint(456)
-	testIDStr := strconv.FormatUint(uint64(testID), 10)
 
-	// Subteste para o cenário de sucesso.
-	t.Run("Success", func(t *testing.T) {
-		// Define os dados de atualização e o resultado esperado.
-		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
-		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
-		// O cliente esperado na resposta deve ter o ID da URL e os dados do corpo.
-		expectedUpdatedCustomer := model.Customer{ID: testID, Name: "Updated Name", Email: "updated@example.com"}
+	// Subteste para o cenário da listagem administrativa que inclui clientes excluídos.
+	t.Run("Include Deleted", func(t *testing.T) {
+		deletedAt := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+		customers := []*model.Customer{
+			{ID: 1, Name: "Active", Email: "active@example.com"},
+			{ID: 2, Name: "Deleted", Email: "deleted@example.com", DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+		}
 
-		// Define a expectativa: UpdateCustomer será chamado.
 		mockService.EXPECT().
-			UpdateCustomer(gomock.Any(), gomock.Any()).
-			DoAndReturn(func(ctx context.Context, c *model.Customer) error {
-				// Verifica se o ID da URL foi corretamente atribuído ao objeto Customer passado para o serviço.
-				assert.Equal(t, testID, c.ID)
-				// Verifica se os dados do corpo foram corretamente passados.
-				assert.Equal(t, customerUpdateInput.Name, c.Name)
-				assert.Equal(t, customerUpdateInput.Email, c.Email)
-				// Simula o sucesso da atualização no serviço (retorna nil).
-				// O serviço pode ou não modificar 'c' ou retornar o objeto atualizado;
-				// aqui assumimos que o handler retornará o objeto 'c' como ele foi passado (com ID setado).
-				return nil
-			}).Times(1)
+			GetAllCustomers(gomock.Any(), model.CustomerFilter{IncludeDeleted: true}, nil, model.Pagination{}).
+			Return(&model.CustomerPage{Items: customers, Total: int64Ptr(2), Page: 1, PageSize: 20}, nil).
+			Times(1)
 
 		recorder = httptest.NewRecorder()
-		// Executa a requisição PUT com o ID e o corpo JSON.
-		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON)
+		performRequest(router, recorder, http.MethodGet, "/api/customers?include_deleted=true", nil)
 
-		// Verifica o status 200 OK.
+		// Verifica que somente o cliente excluído informa deleted_at.
 		assert.Equal(t, http.StatusOK, recorder.Code)
-
+		var page handler.CustomerPageResponse
+		err := json.Unmarshal(recorder.Body.Bytes(), &page)
+		assert.NoError(t, err)
+		if assert.Len(t, page.Items, 2) {
+			assert.Nil(t, page.Items[0].DeletedAt)
+			if assert.NotNil(t, page.Items[1].DeletedAt) {
+				assert.True(t, deletedAt.Equal(*page.Items[1].DeletedAt))
+			}
+		}
+	})
+
+	// Subteste para o cenário com ordenação por múltiplos campos.
+	t.Run("With Sort", func(t *testing.T) {
+		expectedSort := model.Sort{{Field: "created_at", Desc: true}, {Field: "name"}}
+
+		mockService.EXPECT().
+			GetAllCustomers(gomock.Any(), model.CustomerFilter{}, expectedSort, model.Pagination{}).
+			Return(&model.CustomerPage{Items: []*model.Customer{}, Total: int64Ptr(0), Page: 1, PageSize: 20}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers?sort=-created_at,name", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	// Subteste para o cenário de campo de ordenação fora da lista permitida.
+	t.Run("Invalid Sort", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers?sort=name%3BDROP%20TABLE%20customers", nil)
+
+		// Verifica o status 400 Bad Request e a lista de valores permitidos.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var problem utils.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		assert.Contains(t, problem.Detail, "campo de ordenação inválido")
+		assert.Equal(t, model.SortableCustomerFields, problem.AllowedValues)
+	})
+
+	// Subteste para o cenário com campos selecionados: cada item contém somente os campos pedidos, e o envelope
+	// de paginação é mantido.
+	t.Run("With Fields", func(t *testing.T) {
+		mockService.EXPECT().
+			GetAllCustomers(gomock.Any(), model.CustomerFilter{Fields: model.FieldSet{"id", "name", "active"}}, nil, model.Pagination{}).
+			Return(&model.CustomerPage{
+				Items:    []*model.Customer{{ID: 1, Name: "Ana", Email: "ana@example.com", Address: "Rua A, 1", Active: true}},
+				Total:    int64Ptr(1),
+				Page:     1,
+				PageSize: 20,
+			}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers?fields=id,name,active", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"items":[{"id":1,"name":"Ana","active":true}],"total":1,"page":1,"page_size":20}`, recorder.Body.String())
+	})
+
+	// Subteste para o cenário com campos selecionados em XML.
+	t.Run("With Fields XML", func(t *testing.T) {
+		mockService.EXPECT().
+			GetAllCustomers(gomock.Any(), model.CustomerFilter{Fields: model.FieldSet{"id", "email"}}, nil, model.Pagination{}).
+			Return(&model.CustomerPage{Items: []*model.Customer{{ID: 1, Name: "Ana", Email: "ana@example.com"}}, Page: 1, PageSize: 20}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers?fields=id,email", nil, map[string]string{"Accept": "application/xml"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "<items><customer><id>1</id><email>ana@example.com</email></customer></items>")
+		assert.NotContains(t, recorder.Body.String(), "<name>")
+	})
+
+	// Subteste para o cenário de campo fora da lista permitida: o serviço não é chamado.
+	t.Run("Invalid Fields", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers?fields=id,version", nil)
+
+		// Verifica o status 400 Bad Request e a lista de campos permitidos.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var problem utils.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		assert.Contains(t, problem.Detail, `"version"`)
+		assert.Equal(t, model.SelectableCustomerFields, problem.AllowedValues)
+	})
+
+	// Subteste para o cenário de filtros inválidos.
+	t.Run("Invalid Filter", func(t *testing.T) {
+		for _, query := range []string{"active=talvez", "created_before=ontem", "email_domain=a@b.com", "phone_prefix=abc"} {
+			recorder = httptest.NewRecorder()
+			performRequest(router, recorder, http.MethodGet, "/api/customers?"+query, nil)
+
+			// Verifica o status 400 Bad Request.
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+			assert.Contains(t, recorder.Body.String(), "Parâmetros de filtro inválidos", query)
+		}
+	})
+
+	// Subteste para o cenário com expressão de filtro, convertida na árvore sintática.
+	t.Run("With Filter Expression", func(t *testing.T) {
+		expectedFilter := model.CustomerFilter{Expression: &rsql.Logical{Operator: rsql.And, Operands: []rsql.Node{
+			&rsql.Comparison{Field: "active", Operator: rsql.Equal, Values: []any{true}},
+			&rsql.Logical{Operator: rsql.Or, Operands: []rsql.Node{
+				&rsql.Comparison{Field: "name", Operator: rsql.Like, Values: []any{"Silva*"}},
+				&rsql.Comparison{Field: "email", Operator: rsql.Like, Values: []any{"*@corp.com"}},
+			}},
+		}}}
+
+		mockService.EXPECT().
+			GetAllCustomers(gomock.Any(), expectedFilter, nil, model.Pagination{}).
+			Return(&model.CustomerPage{Items: []*model.Customer{}, Total: int64Ptr(0), Page: 1, PageSize: 20}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet,
+			"/api/customers?filter="+url.QueryEscape("active==true;(name=like=Silva*,email=like=*@corp.com)"), nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	// Subteste para o cenário de expressão de filtro inválida, com a posição do erro.
+	t.Run("Invalid Filter Expression", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers?filter="+url.QueryEscape("active==true;(name==Ana"), nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var problem utils.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		assert.Equal(t, 24, problem.Position)
+		assert.Contains(t, problem.Detail, "expressão de filtro inválida na posição 24")
+	})
+
+	// Subteste para o cenário de campo fora da lista permitida na expressão de filtro.
+	t.Run("Filter Expression Unknown Field", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers?filter="+url.QueryEscape("active==true;version>1"), nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var problem utils.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		assert.Equal(t, 14, problem.Position)
+		assert.Contains(t, problem.AllowedValues, "created_at")
+		assert.NotContains(t, problem.AllowedValues, "version")
+	})
+
+	// Subteste para o cenário de parâmetros de paginação inválidos.
+	t.Run("Invalid Pagination", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers?page=0&page_size=abc", nil)
+
+		// Verifica o status 400 Bad Request.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Parâmetros de paginação inválidos")
+	})
+
+	// Subteste para o cenário de erro interno do serviço.
+	t.Run("Service Internal Error", func(t *testing.T) {
+		serviceErr := errors.New("failed to fetch all") // Erro genérico simulado.
+
+		// Define a expectativa: GetAllCustomers será chamado e retornará um erro interno.
+		mockService.EXPECT().
+			GetAllCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, serviceErr). // Retorna nil para a página e o erro genérico.
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers", nil)
+
+		// Verifica o status 500 Internal Server Error.
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		// Verifica a mensagem de erro genérica do handler.
+		assert.Contains(t, recorder.Body.String(), "Erro ao buscar clientes")
+	})
+}
+
+// TestCustomerHandler_ExportCustomers testa o endpoint GET /api/customers/export.
+// Verifica a exportação em CSV e NDJSON, os filtros repassados ao serviço e as falhas antes e durante a escrita.
+func TestCustomerHandler_ExportCustomers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+
+	createdAt := time.Date(2025, 4, 23, 15, 4, 5, 0, time.UTC)
+	deletedAt := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	customers := []*model.Customer{
+		{ID: 1, Name: "Ana Souza", Email: "ana@example.com", Phone: "(11) 91234-5678", Address: "Rua A, 1", Active: true, CreatedAt: createdAt, UpdatedAt: createdAt},
+		{ID: 2, Name: "Carlos Lima", Email: "carlos@example.com", CreatedAt: createdAt, UpdatedAt: createdAt, DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+	}
+
+	// exportAll simula o serviço chamando fn para cada cliente.
+	exportAll := func(_ context.Context, _ model.CustomerFilter, _ model.Sort, fn func(*model.Customer) error) error {
+		for _, customer := range customers {
+			if err := fn(customer); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Subteste para a exportação em CSV, o formato padrão, com os filtros da listagem.
+	t.Run("CSV", func(t *testing.T) {
+		expectedFilter := model.CustomerFilter{EmailDomain: "example.com", IncludeDeleted: true}
+		expectedSort := model.Sort{{Field: "name", Desc: true}}
+
+		mockService.EXPECT().
+			ExportCustomers(gomock.Any(), expectedFilter, expectedSort, gomock.Any()).
+			DoAndReturn(exportAll).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/export?email_domain=@example.com&include_deleted=true&sort=-name", nil)
+
+		// Verifica o status 200, o arquivo sugerido e uma linha por cliente após o cabeçalho.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="customers.csv"`, recorder.Header().Get("Content-Disposition"))
+		assert.Equal(t, "id,name,email,phone,address,active,created_at,updated_at,deleted_at\n"+
+			"1,Ana Souza,ana@example.com,(11) 91234-5678,\"Rua A, 1\",true,2025-04-23T15:04:05Z,2025-04-23T15:04:05Z,\n"+
+			"2,Carlos Lima,carlos@example.com,,,false,2025-04-23T15:04:05Z,2025-04-23T15:04:05Z,2025-05-01T10:00:00Z\n",
+			recorder.Body.String())
+	})
+
+	// Subteste para a exportação em NDJSON, com um cliente por linha.
+	t.Run("NDJSON", func(t *testing.T) {
+		mockService.EXPECT().
+			ExportCustomers(gomock.Any(), model.CustomerFilter{}, nil, gomock.Any()).
+			DoAndReturn(exportAll).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/export?format=ndjson", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+		lines := strings.Split(strings.TrimSuffix(recorder.Body.String(), "\n"), "\n")
+		if assert.Len(t, lines, 2) {
+			var customer handler.CustomerResponse
+			assert.NoError(t, json.Unmarshal([]byte(lines[1]), &customer))
+			assert.Equal(t, uint(2), customer.ID)
+			assert.Equal(t, deletedAt, *customer.DeletedAt)
+		}
+	})
+
+	// Subteste para a exportação em CSV com campos selecionados, com as colunas na ordem do arquivo completo.
+	t.Run("CSV With Fields", func(t *testing.T) {
+		mockService.EXPECT().
+			ExportCustomers(gomock.Any(), model.CustomerFilter{Fields: model.FieldSet{"name", "id"}}, nil, gomock.Any()).
+			DoAndReturn(exportAll).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/export?fields=name,id", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "id,name\n1,Ana Souza\n2,Carlos Lima\n", recorder.Body.String())
+	})
+
+	// Subteste para a exportação em NDJSON com campos selecionados.
+	t.Run("NDJSON With Fields", func(t *testing.T) {
+		mockService.EXPECT().
+			ExportCustomers(gomock.Any(), model.CustomerFilter{Fields: model.FieldSet{"id", "active"}}, nil, gomock.Any()).
+			DoAndReturn(exportAll).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/export?format=ndjson&fields=id,active", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "{\"id\":1,\"active\":true}\n{\"id\":2,\"active\":false}\n", recorder.Body.String())
+	})
+
+	// Subteste para a exportação sem clientes, que retorna somente o cabeçalho do CSV.
+	t.Run("Empty", func(t *testing.T) {
+		mockService.EXPECT().ExportCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/export?format=csv", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "id,name,email,phone,address,active,created_at,updated_at,deleted_at\n", recorder.Body.String())
+	})
+
+	// Subteste para os parâmetros inválidos, rejeitados antes da consulta.
+	t.Run("Invalid Parameters", func(t *testing.T) {
+		mockService.EXPECT().ExportCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for _, query := range []string{"format=xml", "active=talvez", "sort=password"} {
+			recorder = httptest.NewRecorder()
+			performRequest(router, recorder, http.MethodGet, "/api/customers/export?"+query, nil)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+		}
+	})
+
+	// Subteste para a falha do banco de dados antes do primeiro cliente, ainda respondida como problema.
+	t.Run("Database Unavailable", func(t *testing.T) {
+		mockService.EXPECT().
+			ExportCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(service.ErrDatabaseUnavailable).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/export", nil)
+
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Equal(t, utils.ProblemContentType, recorder.Header().Get("Content-Type"))
+	})
+
+	// Subteste para a falha durante a leitura, após o início da resposta.
+	t.Run("Interrupted", func(t *testing.T) {
+		mockService.EXPECT().
+			ExportCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ model.CustomerFilter, _ model.Sort, fn func(*model.Customer) error) error {
+				assert.NoError(t, fn(customers[0]))
+				return service.ErrDatabaseOperation
+			}).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/export?format=ndjson", nil)
+
+		// Verifica que o conteúdo já escrito é mantido, sem um problema misturado ao arquivo.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, 1, strings.Count(recorder.Body.String(), "\n"))
+		assert.NotContains(t, recorder.Body.String(), "Erro ao exportar clientes")
+	})
+}
+
+// TestCustomerHandler_SearchCustomers testa o endpoint GET /api/customers/search.
+// Verifica os cenários de sucesso na busca por nome, pelo termo geral e pelos termos por campo,
+// falha se nenhum termo for fornecido e falha por erro interno do serviço.
+func TestCustomerHandler_SearchCustomers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+
+	searchName := "Test" // Nome a ser buscado.
+	nameSearch := model.CustomerSearch{Name: searchName, MinScore: service.DefaultMinScore}
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		// Define a lista esperada de clientes encontrados, com o campo de maior pontuação.
+		expectedMatches := []*model.CustomerMatch{
+			{Customer: &model.Customer{ID: 1, Name: "Test User 1", Email: "test1@example.com"}, MatchedField: model.SearchFieldName, Score: 0.8},
+			{Customer: &model.Customer{ID: 3, Name: "Test User 3", Email: "test3@example.com"}, MatchedField: model.SearchFieldName, Score: 0.5},
+		}
+
+		// Define a expectativa: SearchCustomers será chamado com o nome correto e retornará a lista.
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), nameSearch, nil, model.Pagination{}). // Espera o nome e a pontuação mínima padrão.
+			Return(expectedMatches, nil).                                       // Retorna a lista encontrada e nenhum erro.
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		// Executa a requisição GET com o parâmetro de query 'name'.
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName, nil)
+
+		// Verifica o status 200 OK.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		// Verifica o corpo da resposta: os dados do cliente acompanhados do campo encontrado e da pontuação.
+		var results []handler.CustomerSearchResult
+		err := json.Unmarshal(recorder.Body.Bytes(), &results)
+		assert.NoError(t, err)
+		if assert.Len(t, results, 2) {
+			assert.Equal(t, uint(1), results[0].ID)
+			assert.Equal(t, "Test User 1", results[0].Name)
+			assert.Equal(t, "name", results[0].MatchedField)
+			assert.Equal(t, 0.8, results[0].Score)
+			assert.Equal(t, uint(3), results[1].ID)
+		}
+	})
+
+	// Subteste para o cenário com campos selecionados: o cliente é limitado aos campos, e o campo encontrado
+	// e a pontuação são mantidos.
+	t.Run("With Fields", func(t *testing.T) {
+		expectedSearch := nameSearch
+		expectedSearch.Fields = model.FieldSet{"id", "name"}
+
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), expectedSearch, nil, model.Pagination{}).
+			Return([]*model.CustomerMatch{{Customer: &model.Customer{ID: 1, Name: "Test User 1", Email: "test1@example.com"}, MatchedField: model.SearchFieldName, Score: 0.8}}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName+"&fields=id,name", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `[{"id":1,"name":"Test User 1","matched_field":"name","score":0.8}]`, recorder.Body.String())
+	})
+
+	// Subteste para o cenário de busca pelo termo geral.
+	t.Run("General Query", func(t *testing.T) {
+		matches := []*model.CustomerMatch{
+			{Customer: &model.Customer{ID: 5, Name: "Ana", Phone: "(11) 98765-4321"}, MatchedField: model.SearchFieldPhone, Score: 1},
+		}
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), model.CustomerSearch{Query: "98765-4321", MinScore: service.DefaultMinScore}, nil, model.Pagination{}).
+			Return(matches, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?q=98765-4321", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"matched_field":"phone"`)
+		assert.NotContains(t, recorder.Body.String(), "headline")
+	})
+
+	// Subteste para o cenário de busca pelos termos por campo, com o telefone normalizado para os dígitos.
+	t.Run("Field Scoped Terms", func(t *testing.T) {
+		expectedSearch := model.CustomerSearch{Email: "joao@", Phone: "1198765", MinScore: service.DefaultMinScore}
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), expectedSearch, nil, model.Pagination{}).
+			Return([]*model.CustomerMatch{}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?email=joao@&phone=(11)%2098765", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "[]", recorder.Body.String())
+	})
+
+	// Subteste para o cenário de busca textual, com a pontuação e o trecho destacado de cada resultado.
+	t.Run("Full Text", func(t *testing.T) {
+		matches := []*model.CustomerMatch{{
+			Customer:     &model.Customer{ID: 8, Name: "Bruna Costa", Address: "Rua das Flores, 100"},
+			MatchedField: model.SearchFieldAddress,
+			Score:        0.09,
+			Headline:     "Bruna Costa - Rua das <b>Flores</b>, 100",
+		}}
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), model.CustomerSearch{FullText: `"rua das flores" -centro`, MinScore: service.DefaultMinScore}, nil, model.Pagination{}).
+			Return(matches, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?fulltext=%22rua%20das%20flores%22%20-centro", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var results []handler.CustomerSearchResult
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &results))
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, "address", results[0].MatchedField)
+			assert.Equal(t, 0.09, results[0].Score)
+			assert.Equal(t, "Bruna Costa - Rua das <b>Flores</b>, 100", results[0].Headline)
+		}
+	})
+
+	// Subteste para os cenários de telefone sem dígitos ou com menos dígitos que o mínimo, que corresponderia a
+	// quase todos os clientes.
+	t.Run("Invalid Phone", func(t *testing.T) {
+		mockService.EXPECT().SearchCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for _, phone := range []string{"abc", "9", "(1)-1"} {
+			recorder = httptest.NewRecorder()
+			performRequest(router, recorder, http.MethodGet, "/api/customers/search?phone="+url.QueryEscape(phone), nil)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, phone)
+			assert.Contains(t, recorder.Body.String(), "ao menos 3 dígitos", phone)
+		}
+	})
+
+	// Subteste para as buscas pelo termo geral, pelo email e pelo telefone, paginadas como as demais.
+	t.Run("Field Scoped Pagination", func(t *testing.T) {
+		pagination := model.Pagination{Page: 2, PageSize: 50}
+		for query, expectedSearch := range map[string]model.CustomerSearch{
+			"q=silva":       {Query: "silva", MinScore: service.DefaultMinScore},
+			"email=example": {Email: "example", MinScore: service.DefaultMinScore},
+			"phone=987":     {Phone: "987", MinScore: service.DefaultMinScore},
+		} {
+			mockService.EXPECT().
+				SearchCustomers(gomock.Any(), expectedSearch, nil, pagination).
+				Return([]*model.CustomerMatch{}, nil).
+				Times(1)
+
+			recorder = httptest.NewRecorder()
+			performRequest(router, recorder, http.MethodGet, "/api/customers/search?"+query+"&page=2&page_size=50", nil)
+
+			assert.Equal(t, http.StatusOK, recorder.Code, query)
+		}
+	})
+
+	// Subteste para o cenário de busca com ordenação.
+	t.Run("With Sort", func(t *testing.T) {
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), nameSearch, model.Sort{{Field: "name"}, {Field: "updated_at", Desc: true}}, model.Pagination{}).
+			Return([]*model.CustomerMatch{}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName+"&sort=name,-updated_at", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	// Subteste para o cenário de busca com pontuação mínima.
+	t.Run("With Min Score", func(t *testing.T) {
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), model.CustomerSearch{Name: "Joao", MinScore: 0.6}, nil, model.Pagination{}).
+			Return([]*model.CustomerMatch{}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name=Joao&min_score=0.6", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	// Subteste para o cenário de pontuação mínima inválida.
+	t.Run("Invalid Min Score", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName+"&min_score=alta", nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "min_score")
+	})
+
+	// Subteste para o cenário de pontuação mínima fora do intervalo, rejeitada pelo serviço.
+	t.Run("Min Score Out Of Range", func(t *testing.T) {
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), model.CustomerSearch{Name: searchName, MinScore: 2.0}, nil, model.Pagination{}).
+			Return(nil, service.ErrInvalidMinScore).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName+"&min_score=2", nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrInvalidMinScore.Error())
+	})
+
+	// Subteste para o cenário de pontuação mínima zero, que retornaria todos os clientes e é rejeitada pelo serviço.
+	t.Run("Min Score Zero", func(t *testing.T) {
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), model.CustomerSearch{Name: searchName}, nil, model.Pagination{}).
+			Return(nil, service.ErrInvalidMinScore).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName+"&min_score=0", nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrInvalidMinScore.Error())
+	})
+
+	// Subteste para o cenário de busca paginada: a página e a quantidade de registros são repassadas ao serviço,
+	// que aplica o limite máximo.
+	t.Run("With Pagination", func(t *testing.T) {
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), nameSearch, nil, model.Pagination{Page: 2, PageSize: 500}).
+			Return([]*model.CustomerMatch{}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName+"&page=2&page_size=500", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	// Subteste para os cenários de paginação inválida, incluindo a paginação por cursor, não aceita na busca.
+	t.Run("Invalid Pagination", func(t *testing.T) {
+		mockService.EXPECT().SearchCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		for _, query := range []string{"page=0", "page_size=-1", "cursor=abc"} {
+			recorder = httptest.NewRecorder()
+			performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName+"&"+query, nil)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+		}
+	})
+
+	// Subteste para o cenário de busca com campo de ordenação inválido.
+	t.Run("Invalid Sort", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName+"&sort=address", nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "allowed_values")
+	})
+
+	// Subteste para o cenário onde nenhum termo de busca é fornecido.
+	t.Run("Search Terms Not Provided", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		// Executa a requisição sem os parâmetros de busca.
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name=%20", nil)
+
+		// Verifica o status 400 Bad Request.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		// Verifica a mensagem de erro específica.
+		assert.Contains(t, recorder.Body.String(), "informe ao menos um dos parâmetros q, name, email, phone ou fulltext")
+	})
+
+	// Subteste para o cenário de erro interno do serviço.
+	t.Run("Service Internal Error", func(t *testing.T) {
+		serviceErr := errors.New("search failed") // Erro genérico simulado.
+
+		// Define a expectativa: SearchCustomers será chamado e retornará um erro interno.
+		mockService.EXPECT().
+			SearchCustomers(gomock.Any(), nameSearch, nil, model.Pagination{}).
+			Return(nil, serviceErr). // Retorna nil para a lista e o erro genérico.
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/customers/search?name="+searchName, nil)
+
+		// Verifica o status 500 Internal Server Error.
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		// Verifica a mensagem de erro genérica do handler.
+		assert.Contains(t, recorder.Body.String(), "Erro ao buscar clientes")
+	})
+}
+
+// TestCustomerHandler_UpdateCustomer testa o endpoint PUT /api/customers/{id}.
+// Verifica os cenários de sucesso na atualização, falha por ID inválido, JSON inválido,
+// erro de validação do serviço, erro de cliente não encontrado pelo serviço e erro interno do serviço.
+func TestCustomerHandler_UpdateCustomer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+
+	testID := uint(456)
+	testIDStr := strconv.FormatUint(uint64(testID), 10)
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		// Define os dados de atualização e o resultado esperado.
+		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
+		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+		// O cliente esperado na resposta deve ter o ID da URL e os dados do corpo.
+		expectedUpdatedCustomer := model.Customer{ID: testID, Name: "Updated Name", Email: "updated@example.com"}
+
+		// Define a expectativa: UpdateCustomer será chamado.
+		mockService.EXPECT().
+			UpdateCustomer(gomock.Any(), gomock.Any(), uint(0)).
+			DoAndReturn(func(ctx context.Context, c *model.Customer, expectedVersion uint) error {
+				// Verifica se o ID da URL foi corretamente atribuído ao objeto Customer passado para o serviço.
+				assert.Equal(t, testID, c.ID)
+				// Verifica se os dados do corpo foram corretamente passados.
+				assert.Equal(t, customerUpdateInput.Name, c.Name)
+				assert.Equal(t, customerUpdateInput.Email, c.Email)
+				// Simula o sucesso da atualização no serviço, que preenche a nova versão do cliente.
+				// O handler retorna o objeto 'c' como ele foi passado (com ID setado).
+				c.Version = 2
+				return nil
+			}).Times(1)
+
+		recorder = httptest.NewRecorder()
+		// Executa a requisição PUT com o ID e o corpo JSON.
+		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON)
+
+		// Verifica o status 200 OK e a ETag com a nova versão.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"2-json"`, recorder.Header().Get("ETag"))
+
 		// Verifica o corpo da resposta.
 		var updatedCustomer model.Customer
 		err := json.Unmarshal(recorder.Body.Bytes(), &updatedCustomer)
 		assert.NoError(t, err)
-		// Compara o cliente retornado com o esperado.
-		assert.Equal(t, expectedUpdatedCustomer, updatedCustomer)
+		// Compara o cliente retornado com o esperado.
+		assert.Equal(t, expectedUpdatedCustomer, updatedCustomer)
+	})
+
+	// Subteste para o cenário em que o corpo informa um ID diferente do ID da URL.
+	t.Run("Body ID Ignored", func(t *testing.T) {
+		body := []byte(`{"id": 99, "name": "Updated Name", "email": "updated@example.com", "created_at": "2020-01-01T00:00:00Z"}`)
+
+		// Define a expectativa: o serviço recebe o ID da URL e nenhuma data informada pelo cliente.
+		mockService.EXPECT().
+			UpdateCustomer(gomock.Any(), gomock.Any(), uint(0)).
+			DoAndReturn(func(ctx context.Context, c *model.Customer, expectedVersion uint) error {
+				assert.Equal(t, testID, c.ID)
+				assert.True(t, c.CreatedAt.IsZero())
+				return nil
+			}).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, body)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"id":`+testIDStr)
+	})
+
+	// Subteste para o cenário de formato de ID inválido na URL.
+	t.Run("Invalid ID Format", func(t *testing.T) {
+		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
+		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+
+		recorder = httptest.NewRecorder()
+		// Executa a requisição com um ID não numérico.
+		performRequest(router, recorder, http.MethodPut, "/api/customers/xyz", customerUpdateInputJSON)
+
+		// Verifica o status 400 Bad Request.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "ID inválido")
+	})
+
+	// Subteste para o cenário de JSON inválido no corpo da requisição.
+	t.Run("Invalid JSON Body", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		// Envia um corpo JSON malformado.
+		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, []byte(`{"invalid`))
+
+		// Verifica o status 400 Bad Request.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Dados inválidos")
+	})
+
+	// Subteste para o cenário onde o serviço retorna um erro de validação.
+	t.Run("Service Validation Error", func(t *testing.T) {
+		// Prepara uma entrada inválida (ex: nome vazio).
+		customerUpdateInput := model.Customer{Name: ""}
+		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+
+		// Define a expectativa: UpdateCustomer será chamado e retornará erro de validação.
+		mockService.EXPECT().
+			UpdateCustomer(gomock.Any(), gomock.Any(), uint(0)).
+			Return(service.ErrInvalidCustomer).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON)
+
+		// Verifica o status 400 Bad Request.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrInvalidCustomer.Error())
+	})
+
+	// Subteste para o cenário onde o serviço não encontra o cliente para atualizar.
+	t.Run("Service Not Found Error", func(t *testing.T) {
+		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
+		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+
+		// Define a expectativa: UpdateCustomer será chamado e retornará ErrCustomerNotFound.
+		mockService.EXPECT().
+			UpdateCustomer(gomock.Any(), gomock.Any(), uint(0)).
+			Return(service.ErrCustomerNotFound).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON)
+
+		// Verifica o status 404 Not Found.
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrCustomerNotFound.Error())
+	})
+
+	// Subteste para o cenário de erro interno do serviço.
+	t.Run("Service Internal Error", func(t *testing.T) {
+		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
+		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+		serviceErr := errors.New("database update failed") // Erro genérico simulado.
+
+		// Define a expectativa: UpdateCustomer será chamado e retornará um erro interno.
+		mockService.EXPECT().
+			UpdateCustomer(gomock.Any(), gomock.Any(), uint(0)).
+			Return(serviceErr).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON)
+
+		// Verifica o status 500 Internal Server Error.
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Erro ao atualizar cliente")
+	})
+
+	// Subteste para o cenário de email já utilizado por outro cliente.
+	t.Run("Already Exists", func(t *testing.T) {
+		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "taken@example.com"}
+		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+
+		mockService.EXPECT().
+			UpdateCustomer(gomock.Any(), gomock.Any(), uint(0)).
+			Return(&service.AlreadyExistsError{Field: "email"}).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON)
+
+		// Verifica o status 409 Conflict e o campo em conflito.
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"field":"email"`)
+	})
+
+	// Subteste para o cenário onde a versão informada em If-Match é repassada ao serviço e está desatualizada.
+	t.Run("If-Match Version Conflict", func(t *testing.T) {
+		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
+		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+
+		// Define a expectativa: UpdateCustomer será chamado com a versão da ETag e retornará conflito.
+		mockService.EXPECT().
+			UpdateCustomer(gomock.Any(), gomock.Any(), uint(3)).
+			Return(service.ErrVersionConflict).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON, map[string]string{"If-Match": `"3"`})
+
+		// Verifica o status 412 Precondition Failed.
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrVersionConflict.Error())
+	})
+
+	// Subteste para a ETag de qualquer representação em If-Match: somente a versão é comparada.
+	t.Run("If-Match Any Representation", func(t *testing.T) {
+		customerUpdateInputJSON := []byte(`{"name": "Updated Name", "email": "updated@example.com"}`)
+
+		for _, ifMatch := range []string{`"3-xml"`, `"3-json-id.name"`} {
+			mockService.EXPECT().UpdateCustomer(gomock.Any(), gomock.Any(), uint(3)).Return(service.ErrVersionConflict).Times(1)
+
+			recorder = httptest.NewRecorder()
+			performRequestWithHeaders(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON, map[string]string{"If-Match": ifMatch})
+
+			assert.Equal(t, http.StatusPreconditionFailed, recorder.Code, ifMatch)
+		}
+	})
+
+	// Subteste para ETags que nunca correspondem a uma versão, rejeitadas sem chamar o serviço.
+	t.Run("If-Match Never Matches", func(t *testing.T) {
+		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
+		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+
+		for _, ifMatch := range []string{`W/"3"`, `"abc"`} {
+			recorder = httptest.NewRecorder()
+			performRequestWithHeaders(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON, map[string]string{"If-Match": ifMatch})
+
+			assert.Equal(t, http.StatusPreconditionFailed, recorder.Code, ifMatch)
+		}
+	})
+
+	// Subteste para o cenário de cabeçalho If-Match mal formado.
+	t.Run("Invalid If-Match", func(t *testing.T) {
+		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
+		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+
+		for _, ifMatch := range []string{`3`, `"3", "4"`} {
+			recorder = httptest.NewRecorder()
+			performRequestWithHeaders(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON, map[string]string{"If-Match": ifMatch})
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, ifMatch)
+			assert.Contains(t, recorder.Body.String(), "If-Match")
+		}
+	})
+}
+
+// TestCustomerHandler_PatchCustomer testa o endpoint PATCH /api/customers/{id}.
+// Verifica os cenários de sucesso com JSON Merge Patch e JSON Patch, Content-Type não suportado,
+// patch inválido, operação test não satisfeita, cliente não encontrado e erro interno do serviço.
+func TestCustomerHandler_PatchCustomer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+
+	testID := uint(321)
+	testIDStr := strconv.FormatUint(uint64(testID), 10)
+	patch := []byte(`{"active": false}`)
+	mergePatch := map[string]string{"Content-Type": "application/merge-patch+json"}
+	jsonPatch := map[string]string{"Content-Type": "application/json-patch+json"}
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		expectedCustomer := &model.Customer{ID: testID, Name: "Patched User", Email: "patched@example.com", Active: false}
+
+		// Define a expectativa: PatchCustomer será chamado com o ID da URL, a versão de If-Match, o tipo e o documento recebido.
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, uint(0), service.MergePatch, patch).
+			Return(expectedCustomer, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, patch, mergePatch)
+
+		// Verifica o status 200 OK e o cliente atualizado.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var patchedCustomer model.Customer
+		err := json.Unmarshal(recorder.Body.Bytes(), &patchedCustomer)
+		assert.NoError(t, err)
+		assert.Equal(t, *expectedCustomer, patchedCustomer)
+	})
+
+	// Subteste para o cenário de sucesso com JSON Patch.
+	t.Run("JSON Patch Success", func(t *testing.T) {
+		operations := []byte(`[{"op": "replace", "path": "/active", "value": false}]`)
+		expectedCustomer := &model.Customer{ID: testID, Name: "Patched User", Email: "patched@example.com", Active: false}
+
+		// Define a expectativa: o Content-Type determina o tipo de patch repassado ao serviço.
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, uint(0), service.JSONPatch, operations).
+			Return(expectedCustomer, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, operations, jsonPatch)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	// Subteste para o cenário onde uma operação test do JSON Patch não é satisfeita.
+	t.Run("JSON Patch Test Failed", func(t *testing.T) {
+		operations := []byte(`[{"op": "test", "path": "/active", "value": false}]`)
+
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, uint(0), service.JSONPatch, operations).
+			Return(nil, service.ErrPatchTestFailed).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, operations, jsonPatch)
+
+		// Verifica o status 409 Conflict.
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrPatchTestFailed.Error())
+	})
+
+	// Subteste para o cenário onde o cliente foi alterado desde a versão informada em If-Match.
+	t.Run("If-Match Version Conflict", func(t *testing.T) {
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, uint(5), service.MergePatch, patch).
+			Return(nil, service.ErrVersionConflict).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, patch,
+			map[string]string{"Content-Type": "application/merge-patch+json", "If-Match": `"5"`})
+
+		// Verifica o status 412 Precondition Failed.
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrVersionConflict.Error())
+	})
+
+	// Subteste para o cenário de Content-Type diferente dos tipos de patch suportados.
+	t.Run("Unsupported Content Type", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, patch)
+
+		// Verifica o status 415 Unsupported Media Type.
+		assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "application/merge-patch+json")
+		assert.Contains(t, recorder.Body.String(), "application/json-patch+json")
+	})
+
+	// Subteste para o cenário de formato de ID inválido na URL.
+	t.Run("Invalid ID Format", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/abc", patch, mergePatch)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "ID inválido")
+	})
+
+	// Subteste para o cenário de documento de patch inválido.
+	t.Run("Invalid Patch", func(t *testing.T) {
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrInvalidPatch).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, []byte(`{"id": 1}`), mergePatch)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrInvalidPatch.Error())
+	})
+
+	// Subteste para o cenário de patch sobre um campo gerenciado pelo servidor: o campo é informado no problema.
+	t.Run("Server Managed Field", func(t *testing.T) {
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, uint(0), service.MergePatch, []byte(`{"deleted_at": null}`)).
+			Return(nil, fmt.Errorf("%w: o campo /deleted_at não pode ser alterado", service.ErrInvalidPatch)).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, []byte(`{"deleted_at": null}`), mergePatch)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "/deleted_at")
+	})
+
+	// Subteste para o cenário de null em um campo que não pode ser removido: o patch é rejeitado com 400, e o
+	// valor não é gravado como false.
+	t.Run("Null Required Field", func(t *testing.T) {
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, uint(0), service.MergePatch, []byte(`{"active": null}`)).
+			Return(nil, fmt.Errorf("%w: o campo /active não pode ser removido nem receber null", service.ErrInvalidPatch)).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, []byte(`{"active": null}`), mergePatch)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "/active não pode ser removido")
+	})
+
+	// Subteste para o cenário onde o resultado do patch não passa na validação.
+	t.Run("Service Validation Error", func(t *testing.T) {
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrInvalidCustomer).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, []byte(`{"name": "Jo"}`), mergePatch)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrInvalidCustomer.Error())
+	})
+
+	// Subteste para o cenário onde o cliente não é encontrado.
+	t.Run("Not Found", func(t *testing.T) {
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrCustomerNotFound).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, patch, mergePatch)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrCustomerNotFound.Error())
+	})
+
+	// Subteste para o cenário de erro interno do serviço.
+	t.Run("Service Internal Error", func(t *testing.T) {
+		mockService.EXPECT().
+			PatchCustomer(gomock.Any(), testID, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("database update failed")).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPatch, "/api/customers/"+testIDStr, patch, mergePatch)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Erro ao atualizar cliente")
+	})
+}
+
+// TestCustomerHandler_DeleteCustomer testa o endpoint DELETE /api/customers/{id}.
+// Verifica os cenários de sucesso na exclusão, falha por ID inválido,
+// falha quando o cliente não é encontrado e falha por erro interno do serviço.
+func TestCustomerHandler_DeleteCustomer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+
+	testID := uint(789)
+	testIDStr := strconv.FormatUint(uint64(testID), 10)
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		// Define a expectativa: DeleteCustomer será chamado com o ID correto e retornará nil (sucesso).
+		mockService.EXPECT().
+			DeleteCustomer(gomock.Any(), testID, uint(0)).
+			Return(nil). // Simula exclusão bem-sucedida.
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		// Executa a requisição DELETE.
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr, nil)
+
+		// Verifica o status 204 No Content.
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		// Verifica que o corpo da resposta está vazio.
+		assert.Empty(t, recorder.Body.String())
+	})
+
+	// Subteste para o cenário onde o cliente foi alterado desde a versão informada em If-Match.
+	t.Run("If-Match Version Conflict", func(t *testing.T) {
+		// Define a expectativa: DeleteCustomer será chamado com a versão da ETag e retornará conflito.
+		mockService.EXPECT().
+			DeleteCustomer(gomock.Any(), testID, uint(7)).
+			Return(service.ErrVersionConflict).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr, nil, map[string]string{"If-Match": `"7"`})
+
+		// Verifica o status 412 Precondition Failed.
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrVersionConflict.Error())
+	})
+
+	// Subteste para o cenário de formato de ID inválido na URL.
+	t.Run("Invalid ID Format", func(t *testing.T) {
+		recorder = httptest.NewRecorder()
+		// Executa a requisição com um ID não numérico.
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/invalid-id", nil)
+
+		// Verifica o status 400 Bad Request.
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "ID inválido")
+	})
+
+	// Subteste para o cenário onde o cliente a ser excluído não é encontrado.
+	t.Run("Not Found", func(t *testing.T) {
+		// Define a expectativa: DeleteCustomer será chamado e retornará ErrCustomerNotFound.
+		mockService.EXPECT().
+			DeleteCustomer(gomock.Any(), testID, uint(0)).
+			Return(service.ErrCustomerNotFound). // Simula cliente não encontrado.
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr, nil)
+
+		// Verifica o status 404 Not Found.
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrCustomerNotFound.Error())
+	})
+
+	// Subteste para o cenário de cliente inexistente ou já excluído com If-Match: 404, e não 412.
+	t.Run("If-Match Not Found", func(t *testing.T) {
+		mockService.EXPECT().
+			DeleteCustomer(gomock.Any(), testID, uint(7)).
+			Return(service.ErrCustomerNotFound).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr, nil, map[string]string{"If-Match": `"7-json"`})
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrCustomerNotFound.Error())
+	})
+
+	// Subteste para o cenário de erro interno do serviço.
+	t.Run("Service Internal Error", func(t *testing.T) {
+		serviceErr := errors.New("database delete failed") // Erro genérico simulado.
+
+		// Define a expectativa: DeleteCustomer será chamado e retornará um erro interno.
+		mockService.EXPECT().
+			DeleteCustomer(gomock.Any(), testID, uint(0)).
+			Return(serviceErr). // Simula erro interno.
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr, nil)
+
+		// Verifica o status 500 Internal Server Error.
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Erro ao excluir cliente")
+	})
+}
+
+// TestCustomerHandler_ImportCustomers testa o endpoint POST /api/customers/import.
+// Verifica a leitura do arquivo CSV, o relatório das linhas rejeitadas e os erros de cabeçalho e do serviço.
+func TestCustomerHandler_ImportCustomers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+	csvHeaders := map[string]string{"Content-Type": "text/csv; charset=utf-8"}
+
+	// readRows lê todas as linhas da fonte recebida pelo serviço.
+	readRows := func(source service.ImportSource) []service.ImportRow {
+		var rows []service.ImportRow
+		for {
+			row, err := source.Next()
+			if err != nil {
+				return rows
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	// Subteste para a importação com colunas em ordem livre e linhas rejeitadas.
+	t.Run("Success", func(t *testing.T) {
+		file := "\ufeffEmail,Name,Active\n" +
+			"ana@example.com,Ana Souza,\n" +
+			"carlos@example.com,Carlos Lima,nao\n" +
+			"ana@example.com,Ana Repetida,true\n" +
+			"sem-campos\n"
+
+		// Define a expectativa: o serviço recebe as linhas convertidas e rejeita o email repetido.
+		mockService.EXPECT().
+			ImportCustomers(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, source service.ImportSource) (*service.ImportReport, error) {
+				rows := readRows(source)
+				if assert.Len(t, rows, 4) {
+					assert.Equal(t, service.ImportRow{Line: 2, Customer: &model.Customer{Name: "Ana Souza", Email: "ana@example.com", Active: true}}, rows[0])
+					assert.Equal(t, 3, rows[1].Line)
+					assert.EqualError(t, rows[1].Err, "active: deve ser true ou false")
+					assert.Equal(t, 4, rows[2].Line)
+					assert.Equal(t, 5, rows[3].Line)
+					assert.ErrorContains(t, rows[3].Err, "quantidade de campos")
+				}
+				return &service.ImportReport{Imported: 1, Rejected: []service.ImportRejection{
+					{Line: 3, Err: rows[1].Err},
+					{Line: 4, Err: &service.AlreadyExistsError{Field: "email"}},
+					{Line: 5, Err: rows[3].Err},
+				}}, nil
+			}).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPost, "/api/customers/import", []byte(file), csvHeaders)
+
+		// Verifica o status 200, as contagens e o relatório das linhas rejeitadas.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Header().Get("Content-Disposition"), "attachment")
+		assert.Equal(t, "1", recorder.Header().Get("X-Imported-Count"))
+		assert.Equal(t, "3", recorder.Header().Get("X-Rejected-Count"))
+		assert.Equal(t, "line,reason\n"+
+			"3,active: deve ser true ou false\n"+
+			"4,já existe um cliente com os mesmos dados (email: já utilizado por outro cliente)\n"+
+			"5,linha malformada: quantidade de campos diferente do cabeçalho\n", recorder.Body.String())
+	})
+
+	// Subteste para o motivo de rejeição de um cliente inválido.
+	t.Run("Validation Reason", func(t *testing.T) {
+		mockService.EXPECT().
+			ImportCustomers(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, source service.ImportSource) (*service.ImportReport, error) {
+				rows := readRows(source)
+				return &service.ImportReport{Rejected: []service.ImportRejection{
+					{Line: 2, Err: &service.ValidationError{Errors: rows[0].Customer.Validate().(validator.ValidationErrors)}},
+				}}, nil
+			}).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPost, "/api/customers/import", []byte("name,email\nAn,invalido\n"), csvHeaders)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "2,dados do cliente inválidos (name: deve ter no mínimo 3 caracteres; email: deve ser um email válido)")
+	})
+
+	// Subteste para a importação com o serviço real sobre o repositório mockado: a linha com active=false chega ao
+	// repositório como cliente inativo.
+	t.Run("Inactive Row Stored", func(t *testing.T) {
+		mockRepo := mock_repository.NewMockCustomerRepository(mockCtrl)
+		storedRouter, _ := setupTestRouter(t, service.NewCustomerService(mockRepo))
+		file := "name,email,active\n" +
+			"Ana Souza,ana@example.com,false\n" +
+			"Carlos Lima,carlos@example.com,true\n"
+
+		mockRepo.EXPECT().
+			CreateBatch(gomock.Any(), gomock.Any(), false).
+			DoAndReturn(func(_ context.Context, customers []*model.Customer, _ bool) ([]error, error) {
+				if assert.Len(t, customers, 2) {
+					assert.False(t, customers[0].Active)
+					assert.True(t, customers[1].Active)
+				}
+				return make([]error, len(customers)), nil
+			}).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(storedRouter, recorder, http.MethodPost, "/api/customers/import", []byte(file), csvHeaders)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "2", recorder.Header().Get("X-Imported-Count"))
+	})
+
+	// Subteste para os cabeçalhos inválidos, que impedem a leitura do arquivo.
+	t.Run("Invalid Header", func(t *testing.T) {
+		mockService.EXPECT().ImportCustomers(gomock.Any(), gomock.Any()).Times(0)
+
+		for file, detail := range map[string]string{
+			"":                      "cabeçalho",
+			"name,email,document\n": `coluna \"document\" não é aceita`,
+			"name,email,NAME\n":     `coluna \"name\" está repetida`,
+			"name,phone\n":          `coluna \"email\" é obrigatória`,
+		} {
+			recorder = httptest.NewRecorder()
+			performRequestWithHeaders(router, recorder, http.MethodPost, "/api/customers/import", []byte(file), csvHeaders)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), detail)
+		}
+	})
+
+	// Subteste para o cenário de Content-Type diferente de text/csv.
+	t.Run("Unsupported Content Type", func(t *testing.T) {
+		mockService.EXPECT().ImportCustomers(gomock.Any(), gomock.Any()).Times(0)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers/import", []byte(`{"name": "Ana"}`))
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+	})
+
+	// Subteste para o cenário de indisponibilidade do banco de dados.
+	t.Run("Database Unavailable", func(t *testing.T) {
+		mockService.EXPECT().
+			ImportCustomers(gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrDatabaseUnavailable).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPost, "/api/customers/import", []byte("name,email\nAna Souza,ana@example.com\n"), csvHeaders)
+
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Equal(t, "5", recorder.Header().Get("Retry-After"))
+	})
+}
+
+// TestCustomerHandler_UpdateCustomers testa o endpoint PATCH /api/customers/bulk.
+// Verifica a seleção por filtro e por IDs, a simulação com dry_run e os erros de requisição e do serviço.
+func TestCustomerHandler_UpdateCustomers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+	inactive := false
+
+	// Subteste para a desativação dos clientes de um domínio de email.
+	t.Run("Success By Filter", func(t *testing.T) {
+		createdAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		expectedSelection := model.BulkSelection{Filter: model.CustomerFilter{EmailDomain: "partner.com", CreatedAfter: &createdAfter}}
+
+		// Define a expectativa: o filtro é normalizado como na listagem.
+		mockService.EXPECT().
+			UpdateCustomers(gomock.Any(), expectedSelection, model.CustomerChanges{Active: &inactive}, false).
+			Return(&model.BulkResult{Matched: 12, Affected: 10}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPatch, "/api/customers/bulk",
+			[]byte(`{"filter": {"email_domain": "@partner.com", "created_after": "2025-01-01"}, "set": {"active": false}}`))
+
+		// Verifica o status 200 e as contagens.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response handler.BulkResultResponse
+		err := json.Unmarshal(recorder.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, handler.BulkResultResponse{Matched: 12, Affected: 10}, response)
+	})
+
+	// Subteste para a simulação da alteração de uma lista de IDs.
+	t.Run("Dry Run", func(t *testing.T) {
+		mockService.EXPECT().
+			UpdateCustomers(gomock.Any(), model.BulkSelection{IDs: []uint{1, 2, 3}}, model.CustomerChanges{Active: &inactive}, true).
+			Return(&model.BulkResult{Matched: 3, Affected: 2, DryRun: true}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPatch, "/api/customers/bulk?dry_run=true", []byte(`{"ids": [1, 2, 3], "set": {"active": false}}`))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"dry_run": true, "matched": 3, "affected": 2}`, recorder.Body.String())
+	})
+
+	// Subteste para o cenário de dry_run inválido.
+	t.Run("Invalid Dry Run", func(t *testing.T) {
+		mockService.EXPECT().UpdateCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPatch, "/api/customers/bulk?dry_run=maybe", []byte(`{"ids": [1], "set": {"active": false}}`))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	// Subteste para o cenário de filtro inválido.
+	t.Run("Invalid Filter", func(t *testing.T) {
+		mockService.EXPECT().UpdateCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPatch, "/api/customers/bulk", []byte(`{"filter": {"created_after": "ontem"}, "set": {"active": false}}`))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	// Subteste para a desativação dos clientes selecionados pela expressão de filtro, combinada com os demais critérios.
+	t.Run("Success By Expression", func(t *testing.T) {
+		expression, err := rsql.Parse("name=like=Silva*,email=like=*@corp.com", model.FilterableCustomerFields)
+		assert.NoError(t, err)
+		expectedSelection := model.BulkSelection{Filter: model.CustomerFilter{PhonePrefix: "11", Expression: expression}}
+
+		mockService.EXPECT().
+			UpdateCustomers(gomock.Any(), expectedSelection, model.CustomerChanges{Active: &inactive}, false).
+			Return(&model.BulkResult{Matched: 4, Affected: 4}, nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPatch, "/api/customers/bulk",
+			[]byte(`{"filter": {"phone_prefix": "11", "expression": "name=like=Silva*,email=like=*@corp.com"}, "set": {"active": false}}`))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"dry_run": false, "matched": 4, "affected": 4}`, recorder.Body.String())
 	})
 
-	// Subteste para o cenário de formato de ID inválido na URL.
-	t.Run("Invalid ID Format", func(t *testing.T) {
-		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
-		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+	// Subteste para o cenário de expressão de filtro inválida, com a posição do erro, como na listagem.
+	t.Run("Invalid Filter Expression", func(t *testing.T) {
+		mockService.EXPECT().UpdateCustomers(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
 		recorder = httptest.NewRecorder()
-		// Executa a requisição com um ID não numérico.
-		performRequest(router, recorder, http.MethodPut, "/api/customers/xyz", customerUpdateInputJSON)
+		performRequest(router, recorder, http.MethodPatch, "/api/customers/bulk",
+			[]byte(`{"filter": {"expression": "active==true;(name==Ana"}, "set": {"active": false}}`))
 
-		// Verifica o status 400 Bad Request.
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
-		assert.Contains(t, recorder.Body.String(), "ID inválido")
+		var problem utils.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		assert.Equal(t, 24, problem.Position)
+		assert.Contains(t, problem.Detail, "expressão de filtro inválida na posição 24")
 	})
 
-	// Subteste para o cenário de JSON inválido no corpo da requisição.
-	t.Run("Invalid JSON Body", func(t *testing.T) {
+	// Subteste para o cenário em que o serviço rejeita a seleção.
+	t.Run("Invalid Selection", func(t *testing.T) {
+		mockService.EXPECT().
+			UpdateCustomers(gomock.Any(), model.BulkSelection{}, model.CustomerChanges{Active: &inactive}, false).
+			Return(nil, service.ErrInvalidBulkSelection).
+			Times(1)
+
 		recorder = httptest.NewRecorder()
-		// Envia um corpo JSON malformado.
-		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, []byte(`{"invalid`))
+		performRequest(router, recorder, http.MethodPatch, "/api/customers/bulk", []byte(`{"set": {"active": false}}`))
 
-		// Verifica o status 400 Bad Request.
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
-		assert.Contains(t, recorder.Body.String(), "Dados inválidos")
+		assert.Contains(t, recorder.Body.String(), service.ErrInvalidBulkSelection.Error())
 	})
 
-	// Subteste para o cenário onde o serviço retorna um erro de validação.
-	t.Run("Service Validation Error", func(t *testing.T) {
-		// Prepara uma entrada inválida (ex: nome vazio).
-		customerUpdateInput := model.Customer{Name: ""}
-		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+	// Subteste para o cenário de erro interno do serviço.
+	t.Run("Service Internal Error", func(t *testing.T) {
+		mockService.EXPECT().
+			UpdateCustomers(gomock.Any(), gomock.Any(), gomock.Any(), false).
+			Return(nil, errors.New("database update failed")).
+			Times(1)
 
-		// Define a expectativa: UpdateCustomer será chamado e retornará erro de validação.
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPatch, "/api/customers/bulk", []byte(`{"ids": [1], "set": {"active": false}}`))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Erro ao atualizar clientes")
+	})
+}
+
+// TestCustomerHandler_DeleteCustomers testa o endpoint DELETE /api/customers/bulk.
+// Verifica a exclusão por IDs, a simulação com dry_run e a seleção inválida.
+func TestCustomerHandler_DeleteCustomers(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+
+	// Subteste para a exclusão de uma lista de IDs.
+	t.Run("Success", func(t *testing.T) {
 		mockService.EXPECT().
-			UpdateCustomer(gomock.Any(), gomock.Any()).
-			Return(service.ErrInvalidCustomer).
+			DeleteCustomers(gomock.Any(), model.BulkSelection{IDs: []uint{4, 5}}, false).
+			Return(&model.BulkResult{Matched: 2, Affected: 2}, nil).
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON)
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/bulk", []byte(`{"ids": [4, 5]}`))
 
-		// Verifica o status 400 Bad Request.
-		assert.Equal(t, http.StatusBadRequest, recorder.Code)
-		assert.Contains(t, recorder.Body.String(), service.ErrInvalidCustomer.Error())
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"dry_run": false, "matched": 2, "affected": 2}`, recorder.Body.String())
 	})
 
-	// Subteste para o cenário onde o serviço não encontra o cliente para atualizar.
-	t.Run("Service Not Found Error", func(t *testing.T) {
-		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
-		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
+	// Subteste para a simulação da exclusão por filtro.
+	t.Run("Dry Run", func(t *testing.T) {
+		expectedSelection := model.BulkSelection{Filter: model.CustomerFilter{PhonePrefix: "11"}}
 
-		// Define a expectativa: UpdateCustomer será chamado e retornará ErrCustomerNotFound.
 		mockService.EXPECT().
-			UpdateCustomer(gomock.Any(), gomock.Any()).
-			Return(service.ErrCustomerNotFound).
+			DeleteCustomers(gomock.Any(), expectedSelection, true).
+			Return(&model.BulkResult{Matched: 7, Affected: 7, DryRun: true}, nil).
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON)
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/bulk?dry_run=true", []byte(`{"filter": {"phone_prefix": "(11)"}}`))
 
-		// Verifica o status 404 Not Found.
-		assert.Equal(t, http.StatusNotFound, recorder.Code)
-		assert.Contains(t, recorder.Body.String(), service.ErrCustomerNotFound.Error())
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"dry_run":true`)
 	})
 
-	// Subteste para o cenário de erro interno do serviço.
-	t.Run("Service Internal Error", func(t *testing.T) {
-		customerUpdateInput := model.Customer{Name: "Updated Name", Email: "updated@example.com"}
-		customerUpdateInputJSON, _ := json.Marshal(customerUpdateInput)
-		serviceErr := errors.New("database update failed") // Erro genérico simulado.
+	// Subteste para a exclusão dos clientes selecionados pela expressão de filtro.
+	t.Run("Success By Expression", func(t *testing.T) {
+		expression, err := rsql.Parse("active==false;created_at<2024-01-01", model.FilterableCustomerFields)
+		assert.NoError(t, err)
 
-		// Define a expectativa: UpdateCustomer será chamado e retornará um erro interno.
 		mockService.EXPECT().
-			UpdateCustomer(gomock.Any(), gomock.Any()).
-			Return(serviceErr).
+			DeleteCustomers(gomock.Any(), model.BulkSelection{Filter: model.CustomerFilter{Expression: expression}}, false).
+			Return(&model.BulkResult{Matched: 3, Affected: 3}, nil).
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		performRequest(router, recorder, http.MethodPut, "/api/customers/"+testIDStr, customerUpdateInputJSON)
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/bulk",
+			[]byte(`{"filter": {"expression": "active==false;created_at<2024-01-01"}}`))
 
-		// Verifica o status 500 Internal Server Error.
-		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-		assert.Contains(t, recorder.Body.String(), "Erro ao atualizar cliente")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"dry_run": false, "matched": 3, "affected": 3}`, recorder.Body.String())
+	})
+
+	// Subteste para o cenário de campo fora da lista permitida na expressão de filtro, com os campos aceitos.
+	t.Run("Filter Expression Unknown Field", func(t *testing.T) {
+		mockService.EXPECT().DeleteCustomers(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/bulk", []byte(`{"filter": {"expression": "version>1"}}`))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var problem utils.Problem
+		err := json.Unmarshal(recorder.Body.Bytes(), &problem)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, problem.Position)
+		assert.Contains(t, problem.AllowedValues, "created_at")
+	})
+
+	// Subteste para o cenário em que o serviço rejeita a seleção.
+	t.Run("Invalid Selection", func(t *testing.T) {
+		mockService.EXPECT().
+			DeleteCustomers(gomock.Any(), gomock.Any(), false).
+			Return(nil, service.ErrInvalidBulkSelection).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/bulk", []byte(`{}`))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 	})
 }
 
-// TestCustomerHandler_DeleteCustomer testa o endpoint DELETE /api/customers/{id}.
-// Verifica os cenários de sucesso na exclusão, falha por ID inválido,
-// falha quando o cliente não é encontrado e falha por erro interno do serviço.
-func TestCustomerHandler_DeleteCustomer(t *testing.T) {
+// TestCustomerHandler_RestoreCustomer testa o endpoint POST /api/customers/{id}/restore.
+// Verifica os cenários de sucesso, cliente inexistente, cliente não excluído e email em uso.
+func TestCustomerHandler_RestoreCustomer(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
@@ -533,65 +2434,119 @@ func TestCustomerHandler_DeleteCustomer(t *testing.T) {
 
 	// Subteste para o cenário de sucesso.
 	t.Run("Success", func(t *testing.T) {
-		// Define a expectativa: DeleteCustomer será chamado com o ID correto e retornará nil (sucesso).
+		restored := &model.Customer{ID: testID, Name: "Restored", Email: "restored@example.com", Active: true, Version: 4}
+
+		// Define a expectativa: RestoreCustomer será chamado e retornará o cliente restaurado.
 		mockService.EXPECT().
-			DeleteCustomer(gomock.Any(), testID).
-			Return(nil). // Simula exclusão bem-sucedida.
+			RestoreCustomer(gomock.Any(), testID).
+			Return(restored, nil).
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		// Executa a requisição DELETE.
-		performRequest(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr, nil)
+		performRequest(router, recorder, http.MethodPost, "/api/customers/"+testIDStr+"/restore", nil)
 
-		// Verifica o status 204 No Content.
-		assert.Equal(t, http.StatusNoContent, recorder.Code)
-		// Verifica que o corpo da resposta está vazio.
-		assert.Empty(t, recorder.Body.String())
+		// Verifica o status 200, a ETag com a nova versão e o cliente sem deleted_at.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, `"4-json"`, recorder.Header().Get("ETag"))
+		assert.Contains(t, recorder.Body.String(), `"id":`+testIDStr)
+		assert.NotContains(t, recorder.Body.String(), "deleted_at")
 	})
 
-	// Subteste para o cenário de formato de ID inválido na URL.
-	t.Run("Invalid ID Format", func(t *testing.T) {
+	// Subteste para o cenário onde o cliente não existe.
+	t.Run("Not Found", func(t *testing.T) {
+		mockService.EXPECT().
+			RestoreCustomer(gomock.Any(), testID).
+			Return(nil, service.ErrCustomerNotFound).
+			Times(1)
+
 		recorder = httptest.NewRecorder()
-		// Executa a requisição com um ID não numérico.
-		performRequest(router, recorder, http.MethodDelete, "/api/customers/invalid-id", nil)
+		performRequest(router, recorder, http.MethodPost, "/api/customers/"+testIDStr+"/restore", nil)
 
-		// Verifica o status 400 Bad Request.
-		assert.Equal(t, http.StatusBadRequest, recorder.Code)
-		assert.Contains(t, recorder.Body.String(), "ID inválido")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
 	})
 
-	// Subteste para o cenário onde o cliente a ser excluído não é encontrado.
+	// Subteste para o cenário onde o cliente não está excluído.
+	t.Run("Not Deleted", func(t *testing.T) {
+		mockService.EXPECT().
+			RestoreCustomer(gomock.Any(), testID).
+			Return(nil, service.ErrCustomerNotDeleted).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers/"+testIDStr+"/restore", nil)
+
+		// Verifica o status 409 Conflict.
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrCustomerNotDeleted.Error())
+	})
+
+	// Subteste para o cenário onde o email do cliente já pertence a outro cliente ativo.
+	t.Run("Already Exists", func(t *testing.T) {
+		mockService.EXPECT().
+			RestoreCustomer(gomock.Any(), testID).
+			Return(nil, &service.AlreadyExistsError{Field: "email"}).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/customers/"+testIDStr+"/restore", nil)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"field":"email"`)
+	})
+}
+
+// TestCustomerHandler_PurgeCustomer testa o endpoint DELETE /api/customers/{id}/purge.
+// Verifica os cenários de sucesso, cliente inexistente e erro interno do serviço.
+func TestCustomerHandler_PurgeCustomer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+
+	testID := uint(789)
+	testIDStr := strconv.FormatUint(uint64(testID), 10)
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		mockService.EXPECT().
+			PurgeCustomer(gomock.Any(), testID).
+			Return(nil).
+			Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr+"/purge", nil)
+
+		// Verifica o status 204 No Content e o corpo vazio.
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+	})
+
+	// Subteste para o cenário onde o cliente não existe.
 	t.Run("Not Found", func(t *testing.T) {
-		// Define a expectativa: DeleteCustomer será chamado e retornará ErrCustomerNotFound.
 		mockService.EXPECT().
-			DeleteCustomer(gomock.Any(), testID).
-			Return(service.ErrCustomerNotFound). // Simula cliente não encontrado.
+			PurgeCustomer(gomock.Any(), testID).
+			Return(service.ErrCustomerNotFound).
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		performRequest(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr, nil)
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr+"/purge", nil)
 
-		// Verifica o status 404 Not Found.
 		assert.Equal(t, http.StatusNotFound, recorder.Code)
-		assert.Contains(t, recorder.Body.String(), service.ErrCustomerNotFound.Error())
 	})
 
 	// Subteste para o cenário de erro interno do serviço.
 	t.Run("Service Internal Error", func(t *testing.T) {
-		serviceErr := errors.New("database delete failed") // Erro genérico simulado.
-
-		// Define a expectativa: DeleteCustomer será chamado e retornará um erro interno.
 		mockService.EXPECT().
-			DeleteCustomer(gomock.Any(), testID).
-			Return(serviceErr). // Simula erro interno.
+			PurgeCustomer(gomock.Any(), testID).
+			Return(errors.New("database delete failed")).
 			Times(1)
 
 		recorder = httptest.NewRecorder()
-		performRequest(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr, nil)
+		performRequest(router, recorder, http.MethodDelete, "/api/customers/"+testIDStr+"/purge", nil)
 
-		// Verifica o status 500 Internal Server Error.
 		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-		assert.Contains(t, recorder.Body.String(), "Erro ao excluir cliente")
+		assert.Contains(t, recorder.Body.String(), "Erro ao remover cliente definitivamente")
 	})
 }
 
@@ -610,7 +2565,7 @@ func TestCustomerHandler_CountCustomers(t *testing.T) {
 
 		// Define a expectativa: CountCustomers será chamado e retornará a contagem.
 		mockService.EXPECT().
-			CountCustomers(gomock.Any()).
+			CountCustomers(gomock.Any(), model.CustomerFilter{}).
 			Return(expectedCount, nil). // Retorna a contagem e nenhum erro.
 			Times(1)
 
@@ -628,13 +2583,24 @@ func TestCustomerHandler_CountCustomers(t *testing.T) {
 		assert.Equal(t, expectedCount, responseBody["count"])
 	})
 
+	// Subteste para a contagem em XML, com o elemento raiz customer_count.
+	t.Run("Success XML", func(t *testing.T) {
+		mockService.EXPECT().CountCustomers(gomock.Any(), model.CustomerFilter{}).Return(int64(42), nil).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers/count", nil, map[string]string{"Accept": "application/xml"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "<customer_count><count>42</count></customer_count>")
+	})
+
 	// Subteste para o cenário de erro interno do serviço.
 	t.Run("Service Internal Error", func(t *testing.T) {
 		serviceErr := errors.New("failed to count") // Erro genérico simulado.
 
 		// Define a expectativa: CountCustomers será chamado e retornará um erro interno.
 		mockService.EXPECT().
-			CountCustomers(gomock.Any()).
+			CountCustomers(gomock.Any(), model.CustomerFilter{}).
 			Return(int64(0), serviceErr). // Retorna 0 para contagem e o erro.
 			Times(1)
 
@@ -647,3 +2613,173 @@ func TestCustomerHandler_CountCustomers(t *testing.T) {
 		assert.Contains(t, recorder.Body.String(), "Erro ao contar clientes")
 	})
 }
+
+// TestCustomerHandler_ContentNegotiation testa a negociação da representação pelos cabeçalhos Accept e Content-Type.
+// Verifica as respostas e os corpos em XML, YAML e MessagePack e as respostas 406 e 415.
+func TestCustomerHandler_ContentNegotiation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockService := mock_service.NewMockCustomerService(mockCtrl)
+	router, recorder := setupTestRouter(t, mockService)
+
+	createdAt := time.Date(2025, 4, 23, 15, 4, 5, 0, time.UTC)
+	stored := func() *model.Customer {
+		return &model.Customer{ID: 7, Name: "Ana Souza", Email: "ana@example.com", Active: true, CreatedAt: createdAt, UpdatedAt: createdAt, Version: 1}
+	}
+
+	// Subteste para a resposta em XML.
+	t.Run("XML Response", func(t *testing.T) {
+		mockService.EXPECT().GetCustomerByID(gomock.Any(), uint(7), nil).Return(stored(), nil).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers/7", nil, map[string]string{"Accept": "application/xml"})
+
+		// Verifica o tipo de mídia, o cabeçalho Vary e o elemento raiz.
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/xml; charset=utf-8", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept", recorder.Header().Get("Vary"))
+		assert.Contains(t, recorder.Body.String(), "<customer><id>7</id><name>Ana Souza</name>")
+
+		var customer handler.CustomerResponse
+		assert.NoError(t, xml.Unmarshal(recorder.Body.Bytes(), &customer))
+		assert.Equal(t, "ana@example.com", customer.Email)
+		assert.Equal(t, createdAt, customer.CreatedAt)
+	})
+
+	// Subteste para a lista de clientes em XML, envolvida em um elemento raiz.
+	t.Run("XML List", func(t *testing.T) {
+		matches := []*model.CustomerMatch{{Customer: stored(), MatchedField: model.SearchFieldName, Score: 0.75}}
+		mockService.EXPECT().SearchCustomers(gomock.Any(), gomock.Any(), gomock.Any(), model.Pagination{}).Return(matches, nil).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers/search?name=Ana", nil, map[string]string{"Accept": "text/xml"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "<customers><customer><id>7</id>")
+		assert.Contains(t, recorder.Body.String(), "<matched_field>name</matched_field><score>0.75</score></customer></customers>")
+	})
+
+	// Subteste para a resposta em YAML.
+	t.Run("YAML Response", func(t *testing.T) {
+		mockService.EXPECT().CountCustomers(gomock.Any(), model.CustomerFilter{}).Return(int64(42), nil).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers/count", nil, map[string]string{"Accept": "application/yaml"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/yaml; charset=utf-8", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "count: 42\n", recorder.Body.String())
+	})
+
+	// Subteste para a resposta em MessagePack, que usa os nomes dos campos em JSON.
+	t.Run("MessagePack Response", func(t *testing.T) {
+		mockService.EXPECT().GetCustomerByID(gomock.Any(), uint(7), nil).Return(stored(), nil).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers/7", nil, map[string]string{"Accept": "application/msgpack"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/msgpack", recorder.Header().Get("Content-Type"))
+
+		var fields map[string]any
+		assert.NoError(t, codec.NewDecoderBytes(recorder.Body.Bytes(), new(codec.MsgpackHandle)).Decode(&fields))
+		assert.Contains(t, fields, "created_at")
+		assert.NotContains(t, fields, "XMLName")
+
+		var customer handler.CustomerResponse
+		assert.NoError(t, codec.NewDecoderBytes(recorder.Body.Bytes(), new(codec.MsgpackHandle)).Decode(&customer))
+		assert.Equal(t, uint(7), customer.ID)
+		assert.Equal(t, "ana@example.com", customer.Email)
+		assert.Equal(t, createdAt, customer.CreatedAt)
+	})
+
+	// Subteste para a preferência pelo primeiro tipo aceito entre vários.
+	t.Run("Accept List", func(t *testing.T) {
+		mockService.EXPECT().GetCustomerByID(gomock.Any(), uint(7), nil).Return(stored(), nil).Times(1)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers/7", nil, map[string]string{"Accept": "text/html, application/x-yaml, */*"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/yaml; charset=utf-8", recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Body.String(), "email: ana@example.com")
+	})
+
+	// Subteste para o cabeçalho Accept sem nenhum tipo suportado.
+	t.Run("Not Acceptable", func(t *testing.T) {
+		mockService.EXPECT().GetCustomerByID(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/customers/7", nil, map[string]string{"Accept": "text/csv"})
+
+		// Verifica o status 406 e a lista de tipos aceitos, em problem+json.
+		assert.Equal(t, http.StatusNotAcceptable, recorder.Code)
+		assert.Equal(t, utils.ProblemContentType, recorder.Header().Get("Content-Type"))
+		var problem utils.Problem
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+		assert.Contains(t, problem.AllowedValues, "application/msgpack")
+	})
+
+	// Subteste para a criação com o corpo em XML.
+	t.Run("XML Request", func(t *testing.T) {
+		mockService.EXPECT().
+			CreateCustomer(gomock.Any(), &model.Customer{Name: "Ana Souza", Email: "ana@example.com", Phone: "11987654321", Active: false}).
+			DoAndReturn(func(_ context.Context, customer *model.Customer) error {
+				customer.ID = 7
+				return nil
+			}).
+			Times(1)
+
+		body := []byte(`<customer><name>Ana Souza</name><email>ana@example.com</email><phone>11987654321</phone><active>false</active></customer>`)
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPost, "/api/customers", body,
+			map[string]string{"Content-Type": "application/xml", "Accept": "application/xml"})
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "<id>7</id>")
+	})
+
+	// Subteste para a atualização com o corpo em YAML e a resposta no JSON padrão.
+	t.Run("YAML Request", func(t *testing.T) {
+		mockService.EXPECT().
+			UpdateCustomer(gomock.Any(), &model.Customer{ID: 7, Name: "Ana Souza", Email: "ana@example.com", Active: true}, uint(0)).
+			Return(nil).
+			Times(1)
+
+		body := []byte("name: Ana Souza\nemail: ana@example.com\nactive: true\n")
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPut, "/api/customers/7", body, map[string]string{"Content-Type": "application/yaml"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	})
+
+	// Subteste para a criação com o corpo em MessagePack.
+	t.Run("MessagePack Request", func(t *testing.T) {
+		mockService.EXPECT().
+			CreateCustomer(gomock.Any(), &model.Customer{Name: "Ana Souza", Email: "ana@example.com", Active: true}).
+			Return(nil).
+			Times(1)
+
+		var body []byte
+		err := codec.NewEncoderBytes(&body, new(codec.MsgpackHandle)).Encode(map[string]any{"name": "Ana Souza", "email": "ana@example.com"})
+		assert.NoError(t, err)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPost, "/api/customers", body, map[string]string{"Content-Type": "application/msgpack"})
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+	})
+
+	// Subteste para o corpo em um tipo de mídia não suportado.
+	t.Run("Unsupported Media Type", func(t *testing.T) {
+		mockService.EXPECT().CreateCustomer(gomock.Any(), gomock.Any()).Times(0)
+
+		recorder = httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodPost, "/api/customers", []byte("name=Ana"),
+			map[string]string{"Content-Type": "application/x-www-form-urlencoded"})
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+	})
+}
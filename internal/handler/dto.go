@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/rsql"
+	"github.com/wandermaia/customer-api/internal/utils"
+)
+
+// CreateCustomerRequest representa o corpo aceito na criação de um cliente.
+// Campos gerenciados pelo servidor, como id e datas, não fazem parte do contrato e são ignorados.
+// @Description Dados para a criação de um cliente
+type CreateCustomerRequest struct {
+	Name    string `json:"name" xml:"name" yaml:"name" example:"João da Silva"`
+	Email   string `json:"email" xml:"email" yaml:"email" example:"joao@example.com"`
+	Phone   string `json:"phone" xml:"phone" yaml:"phone" example:"(11) 98765-4321"`
+	Address string `json:"address" xml:"address" yaml:"address" example:"Av. Paulista, 1000, São Paulo - SP"`
+	// Active é verdadeiro quando não informado
+	Active *bool `json:"active" xml:"active" yaml:"active" example:"true"`
+}
+
+// toModel converte a requisição no cliente a ser criado. O padrão de active é aplicado aqui, e não no modelo:
+// com um padrão na tag do GORM, o valor false seria substituído por ele na inserção.
+func (r CreateCustomerRequest) toModel() *model.Customer {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+
+	return &model.Customer{
+		Name:    r.Name,
+		Email:   r.Email,
+		Phone:   r.Phone,
+		Address: r.Address,
+		Active:  active,
+	}
+}
+
+// UpdateCustomerRequest representa o corpo aceito na substituição dos dados de um cliente.
+// Campos gerenciados pelo servidor, como id e datas, não fazem parte do contrato e são ignorados.
+// @Description Dados para a atualização de um cliente
+type UpdateCustomerRequest struct {
+	Name    string `json:"name" xml:"name" yaml:"name" example:"João da Silva"`
+	Email   string `json:"email" xml:"email" yaml:"email" example:"joao@example.com"`
+	Phone   string `json:"phone" xml:"phone" yaml:"phone" example:"(11) 98765-4321"`
+	Address string `json:"address" xml:"address" yaml:"address" example:"Av. Paulista, 1000, São Paulo - SP"`
+	Active  bool   `json:"active" xml:"active" yaml:"active" example:"true"`
+}
+
+// toModel converte a requisição no cliente identificado por id
+func (r UpdateCustomerRequest) toModel(id uint) *model.Customer {
+	return &model.Customer{
+		ID:      id,
+		Name:    r.Name,
+		Email:   r.Email,
+		Phone:   r.Phone,
+		Address: r.Address,
+		Active:  r.Active,
+	}
+}
+
+// CustomerResponse representa um cliente nas respostas da API
+// @Description Cliente retornado pela API
+type CustomerResponse struct {
+	XMLName   xml.Name  `json:"-" xml:"customer" yaml:"-" swaggerignore:"true"`
+	ID        uint      `json:"id" xml:"id" yaml:"id" example:"1"`
+	Name      string    `json:"name" xml:"name" yaml:"name" example:"João da Silva"`
+	Email     string    `json:"email" xml:"email" yaml:"email" example:"joao@example.com"`
+	Phone     string    `json:"phone" xml:"phone" yaml:"phone" example:"(11) 98765-4321"`
+	Address   string    `json:"address" xml:"address" yaml:"address" example:"Av. Paulista, 1000, São Paulo - SP"`
+	Active    bool      `json:"active" xml:"active" yaml:"active" example:"true"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at" yaml:"created_at" example:"2025-04-23T15:04:05Z"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at" yaml:"updated_at" example:"2025-04-23T15:04:05Z"`
+	// DeletedAt é informado somente para clientes excluídos logicamente
+	DeletedAt *time.Time `json:"deleted_at,omitempty" xml:"deleted_at,omitempty" yaml:"deleted_at,omitempty" example:"2025-05-01T10:00:00Z"`
+}
+
+// newCustomerResponse converte o cliente armazenado em sua representação na API
+func newCustomerResponse(customer *model.Customer) CustomerResponse {
+	response := CustomerResponse{
+		ID:        customer.ID,
+		Name:      customer.Name,
+		Email:     customer.Email,
+		Phone:     customer.Phone,
+		Address:   customer.Address,
+		Active:    customer.Active,
+		CreatedAt: customer.CreatedAt,
+		UpdatedAt: customer.UpdatedAt,
+	}
+	if customer.IsDeleted() {
+		response.DeletedAt = &customer.DeletedAt.Time
+	}
+	return response
+}
+
+// newCustomerResponses converte uma lista de clientes, retornando uma lista vazia em vez de nula
+func newCustomerResponses(customers []*model.Customer) []CustomerResponse {
+	responses := make([]CustomerResponse, len(customers))
+	for i, customer := range customers {
+		responses[i] = newCustomerResponse(customer)
+	}
+	return responses
+}
+
+// CustomerSearchResult representa um cliente encontrado na busca, com o campo em que o termo foi encontrado
+// @Description Cliente encontrado na busca, com o campo de maior pontuação e a pontuação, de 0 a 1
+type CustomerSearchResult struct {
+	CustomerResponse `yaml:",inline"`
+	// MatchedField é um dos campos name, email, phone ou address, ou fulltext quando os termos da busca textual
+	// estão distribuídos entre o nome e o endereço
+	MatchedField string  `json:"matched_field" xml:"matched_field" yaml:"matched_field" example:"phone"`
+	Score        float64 `json:"score" xml:"score" yaml:"score" example:"1"`
+	// Headline é informado somente na busca textual, com os termos encontrados entre <b> e </b>
+	Headline string `json:"headline,omitempty" xml:"headline,omitempty" yaml:"headline,omitempty" example:"João da Silva - Rua das <b>Flores</b>, 100"`
+}
+
+// newCustomerSearchResults converte os clientes encontrados na busca, retornando uma lista vazia em vez de nula
+func newCustomerSearchResults(matches []*model.CustomerMatch) []CustomerSearchResult {
+	results := make([]CustomerSearchResult, len(matches))
+	for i, match := range matches {
+		results[i] = CustomerSearchResult{
+			CustomerResponse: newCustomerResponse(match.Customer),
+			MatchedField:     match.MatchedField,
+			Score:            match.Score,
+			Headline:         match.Headline,
+		}
+	}
+	return results
+}
+
+// CustomerPageResponse representa uma página de clientes nas respostas da API
+// @Description Envelope de resposta paginada da listagem de clientes.
+// @Description Na paginação por cursor, page e total não são retornados.
+type CustomerPageResponse struct {
+	XMLName    xml.Name           `json:"-" xml:"customer_page" yaml:"-" swaggerignore:"true"`
+	Items      []CustomerResponse `json:"items" xml:"items>customer" yaml:"items"`
+	Total      *int64             `json:"total,omitempty" xml:"total,omitempty" yaml:"total,omitempty" example:"42"`
+	Page       int                `json:"page,omitempty" xml:"page,omitempty" yaml:"page,omitempty" example:"1"`
+	PageSize   int                `json:"page_size" xml:"page_size" yaml:"page_size" example:"20"`
+	NextCursor string             `json:"next_cursor,omitempty" xml:"next_cursor,omitempty" yaml:"next_cursor,omitempty" example:"eyJjIjoiMjAyNS0wNC0yM1QxNTowNDowNVoiLCJpIjo0Mn0"`
+}
+
+// newCustomerPageResponse converte a página de clientes em sua representação na API
+func newCustomerPageResponse(page *model.CustomerPage) CustomerPageResponse {
+	return CustomerPageResponse{
+		Items:      newCustomerResponses(page.Items),
+		Total:      page.Total,
+		Page:       page.Page,
+		PageSize:   page.PageSize,
+		NextCursor: page.NextCursor,
+	}
+}
+
+// BatchCreateRequest representa o corpo aceito na criação de clientes em lote
+// @Description Clientes a serem criados em lote e o modo de criação
+type BatchCreateRequest struct {
+	// Mode define se o lote é criado por completo ou nada (all_or_nothing, padrão) ou se os clientes válidos são criados mesmo com falhas (best_effort)
+	Mode      string                  `json:"mode" xml:"mode" yaml:"mode" enums:"all_or_nothing,best_effort" example:"best_effort"`
+	Customers []CreateCustomerRequest `json:"customers" xml:"customers>customer" yaml:"customers"`
+}
+
+// toModels converte os clientes da requisição nos clientes a serem criados
+func (r BatchCreateRequest) toModels() []*model.Customer {
+	customers := make([]*model.Customer, len(r.Customers))
+	for i, customer := range r.Customers {
+		customers[i] = customer.toModel()
+	}
+	return customers
+}
+
+// BatchItemResponse representa o resultado da criação de um cliente do lote
+// @Description Resultado da criação de um cliente do lote: o cliente criado ou o erro
+type BatchItemResponse struct {
+	// Index é a posição do cliente na lista enviada
+	Index    int               `json:"index" xml:"index" yaml:"index" example:"0"`
+	Status   int               `json:"status" xml:"status" yaml:"status" example:"201"`
+	Customer *CustomerResponse `json:"customer,omitempty" xml:"customer,omitempty" yaml:"customer,omitempty"`
+	Error    *utils.Problem    `json:"error,omitempty" xml:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// BatchCreateResponse representa a resposta multi-status da criação de clientes em lote
+// @Description Resultado da criação em lote, com um item para cada cliente enviado
+type BatchCreateResponse struct {
+	XMLName xml.Name            `json:"-" xml:"batch_result" yaml:"-" swaggerignore:"true"`
+	Mode    string              `json:"mode" xml:"mode" yaml:"mode" example:"best_effort"`
+	Created int                 `json:"created" xml:"created" yaml:"created" example:"1"`
+	Failed  int                 `json:"failed" xml:"failed" yaml:"failed" example:"0"`
+	Results []BatchItemResponse `json:"results" xml:"results>result" yaml:"results"`
+}
+
+// BulkFilterRequest representa os critérios de filtro de uma operação em massa, combinados com AND
+// @Description Critérios de filtro de uma operação em massa, com o mesmo significado dos filtros da listagem
+type BulkFilterRequest struct {
+	Active        *bool  `json:"active" xml:"active" yaml:"active" example:"true"`
+	CreatedAfter  string `json:"created_after" xml:"created_after" yaml:"created_after" example:"2025-01-01"`
+	CreatedBefore string `json:"created_before" xml:"created_before" yaml:"created_before" example:"2025-12-31T23:59:59Z"`
+	UpdatedSince  string `json:"updated_since" xml:"updated_since" yaml:"updated_since" example:"2025-04-23"`
+	EmailDomain   string `json:"email_domain" xml:"email_domain" yaml:"email_domain" example:"partner.com"`
+	PhonePrefix   string `json:"phone_prefix" xml:"phone_prefix" yaml:"phone_prefix" example:"11"`
+	// Expression é uma expressão de filtro com a mesma sintaxe do parâmetro filter da listagem
+	Expression string `json:"expression" xml:"expression" yaml:"expression" example:"name=like=Silva*,email=like=*@corp.com"`
+}
+
+// toModel converte os critérios da requisição no filtro de clientes. Os erros da expressão de filtro são do tipo
+// *rsql.Error, com a posição do erro.
+func (r *BulkFilterRequest) toModel() (model.CustomerFilter, error) {
+	filter := model.CustomerFilter{Active: r.Active}
+
+	var err error
+	if filter.CreatedAfter, err = parseDate(r.CreatedAfter); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = parseDate(r.CreatedBefore); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedSince, err = parseDate(r.UpdatedSince); err != nil {
+		return filter, err
+	}
+	if filter.EmailDomain, err = parseEmailDomain(r.EmailDomain); err != nil {
+		return filter, err
+	}
+	if filter.PhonePrefix, err = parsePhonePrefix(r.PhonePrefix); err != nil {
+		return filter, err
+	}
+	if value := strings.TrimSpace(r.Expression); value != "" {
+		if filter.Expression, err = rsql.Parse(value, model.FilterableCustomerFields); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// BulkDeleteRequest representa a seleção dos clientes de uma exclusão em massa
+// @Description Clientes a serem excluídos, selecionados pela lista de IDs ou pelo filtro
+type BulkDeleteRequest struct {
+	IDs    []uint             `json:"ids" xml:"ids>id" yaml:"ids" example:"1,2,3"`
+	Filter *BulkFilterRequest `json:"filter" xml:"filter" yaml:"filter"`
+}
+
+// toModel converte a requisição na seleção de clientes
+func (r BulkDeleteRequest) toModel() (model.BulkSelection, error) {
+	return newBulkSelection(r.IDs, r.Filter)
+}
+
+// newBulkSelection converte a lista de IDs e o filtro de uma operação em massa na seleção de clientes
+func newBulkSelection(ids []uint, filter *BulkFilterRequest) (model.BulkSelection, error) {
+	selection := model.BulkSelection{IDs: ids}
+	if filter == nil {
+		return selection, nil
+	}
+
+	var err error
+	selection.Filter, err = filter.toModel()
+	return selection, err
+}
+
+// BulkChangesRequest representa os campos alterados em uma atualização em massa
+// @Description Campos alterados nos clientes selecionados; campos omitidos não são alterados
+type BulkChangesRequest struct {
+	Active *bool `json:"active" xml:"active" yaml:"active" example:"false"`
+}
+
+// BulkUpdateRequest representa a seleção e as alterações de uma atualização em massa
+// @Description Clientes a serem alterados, selecionados pela lista de IDs ou pelo filtro, e os campos alterados
+type BulkUpdateRequest struct {
+	IDs    []uint             `json:"ids" xml:"ids>id" yaml:"ids" example:"1,2,3"`
+	Filter *BulkFilterRequest `json:"filter" xml:"filter" yaml:"filter"`
+	Set    BulkChangesRequest `json:"set" xml:"set" yaml:"set"`
+}
+
+// toModel converte a requisição na seleção de clientes e nas alterações
+func (r BulkUpdateRequest) toModel() (model.BulkSelection, model.CustomerChanges, error) {
+	selection, err := newBulkSelection(r.IDs, r.Filter)
+	return selection, model.CustomerChanges{Active: r.Set.Active}, err
+}
+
+// BulkResultResponse representa as contagens de uma operação em massa
+// @Description Contagens de uma operação em massa. Em uma simulação (dry_run), nada é alterado.
+type BulkResultResponse struct {
+	XMLName xml.Name `json:"-" xml:"bulk_result" yaml:"-" swaggerignore:"true"`
+	DryRun  bool     `json:"dry_run" xml:"dry_run" yaml:"dry_run" example:"false"`
+	// Matched é a quantidade de clientes selecionados
+	Matched int64 `json:"matched" xml:"matched" yaml:"matched" example:"12"`
+	// Affected é a quantidade de clientes alterados, ou que seriam alterados em uma simulação
+	Affected int64 `json:"affected" xml:"affected" yaml:"affected" example:"10"`
+}
+
+// newBulkResultResponse converte as contagens da operação em massa
+func newBulkResultResponse(result *model.BulkResult) BulkResultResponse {
+	return BulkResultResponse{DryRun: result.DryRun, Matched: result.Matched, Affected: result.Affected}
+}
+
+// SegmentRequest representa o corpo aceito na criação e na atualização de um segmento
+// @Description Nome do segmento e a expressão de filtro que seleciona seus clientes
+type SegmentRequest struct {
+	Name string `json:"name" xml:"name" yaml:"name" example:"Clientes ativos da Corp"`
+	// Filter é uma expressão de filtro RSQL, com a mesma sintaxe do parâmetro filter da listagem de clientes
+	Filter string `json:"filter" xml:"filter" yaml:"filter" example:"active==true;email=ilike=*@corp.com"`
+}
+
+// toModel converte a requisição no segmento identificado por id, zero na criação
+func (r SegmentRequest) toModel(id uint) *model.Segment {
+	return &model.Segment{
+		ID:     id,
+		Name:   r.Name,
+		Filter: r.Filter,
+	}
+}
+
+// SegmentResponse representa um segmento nas respostas da API
+// @Description Segmento de clientes retornado pela API
+type SegmentResponse struct {
+	XMLName   xml.Name  `json:"-" xml:"segment" yaml:"-" swaggerignore:"true"`
+	ID        uint      `json:"id" xml:"id" yaml:"id" example:"1"`
+	Name      string    `json:"name" xml:"name" yaml:"name" example:"Clientes ativos da Corp"`
+	Filter    string    `json:"filter" xml:"filter" yaml:"filter" example:"active==true;email=ilike=*@corp.com"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at" yaml:"created_at" example:"2025-04-23T15:04:05Z"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at" yaml:"updated_at" example:"2025-04-23T15:04:05Z"`
+}
+
+// newSegmentResponse converte o segmento armazenado em sua representação na API
+func newSegmentResponse(segment *model.Segment) SegmentResponse {
+	return SegmentResponse{
+		ID:        segment.ID,
+		Name:      segment.Name,
+		Filter:    segment.Filter,
+		CreatedAt: segment.CreatedAt,
+		UpdatedAt: segment.UpdatedAt,
+	}
+}
+
+// newSegmentResponses converte uma lista de segmentos, retornando uma lista vazia em vez de nula
+func newSegmentResponses(segments []*model.Segment) []SegmentResponse {
+	responses := make([]SegmentResponse, len(segments))
+	for i, segment := range segments {
+		responses[i] = newSegmentResponse(segment)
+	}
+	return responses
+}
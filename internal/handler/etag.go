@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errInvalidIfMatch     = errors.New("cabeçalho If-Match inválido")
+	errPreconditionFailed = errors.New("If-Match não corresponde à versão atual do cliente")
+)
+
+// customerETag retorna a ETag forte da representação do cliente: a versão do cliente, o formato negociado e,
+// quando fields é informado, os campos selecionados na ordem de SelectableCustomerFields (ex: "4-json",
+// "4-xml-id.name"). Representações diferentes têm ETags diferentes, como exige a RFC 9110 para validadores fortes;
+// If-Match compara somente a versão.
+func customerETag(c *gin.Context, customer *model.Customer, fields model.FieldSet) string {
+	tag := strconv.FormatUint(uint64(customer.Version), 10) + "-" + negotiatedRepresentation(c).name
+	if len(fields) > 0 {
+		var selected []string
+		for _, field := range model.SelectableCustomerFields {
+			if fields.Includes(field) {
+				selected = append(selected, field)
+			}
+		}
+		tag += "-" + strings.Join(selected, ".")
+	}
+	return strconv.Quote(tag)
+}
+
+// contentETag retorna uma ETag fraca calculada a partir do conteúdo da resposta
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// setETag adiciona o cabeçalho ETag com a versão e a representação completa do cliente
+func setETag(c *gin.Context, customer *model.Customer) {
+	c.Header("ETag", customerETag(c, customer, nil))
+}
+
+// notModified adiciona os cabeçalhos ETag e Last-Modified e avalia as condições If-None-Match
+// e If-Modified-Since da requisição. Quando a representação não mudou, responde 304 e retorna true.
+// Assim como na RFC 9110, If-Modified-Since é ignorado quando If-None-Match é informado.
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if value := c.GetHeader("If-None-Match"); value != "" {
+		if !etagListMatches(value, etag) {
+			return false
+		}
+		c.Status(http.StatusNotModified)
+		return true
+	}
+
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || lastModified.IsZero() || lastModified.Truncate(time.Second).After(since) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagListMatches indica se a lista de ETags de If-None-Match contém a ETag informada,
+// usando a comparação fraca, que desconsidera o prefixo W/
+func etagListMatches(list, etag string) bool {
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion lê a versão esperada do cabeçalho If-Match, que é o início da ETag de qualquer representação
+// do cliente. Retorna zero quando o cabeçalho está ausente ou é "*", e errPreconditionFailed quando a ETag não
+// pode corresponder a nenhuma versão.
+func ifMatchVersion(c *gin.Context) (uint, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	if strings.Contains(value, ",") {
+		return 0, errInvalidIfMatch
+	}
+
+	// ETags fracas nunca satisfazem If-Match, que exige comparação forte (RFC 9110)
+	if strings.HasPrefix(value, "W/") {
+		return 0, errPreconditionFailed
+	}
+
+	tag, err := strconv.Unquote(value)
+	if err != nil || !strings.HasPrefix(value, `"`) {
+		return 0, errInvalidIfMatch
+	}
+
+	version, err := strconv.ParseUint(strings.SplitN(tag, "-", 2)[0], 10, 32)
+	if err != nil || version == 0 {
+		return 0, errPreconditionFailed
+	}
+	return uint(version), nil
+}
+
+// respondIfMatchError responde à requisição conforme o erro de leitura do cabeçalho If-Match
+func respondIfMatchError(c *gin.Context, err error) {
+	if err == errPreconditionFailed {
+		respondProblem(c, http.StatusPreconditionFailed, err.Error())
+		return
+	}
+	respondProblem(c, http.StatusBadRequest, err.Error())
+}
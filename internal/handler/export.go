@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+// Formatos aceitos na exportação de clientes
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+)
+
+// ndjsonContentType é o tipo de mídia da exportação em NDJSON, um objeto JSON por linha
+const ndjsonContentType = "application/x-ndjson"
+
+// exportFlushInterval é a quantidade de clientes escritos entre cada envio parcial da resposta
+const exportFlushInterval = 100
+
+// exportFormats são os formatos aceitos no parâmetro format da exportação
+var exportFormats = []string{exportFormatCSV, exportFormatNDJSON}
+
+// exportColumns são as colunas da exportação em CSV, na ordem em que são escritas
+var exportColumns = []string{"id", "name", "email", "phone", "address", "active", "created_at", "updated_at", "deleted_at"}
+
+var errInvalidExportFormat = errors.New("formato de exportação inválido")
+
+// customerWriter escreve os clientes exportados no formato escolhido, um de cada vez
+type customerWriter interface {
+	// ContentType retorna o tipo de mídia da resposta
+	ContentType() string
+	// Extension retorna a extensão do arquivo sugerido para download
+	Extension() string
+	// Begin escreve o conteúdo que precede o primeiro cliente
+	Begin() error
+	Write(customer CustomerResponse) error
+	// Flush envia o conteúdo mantido em buffer
+	Flush() error
+}
+
+// newCustomerWriter cria o writer da exportação para o formato informado, usando CSV quando vazio.
+// Quando fields é informado, somente os campos selecionados são escritos.
+func newCustomerWriter(format string, w io.Writer, fields model.FieldSet) (customerWriter, error) {
+	switch format {
+	case "", exportFormatCSV:
+		return &csvCustomerWriter{writer: csv.NewWriter(w), fields: fields}, nil
+	case exportFormatNDJSON:
+		return &ndjsonCustomerWriter{encoder: json.NewEncoder(w), fields: fields}, nil
+	default:
+		return nil, errInvalidExportFormat
+	}
+}
+
+// csvCustomerWriter escreve os clientes em CSV, com uma linha de cabeçalho
+type csvCustomerWriter struct {
+	writer *csv.Writer
+	fields model.FieldSet
+}
+
+func (w *csvCustomerWriter) ContentType() string {
+	return csvContentType + "; charset=utf-8"
+}
+
+func (w *csvCustomerWriter) Extension() string {
+	return exportFormatCSV
+}
+
+func (w *csvCustomerWriter) Begin() error {
+	return w.writer.Write(w.selected(exportColumns))
+}
+
+func (w *csvCustomerWriter) Write(customer CustomerResponse) error {
+	deletedAt := ""
+	if customer.DeletedAt != nil {
+		deletedAt = customer.DeletedAt.Format(time.RFC3339Nano)
+	}
+
+	return w.writer.Write(w.selected([]string{
+		strconv.FormatUint(uint64(customer.ID), 10),
+		customer.Name,
+		customer.Email,
+		customer.Phone,
+		customer.Address,
+		strconv.FormatBool(customer.Active),
+		customer.CreatedAt.Format(time.RFC3339Nano),
+		customer.UpdatedAt.Format(time.RFC3339Nano),
+		deletedAt,
+	}))
+}
+
+// selected retorna somente os valores das colunas de exportColumns selecionadas em fields
+func (w *csvCustomerWriter) selected(record []string) []string {
+	if len(w.fields) == 0 {
+		return record
+	}
+
+	var values []string
+	for i, column := range exportColumns {
+		if w.fields.Includes(column) {
+			values = append(values, record[i])
+		}
+	}
+	return values
+}
+
+func (w *csvCustomerWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// ndjsonCustomerWriter escreve cada cliente como um objeto JSON em sua própria linha
+type ndjsonCustomerWriter struct {
+	encoder *json.Encoder
+	fields  model.FieldSet
+}
+
+func (w *ndjsonCustomerWriter) ContentType() string {
+	return ndjsonContentType
+}
+
+func (w *ndjsonCustomerWriter) Extension() string {
+	return exportFormatNDJSON
+}
+
+func (w *ndjsonCustomerWriter) Begin() error {
+	return nil
+}
+
+func (w *ndjsonCustomerWriter) Write(customer CustomerResponse) error {
+	return w.encoder.Encode(withFields(customer, w.fields))
+}
+
+func (w *ndjsonCustomerWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+)
+
+// customerResponseType é o tipo cujos campos são limitados pelo parâmetro fields
+var customerResponseType = reflect.TypeOf(CustomerResponse{})
+
+// withFields retorna o corpo da resposta com os clientes limitados aos campos selecionados, inclusive dentro de
+// listas e envelopes. Cada cliente é convertido em uma struct com somente os campos selecionados de CustomerResponse,
+// com as mesmas tags, para que todas as representações o codifiquem da mesma forma. Sem campos, o corpo é retornado
+// sem alterações.
+func withFields(body any, fields model.FieldSet) any {
+	if len(fields) == 0 {
+		return body
+	}
+	value := reflect.ValueOf(body)
+	return sparseValue(value, sparseType(value.Type(), fields)).Interface()
+}
+
+// sparseType retorna o tipo com os clientes limitados aos campos selecionados, ou o próprio tipo quando ele não
+// contém clientes
+func sparseType(t reflect.Type, fields model.FieldSet) reflect.Type {
+	switch t.Kind() {
+	case reflect.Slice:
+		if elem := sparseType(t.Elem(), fields); elem != t.Elem() {
+			return reflect.SliceOf(elem)
+		}
+	case reflect.Struct:
+		if structFields, changed := sparseFields(t, fields); changed {
+			return reflect.StructOf(structFields)
+		}
+	}
+	return t
+}
+
+// sparseFields retorna os campos da struct com os clientes limitados aos campos selecionados e indica se algum foi
+// alterado. Os campos de um cliente embutido, como em CustomerSearchResult, são incorporados à struct, como já fazem
+// os codificadores; o XMLName do cliente é sempre mantido.
+func sparseFields(t reflect.Type, fields model.FieldSet) ([]reflect.StructField, bool) {
+	var result []reflect.StructField
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		switch {
+		case t == customerResponseType:
+			if field.Name != "XMLName" && !fields.Includes(jsonName(field)) {
+				changed = true
+				continue
+			}
+		case field.Anonymous && field.Type == customerResponseType:
+			embedded, _ := sparseFields(field.Type, fields)
+			result = append(result, embedded...)
+			changed = true
+			continue
+		default:
+			if fieldType := sparseType(field.Type, fields); fieldType != field.Type {
+				field.Type = fieldType
+				changed = true
+			}
+		}
+		result = append(result, field)
+	}
+	return result, changed
+}
+
+// sparseValue copia o valor para o tipo criado por sparseType, campo a campo pelo nome
+func sparseValue(v reflect.Value, t reflect.Type) reflect.Value {
+	if v.Type() == t {
+		return v
+	}
+
+	if t.Kind() == reflect.Slice {
+		out := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(sparseValue(v.Index(i), t.Elem()))
+		}
+		return out
+	}
+
+	out := reflect.New(t).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		// FieldByName também encontra os campos do cliente embutido, incorporados por sparseFields
+		out.Field(i).Set(sparseValue(v.FieldByName(t.Field(i).Name), t.Field(i).Type))
+	}
+	return out
+}
+
+// jsonName retorna o nome do campo em JSON, que identifica os campos selecionados
+func jsonName(field reflect.StructField) string {
+	return strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+	"github.com/wandermaia/customer-api/internal/rsql"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errInvalidFilter   = errors.New("parâmetros de filtro inválidos")
+	errInvalidDryRun   = errors.New("parâmetro dry_run inválido")
+	errInvalidMinScore = errors.New("parâmetro min_score inválido")
+	errEmptySearch     = errors.New("informe ao menos um dos parâmetros q, name, email, phone ou fulltext")
+	errInvalidPhone    = fmt.Errorf("o parâmetro phone deve conter ao menos %d dígitos", model.MinPhoneSearchDigits)
+)
+
+// dateLayouts são os formatos aceitos nos filtros de data
+var dateLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// parseCustomerFilter lê os parâmetros de filtro da listagem de clientes da query string
+func parseCustomerFilter(c *gin.Context) (model.CustomerFilter, error) {
+	var filter model.CustomerFilter
+
+	if value := c.Query("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errInvalidFilter
+		}
+		filter.Active = &active
+	}
+
+	var err error
+	if filter.CreatedAfter, err = parseDateQuery(c, "created_after"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = parseDateQuery(c, "created_before"); err != nil {
+		return filter, err
+	}
+	if filter.UpdatedSince, err = parseDateQuery(c, "updated_since"); err != nil {
+		return filter, err
+	}
+
+	if filter.EmailDomain, err = parseEmailDomain(c.Query("email_domain")); err != nil {
+		return filter, err
+	}
+	if filter.PhonePrefix, err = parsePhonePrefix(c.Query("phone_prefix")); err != nil {
+		return filter, err
+	}
+
+	if value := strings.TrimSpace(c.Query("filter")); value != "" {
+		if filter.Expression, err = rsql.Parse(value, model.FilterableCustomerFields); err != nil {
+			return filter, err
+		}
+	}
+
+	if value := c.Query("include_deleted"); value != "" {
+		includeDeleted, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errInvalidFilter
+		}
+		filter.IncludeDeleted = includeDeleted
+	}
+
+	return filter, nil
+}
+
+// parseDateQuery lê um parâmetro de data no formato RFC 3339 ou AAAA-MM-DD
+func parseDateQuery(c *gin.Context, key string) (*time.Time, error) {
+	return parseDate(c.Query(key))
+}
+
+// parseDate converte uma data no formato RFC 3339 ou AAAA-MM-DD, retornando nil quando vazia
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, errInvalidFilter
+}
+
+// parseEmailDomain normaliza o domínio de email do filtro, aceitando o prefixo @
+func parseEmailDomain(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	domain := strings.TrimPrefix(strings.TrimSpace(value), "@")
+	if domain == "" || strings.Contains(domain, "@") {
+		return "", errInvalidFilter
+	}
+	return domain, nil
+}
+
+// parsePhonePrefix mantém somente os dígitos do prefixo de telefone do filtro
+func parsePhonePrefix(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	prefix := model.PhoneDigits(value)
+	if prefix == "" {
+		return "", errInvalidFilter
+	}
+	return prefix, nil
+}
+
+// parseDryRun lê o parâmetro dry_run das operações em massa
+func parseDryRun(c *gin.Context) (bool, error) {
+	value := c.Query("dry_run")
+	if value == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errInvalidDryRun
+	}
+	return dryRun, nil
+}
+
+// parseCustomerSearch lê os termos da busca de clientes da query string, mantendo somente os dígitos do telefone,
+// que deve ter ao menos model.MinPhoneSearchDigits dígitos
+func parseCustomerSearch(c *gin.Context) (model.CustomerSearch, error) {
+	search := model.CustomerSearch{
+		Query:    strings.TrimSpace(c.Query("q")),
+		Name:     strings.TrimSpace(c.Query("name")),
+		Email:    strings.TrimSpace(c.Query("email")),
+		FullText: strings.TrimSpace(c.Query("fulltext")),
+	}
+
+	if value := c.Query("phone"); value != "" {
+		if search.Phone = model.PhoneDigits(value); len(search.Phone) < model.MinPhoneSearchDigits {
+			return search, errInvalidPhone
+		}
+	}
+	if search.IsEmpty() {
+		return search, errEmptySearch
+	}
+
+	var err error
+	search.MinScore, err = parseMinScore(c)
+	return search, err
+}
+
+// parseMinScore lê a pontuação mínima da busca, usando service.DefaultMinScore quando não informada
+func parseMinScore(c *gin.Context) (float64, error) {
+	value := c.Query("min_score")
+	if value == "" {
+		return service.DefaultMinScore, nil
+	}
+	minScore, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errInvalidMinScore
+	}
+	return minScore, nil
+}
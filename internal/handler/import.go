@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+)
+
+// csvContentType é o tipo de mídia aceito na importação e usado no relatório de rejeições
+const csvContentType = "text/csv"
+
+// importColumns são as colunas aceitas no cabeçalho do arquivo de importação
+var importColumns = []string{"name", "email", "phone", "address", "active"}
+
+// errMissingHeader indica um arquivo de importação vazio, sem a linha de cabeçalho
+var errMissingHeader = errors.New("o arquivo CSV deve conter uma linha de cabeçalho")
+
+// headerError indica uma coluna inválida no cabeçalho do arquivo de importação
+type headerError struct {
+	Column string
+	Reason string
+}
+
+func (e *headerError) Error() string {
+	return fmt.Sprintf("coluna %q %s", e.Column, e.Reason)
+}
+
+// rowFieldError indica um campo de uma linha do arquivo que não pôde ser convertido
+type rowFieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *rowFieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// csvImportSource lê os clientes de um arquivo CSV, associando as colunas pelo cabeçalho
+type csvImportSource struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// newCSVImportSource lê e valida o cabeçalho do arquivo. As colunas name e email são obrigatórias;
+// os nomes não diferenciam maiúsculas e minúsculas e a ordem é livre.
+func newCSVImportSource(r io.Reader) (*csvImportSource, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errMissingHeader
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		// Planilhas costumam gravar o BOM do UTF-8 no início do arquivo
+		if i == 0 {
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+		column = strings.ToLower(strings.TrimSpace(column))
+
+		if !isImportColumn(column) {
+			return nil, &headerError{Column: column, Reason: "não é aceita"}
+		}
+		if _, ok := columns[column]; ok {
+			return nil, &headerError{Column: column, Reason: "está repetida"}
+		}
+		columns[column] = i
+	}
+
+	for _, column := range []string{"name", "email"} {
+		if _, ok := columns[column]; !ok {
+			return nil, &headerError{Column: column, Reason: "é obrigatória"}
+		}
+	}
+
+	// Cada linha deve ter a mesma quantidade de campos do cabeçalho
+	reader.FieldsPerRecord = len(header)
+	return &csvImportSource{reader: reader, columns: columns}, nil
+}
+
+// isImportColumn indica se a coluna é aceita no cabeçalho do arquivo de importação
+func isImportColumn(column string) bool {
+	for _, allowed := range importColumns {
+		if column == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Next lê a próxima linha do arquivo. Linhas malformadas são retornadas com o erro para que sejam rejeitadas,
+// sem interromper a importação.
+func (s *csvImportSource) Next() (service.ImportRow, error) {
+	record, err := s.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return service.ImportRow{Line: parseErr.StartLine, Err: malformedRow(parseErr.Err)}, nil
+		}
+		return service.ImportRow{}, err
+	}
+
+	line, _ := s.reader.FieldPos(0)
+	row := service.ImportRow{Line: line}
+
+	active := true
+	if value := s.value(record, "active"); value != "" {
+		if active, err = strconv.ParseBool(value); err != nil {
+			row.Err = &rowFieldError{Field: "active", Message: "deve ser true ou false"}
+			return row, nil
+		}
+	}
+
+	row.Customer = &model.Customer{
+		Name:    s.value(record, "name"),
+		Email:   s.value(record, "email"),
+		Phone:   s.value(record, "phone"),
+		Address: s.value(record, "address"),
+		Active:  active,
+	}
+	return row, nil
+}
+
+// malformedRow descreve o erro de formatação de uma linha do arquivo
+func malformedRow(err error) error {
+	switch {
+	case errors.Is(err, csv.ErrFieldCount):
+		return errors.New("linha malformada: quantidade de campos diferente do cabeçalho")
+	case errors.Is(err, csv.ErrQuote), errors.Is(err, csv.ErrBareQuote):
+		return errors.New("linha malformada: uso inválido de aspas")
+	default:
+		return fmt.Errorf("linha malformada: %w", err)
+	}
+}
+
+// value retorna o valor da coluna na linha, ou vazio quando a coluna não faz parte do arquivo
+func (s *csvImportSource) value(record []string, column string) string {
+	i, ok := s.columns[column]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// writeImportReport escreve o relatório das linhas rejeitadas em CSV, com o número da linha e o motivo
+func writeImportReport(w io.Writer, rejected []service.ImportRejection) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"line", "reason"}); err != nil {
+		return err
+	}
+	for _, rejection := range rejected {
+		if err := writer.Write([]string{strconv.Itoa(rejection.Line), rejectionReason(rejection.Err)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// rejectionReason descreve o motivo da rejeição de uma linha, detalhando os campos inválidos
+func rejectionReason(err error) string {
+	var validation *service.ValidationError
+	var conflict *service.AlreadyExistsError
+	switch {
+	case errors.As(err, &validation):
+		problem := invalidCustomerProblem(err)
+		details := make([]string, len(problem.Errors))
+		for i, fieldErr := range problem.Errors {
+			details[i] = fieldErr.Field + ": " + fieldErr.Message
+		}
+		return problem.Detail + " (" + strings.Join(details, "; ") + ")"
+	case errors.As(err, &conflict):
+		return conflict.Error() + " (" + conflict.Field + ": já utilizado por outro cliente)"
+	case errors.Is(err, service.ErrDatabaseOperation), errors.Is(err, service.ErrDatabaseUnavailable):
+		return "erro ao criar cliente"
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/wandermaia/customer-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+// Tipos de mídia das representações aceitas nos corpos das requisições e das respostas
+const (
+	jsonContentType    = "application/json"
+	xmlContentType     = "application/xml"
+	yamlContentType    = "application/yaml"
+	msgpackContentType = "application/msgpack"
+)
+
+// representationKey é a chave do contexto com a representação negociada para a resposta
+const representationKey = "representation"
+
+var errUnsupportedMediaType = errors.New("Content-Type não suportado")
+
+// representation descreve um formato em que os clientes podem ser enviados e recebidos
+type representation struct {
+	// name identifica o formato nas ETags dos clientes
+	name string
+	// contentType é o tipo de mídia usado nas respostas
+	contentType string
+	// aliases são os tipos de mídia aceitos como equivalentes, incluindo contentType
+	aliases []string
+	marshal func(v any) ([]byte, error)
+	binding binding.Binding
+}
+
+// representations são os formatos aceitos, em ordem de preferência quando o cliente aceita qualquer um
+var representations = []representation{
+	{
+		name:        "json",
+		contentType: jsonContentType + "; charset=utf-8",
+		aliases:     []string{jsonContentType},
+		marshal:     json.Marshal,
+		binding:     binding.JSON,
+	},
+	{
+		name:        "xml",
+		contentType: xmlContentType + "; charset=utf-8",
+		aliases:     []string{xmlContentType, "text/xml"},
+		marshal:     marshalXML,
+		binding:     binding.XML,
+	},
+	{
+		name:        "yaml",
+		contentType: yamlContentType + "; charset=utf-8",
+		aliases:     []string{yamlContentType, "application/x-yaml", "text/yaml"},
+		marshal:     yaml.Marshal,
+		binding:     binding.YAML,
+	},
+	{
+		name:        "msgpack",
+		contentType: msgpackContentType,
+		aliases:     []string{msgpackContentType, "application/x-msgpack"},
+		marshal:     marshalMsgPack,
+		binding:     binding.MsgPack,
+	},
+}
+
+// supportedMediaTypes lista todos os tipos de mídia aceitos, na ordem de preferência
+func supportedMediaTypes() []string {
+	var mediaTypes []string
+	for _, r := range representations {
+		mediaTypes = append(mediaTypes, r.aliases...)
+	}
+	return mediaTypes
+}
+
+// representationFor retorna a representação que aceita o tipo de mídia informado
+func representationFor(mediaType string) (representation, bool) {
+	mediaType = strings.ToLower(mediaType)
+	for _, r := range representations {
+		for _, alias := range r.aliases {
+			if alias == mediaType {
+				return r, true
+			}
+		}
+	}
+	return representation{}, false
+}
+
+// negotiateContent escolhe a representação da resposta a partir do cabeçalho Accept, usando JSON
+// quando ele está ausente ou aceita qualquer tipo, e responde 406 quando nenhum formato é aceito.
+// Os erros são sempre respondidos como application/problem+json.
+func negotiateContent(c *gin.Context) {
+	c.Header("Vary", "Accept")
+
+	r, ok := representationFor(c.NegotiateFormat(supportedMediaTypes()...))
+	if !ok {
+		problem := utils.NewProblem(http.StatusNotAcceptable, "Nenhum dos tipos de mídia do cabeçalho Accept é suportado")
+		problem.AllowedValues = supportedMediaTypes()
+		utils.RespondProblem(c, problem)
+		return
+	}
+
+	c.Set(representationKey, r)
+	c.Next()
+}
+
+// negotiatedRepresentation retorna a representação escolhida por negotiateContent, ou JSON
+func negotiatedRepresentation(c *gin.Context) representation {
+	if r, ok := c.Get(representationKey); ok {
+		return r.(representation)
+	}
+	return representations[0]
+}
+
+// encodeResponse codifica o corpo da resposta na representação negociada
+func encodeResponse(c *gin.Context, body any) ([]byte, error) {
+	return negotiatedRepresentation(c).marshal(body)
+}
+
+// respond responde com o corpo codificado na representação negociada
+func respond(c *gin.Context, status int, body any) {
+	data, err := encodeResponse(c, body)
+	if err != nil {
+		respondServerError(c, err, "Erro ao codificar a resposta")
+		return
+	}
+	c.Data(status, negotiatedRepresentation(c).contentType, data)
+}
+
+// bindBody decodifica o corpo da requisição de acordo com o Content-Type, usando JSON quando ele não é
+// informado. Retorna errUnsupportedMediaType para os tipos de mídia não suportados.
+func bindBody(c *gin.Context, request any) error {
+	r := representations[0]
+	if contentType := c.ContentType(); contentType != "" {
+		var ok bool
+		if r, ok = representationFor(contentType); !ok {
+			return errUnsupportedMediaType
+		}
+	}
+	return c.ShouldBindWith(request, r.binding)
+}
+
+// respondBindError responde 415 para corpos em formatos não suportados e 400 para corpos inválidos
+func respondBindError(c *gin.Context, err error) {
+	if errors.Is(err, errUnsupportedMediaType) {
+		problem := utils.NewProblem(http.StatusUnsupportedMediaType, err.Error())
+		problem.AllowedValues = supportedMediaTypes()
+		utils.RespondProblem(c, problem)
+		return
+	}
+	respondInvalidBody(c, err)
+}
+
+// xmlList envolve as listas em um elemento raiz, já que o XML não admite mais de uma raiz
+type xmlList struct {
+	XMLName xml.Name
+	Items   any
+}
+
+// marshalXML codifica o corpo em XML, envolvendo as listas de segmentos no elemento segments e as demais,
+// de clientes, inclusive as limitadas por withFields, no elemento customers
+func marshalXML(v any) ([]byte, error) {
+	if _, ok := v.([]SegmentResponse); ok {
+		v = xmlList{XMLName: xml.Name{Local: "segments"}, Items: v}
+	} else if reflect.ValueOf(v).Kind() == reflect.Slice {
+		v = xmlList{XMLName: xml.Name{Local: "customers"}, Items: v}
+	}
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// msgpackHandle codifica na especificação atual do MessagePack, com o tipo str e a extensão de datas
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := new(codec.MsgpackHandle)
+	h.WriteExt = true
+	return h
+}()
+
+// marshalMsgPack codifica o corpo em MessagePack, usando os nomes dos campos em JSON
+func marshalMsgPack(v any) ([]byte, error) {
+	var data []byte
+	err := codec.NewEncoderBytes(&data, msgpackHandle).Encode(v)
+	return data, err
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidPagination = errors.New("parâmetros de paginação inválidos")
+
+// parsePagination lê os parâmetros page e page_size da query string
+func parsePagination(c *gin.Context) (model.Pagination, error) {
+	var pagination model.Pagination
+
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return pagination, errInvalidPagination
+		}
+		pagination.Page = page
+	}
+
+	pagination.Cursor = c.Query("cursor")
+
+	if value := c.Query("page_size"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize < 1 {
+			return pagination, errInvalidPagination
+		}
+		pagination.PageSize = pageSize
+	}
+
+	return pagination, nil
+}
+
+// setLinkHeader adiciona o cabeçalho Link (RFC 8288) com as páginas seguinte e anterior
+func setLinkHeader(c *gin.Context, page *model.CustomerPage) {
+	var links []string
+
+	if page.HasNext() {
+		next := pageURL(c, page.Page+1, page.PageSize)
+		if page.IsCursorPage() {
+			next = cursorURL(c, page.NextCursor, page.PageSize)
+		}
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, next))
+	}
+	if page.HasPrev() {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(c, page.Page-1, page.PageSize)))
+	}
+
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
+
+// pageURL monta a URL da página informada preservando os demais parâmetros da requisição
+func pageURL(c *gin.Context, page, pageSize int) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Del("cursor")
+	query.Set("page", strconv.Itoa(page))
+	query.Set("page_size", strconv.Itoa(pageSize))
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+// cursorURL monta a URL da página seguinte a partir do cursor informado
+func cursorURL(c *gin.Context, cursor string, pageSize int) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Del("page")
+	query.Set("cursor", cursor)
+	query.Set("page_size", strconv.Itoa(pageSize))
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
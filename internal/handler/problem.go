@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+	"github.com/wandermaia/customer-api/internal/rsql"
+	"github.com/wandermaia/customer-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// retryAfterSeconds é o intervalo sugerido ao cliente para repetir requisições que falharam
+// por indisponibilidade do banco de dados
+const retryAfterSeconds = "5"
+
+// statusClientClosedRequest é o status, não padronizado, das requisições canceladas pelo cliente antes da resposta.
+// Ele é registrado nos logs de acesso, mas não é um erro do servidor.
+const statusClientClosedRequest = 499
+
+// respondProblem responde com um problema RFC 7807 com o status e o detalhe informados
+func respondProblem(c *gin.Context, status int, detail string) {
+	utils.RespondProblem(c, utils.NewProblem(status, detail))
+}
+
+// respondServerError registra a causa da falha e responde 503 quando o banco de dados está
+// indisponível ou 500 com o detalhe informado nos demais casos, sem expor a causa ao cliente.
+// Requisições canceladas pelo cliente não são falhas do servidor: recebem 499, sem corpo e sem registro da causa.
+func respondServerError(c *gin.Context, err error, detail string) {
+	if errors.Is(err, context.Canceled) {
+		c.AbortWithStatus(statusClientClosedRequest)
+		return
+	}
+
+	log.Printf("%s %s: %s: %v", c.Request.Method, c.Request.URL.Path, detail, err)
+
+	if errors.Is(err, service.ErrDatabaseUnavailable) {
+		c.Header("Retry-After", retryAfterSeconds)
+		respondProblem(c, http.StatusServiceUnavailable, service.ErrDatabaseUnavailable.Error())
+		return
+	}
+	respondProblem(c, http.StatusInternalServerError, detail)
+}
+
+// respondInvalidBody responde 400 para corpos que não puderam ser decodificados,
+// indicando o campo quando o erro é de tipo
+func respondInvalidBody(c *gin.Context, err error) {
+	problem := utils.NewProblem(http.StatusBadRequest, "Dados inválidos")
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		problem.Errors = []utils.FieldError{{Field: typeErr.Field, Rule: "type", Message: "deve ser do tipo " + typeErr.Type.String()}}
+	}
+
+	utils.RespondProblem(c, problem)
+}
+
+// respondInvalidCustomer responde 400 com os campos do cliente que não passaram na validação
+func respondInvalidCustomer(c *gin.Context, err error) {
+	utils.RespondProblem(c, invalidCustomerProblem(err))
+}
+
+// invalidCustomerProblem descreve os campos do cliente que não passaram na validação
+func invalidCustomerProblem(err error) *utils.Problem {
+	problem := utils.NewProblem(http.StatusBadRequest, err.Error())
+
+	var validation *service.ValidationError
+	if errors.As(err, &validation) {
+		problem.Errors = utils.FieldErrors(validation.Errors)
+	}
+	return problem
+}
+
+// respondAlreadyExists responde 409 com o campo cujo valor já pertence a outro cliente
+func respondAlreadyExists(c *gin.Context, conflict *service.AlreadyExistsError) {
+	utils.RespondProblem(c, alreadyExistsProblem(conflict))
+}
+
+// alreadyExistsProblem descreve o campo cujo valor já pertence a outro cliente
+func alreadyExistsProblem(conflict *service.AlreadyExistsError) *utils.Problem {
+	problem := utils.NewProblem(http.StatusConflict, conflict.Error())
+	problem.Errors = []utils.FieldError{{Field: conflict.Field, Rule: "unique", Message: "já utilizado por outro cliente"}}
+	return problem
+}
+
+// batchItemProblem descreve o erro de um cliente do lote, registrando a causa das falhas internas
+func batchItemProblem(c *gin.Context, index int, err error) *utils.Problem {
+	var problem *utils.Problem
+	var conflict *service.AlreadyExistsError
+	switch {
+	case errors.Is(err, service.ErrInvalidCustomer):
+		problem = invalidCustomerProblem(err)
+	case errors.As(err, &conflict):
+		problem = alreadyExistsProblem(conflict)
+	case errors.Is(err, service.ErrBatchAborted):
+		problem = utils.NewProblem(http.StatusFailedDependency, err.Error())
+	case errors.Is(err, context.Canceled):
+		problem = utils.NewProblem(statusClientClosedRequest, "Requisição cancelada pelo cliente")
+	case errors.Is(err, service.ErrDatabaseUnavailable):
+		log.Printf("%s %s: item %d: %v", c.Request.Method, c.Request.URL.Path, index, err)
+		problem = utils.NewProblem(http.StatusServiceUnavailable, service.ErrDatabaseUnavailable.Error())
+	default:
+		log.Printf("%s %s: item %d: %v", c.Request.Method, c.Request.URL.Path, index, err)
+		problem = utils.NewProblem(http.StatusInternalServerError, "Erro ao criar cliente")
+	}
+
+	problem.Instance = c.Request.URL.Path
+	return problem
+}
+
+// respondInvalidFilter responde 400 para filtros inválidos. Nas expressões de filtro, o problema indica a posição
+// do erro e, quando conhecidos, os valores aceitos nela.
+func respondInvalidFilter(c *gin.Context, err error) {
+	var expressionErr *rsql.Error
+	if !errors.As(err, &expressionErr) {
+		respondProblem(c, http.StatusBadRequest, "Parâmetros de filtro inválidos")
+		return
+	}
+
+	problem := utils.NewProblem(http.StatusBadRequest, expressionErr.Error())
+	problem.Position = expressionErr.Position
+	problem.AllowedValues = expressionErr.Allowed
+	utils.RespondProblem(c, problem)
+}
+
+// respondInvalidSegment responde 400 para segmentos inválidos, com os campos que não passaram na validação ou,
+// quando o erro é da expressão de filtro, com a posição do erro
+func respondInvalidSegment(c *gin.Context, err error) {
+	var expressionErr *rsql.Error
+	if errors.As(err, &expressionErr) {
+		respondInvalidFilter(c, expressionErr)
+		return
+	}
+
+	problem := utils.NewProblem(http.StatusBadRequest, service.ErrInvalidSegment.Error())
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		problem.Errors = utils.FieldErrors(validationErrors)
+	}
+	utils.RespondProblem(c, problem)
+}
+
+// respondInvalidFields responde 400 para seleções de campos inválidas, listando os campos aceitos
+func respondInvalidFields(c *gin.Context, err error) {
+	problem := utils.NewProblem(http.StatusBadRequest, err.Error())
+	problem.AllowedValues = model.SelectableCustomerFields
+	utils.RespondProblem(c, problem)
+}
+
+// respondInvalidSort responde 400 para ordenações inválidas, listando os campos aceitos
+func respondInvalidSort(c *gin.Context, err error, allowed []string) {
+	problem := utils.NewProblem(http.StatusBadRequest, err.Error())
+	problem.AllowedValues = allowed
+	utils.RespondProblem(c, problem)
+}
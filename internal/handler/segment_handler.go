@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+	"github.com/wandermaia/customer-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SegmentHandler struct {
+	service service.SegmentService
+}
+
+// NewSegmentHandler cria uma nova instância do handler de segmentos
+func NewSegmentHandler(service service.SegmentService) *SegmentHandler {
+	return &SegmentHandler{
+		service: service,
+	}
+}
+
+// RegisterRoutes registra as rotas do handler no router do Gin
+func (h *SegmentHandler) RegisterRoutes(router *gin.Engine) {
+	// As rotas negociam a representação pelos cabeçalhos Accept e Content-Type
+	segments := router.Group("/api/segments", negotiateContent)
+	{
+		segments.POST("", h.CreateSegment)
+		segments.GET("", h.GetAllSegments)
+		segments.GET("/:id", h.GetSegmentByID)
+		segments.PUT("/:id", h.UpdateSegment)
+		segments.DELETE("/:id", h.DeleteSegment)
+		segments.GET("/:id/customers", h.GetSegmentCustomers)
+		segments.GET("/:id/count", h.CountSegmentCustomers)
+	}
+}
+
+// CreateSegment cria um novo segmento
+// @Summary Criar um novo segmento
+// @Description Salva uma busca de clientes como um segmento: um nome e uma expressão de filtro RSQL, com a mesma sintaxe do parâmetro filter da listagem de clientes.
+// @Description A expressão é validada na criação; expressões inválidas retornam 400 com a posição do erro em position.
+// @Tags segments
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param segment body SegmentRequest true "Dados do segmento"
+// @Success 201 {object} SegmentResponse
+// @Failure 400 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /segments [post]
+func (h *SegmentHandler) CreateSegment(c *gin.Context) {
+	var request SegmentRequest
+	if err := bindBody(c, &request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	segment := request.toModel(0)
+	if err := h.service.CreateSegment(c.Request.Context(), segment); err != nil {
+		if errors.Is(err, service.ErrInvalidSegment) {
+			respondInvalidSegment(c, err)
+			return
+		}
+		respondServerError(c, err, "Erro ao criar segmento")
+		return
+	}
+
+	respond(c, http.StatusCreated, newSegmentResponse(segment))
+}
+
+// GetAllSegments retorna todos os segmentos
+// @Summary Listar segmentos
+// @Description Retorna todos os segmentos cadastrados, ordenados pelo nome
+// @Tags segments
+// @Accept json
+// @Produce json,xml,application/yaml,application/msgpack
+// @Success 200 {array} SegmentResponse
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /segments [get]
+func (h *SegmentHandler) GetAllSegments(c *gin.Context) {
+	segments, err := h.service.GetAllSegments(c.Request.Context())
+	if err != nil {
+		respondServerError(c, err, "Erro ao buscar segmentos")
+		return
+	}
+
+	respond(c, http.StatusOK, newSegmentResponses(segments))
+}
+
+// GetSegmentByID busca um segmento pelo ID
+// @Summary Buscar segmento por ID
+// @Description Retorna o nome e a expressão de filtro de um segmento
+// @Tags segments
+// @Accept json
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "ID do Segmento"
+// @Success 200 {object} SegmentResponse
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /segments/{id} [get]
+func (h *SegmentHandler) GetSegmentByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	segment, err := h.service.GetSegmentByID(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrSegmentNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao buscar segmento")
+		return
+	}
+
+	respond(c, http.StatusOK, newSegmentResponse(segment))
+}
+
+// UpdateSegment atualiza um segmento existente
+// @Summary Atualizar segmento
+// @Description Substitui o nome e a expressão de filtro de um segmento. A expressão é validada como na criação.
+// @Tags segments
+// @Accept json,xml,application/yaml,application/msgpack
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "ID do Segmento"
+// @Param segment body SegmentRequest true "Dados atualizados do segmento"
+// @Success 200 {object} SegmentResponse
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 415 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /segments/{id} [put]
+func (h *SegmentHandler) UpdateSegment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	var request SegmentRequest
+	if err := bindBody(c, &request); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	segment := request.toModel(uint(id))
+	if err := h.service.UpdateSegment(c.Request.Context(), segment); err != nil {
+		if errors.Is(err, service.ErrInvalidSegment) {
+			respondInvalidSegment(c, err)
+			return
+		}
+		if errors.Is(err, service.ErrSegmentNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao atualizar segmento")
+		return
+	}
+
+	respond(c, http.StatusOK, newSegmentResponse(segment))
+}
+
+// DeleteSegment remove um segmento pelo ID
+// @Summary Excluir segmento
+// @Description Remove um segmento com base no ID. Os clientes do segmento não são alterados.
+// @Tags segments
+// @Accept json
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "ID do Segmento"
+// @Success 204 "No Content"
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /segments/{id} [delete]
+func (h *SegmentHandler) DeleteSegment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	if err := h.service.DeleteSegment(c.Request.Context(), uint(id)); err != nil {
+		if errors.Is(err, service.ErrSegmentNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao excluir segmento")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// GetSegmentCustomers retorna os clientes do segmento de forma paginada
+// @Summary Listar clientes do segmento
+// @Description Avalia a expressão de filtro do segmento e retorna uma página dos clientes que a atendem, com a mesma paginação, ordenação e cabeçalho Link da listagem de clientes.
+// @Tags segments
+// @Accept json
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "ID do Segmento"
+// @Param page query int false "Número da página" default(1)
+// @Param page_size query int false "Quantidade de registros por página (máximo 100)" default(20)
+// @Param cursor query string false "Cursor opaco retornado em next_cursor (paginação por keyset)"
+// @Param sort query string false "Campos de ordenação separados por vírgula; prefixo - para ordem decrescente (ex: -created_at,name)"
+// @Param fields query string false "Campos retornados, separados por vírgula (ex: id,name,active); os demais são omitidos"
+// @Success 200 {object} CustomerPageResponse
+// @Header 200 {string} Link "Links para as páginas seguinte e anterior (RFC 8288)"
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /segments/{id}/customers [get]
+func (h *SegmentHandler) GetSegmentCustomers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	pagination, err := parsePagination(c)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "Parâmetros de paginação inválidos")
+		return
+	}
+
+	fields, err := model.ParseFields(c.Query("fields"))
+	if err != nil {
+		respondInvalidFields(c, err)
+		return
+	}
+
+	sort, err := model.ParseSort(c.Query("sort"))
+	if err != nil {
+		respondInvalidSort(c, err, model.SortableCustomerFields)
+		return
+	}
+
+	page, err := h.service.GetSegmentCustomers(c.Request.Context(), uint(id), fields, sort, pagination)
+	if err != nil {
+		if errors.Is(err, service.ErrSegmentNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSegment) {
+			respondInvalidSegment(c, err)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCursor) {
+			respondProblem(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondServerError(c, err, "Erro ao buscar clientes do segmento")
+		return
+	}
+
+	setLinkHeader(c, page)
+	respond(c, http.StatusOK, withFields(newCustomerPageResponse(page), fields))
+}
+
+// CountSegmentCustomers retorna o número de clientes do segmento
+// @Summary Contar clientes do segmento
+// @Description Avalia a expressão de filtro do segmento e retorna o número de clientes que a atendem, para pré-visualizar o tamanho do segmento
+// @Tags segments
+// @Accept json
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path int true "ID do Segmento"
+// @Success 200 {object} utils.CountResponse
+// @Failure 400 {object} utils.Problem
+// @Failure 404 {object} utils.Problem
+// @Failure 406 {object} utils.Problem
+// @Failure 500 {object} utils.Problem
+// @Failure 503 {object} utils.Problem
+// @Router /segments/{id}/count [get]
+func (h *SegmentHandler) CountSegmentCustomers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondProblem(c, http.StatusBadRequest, "ID inválido")
+		return
+	}
+
+	count, err := h.service.CountSegmentCustomers(c.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrSegmentNotFound) {
+			respondProblem(c, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSegment) {
+			respondInvalidSegment(c, err)
+			return
+		}
+		respondServerError(c, err, "Erro ao contar clientes do segmento")
+		return
+	}
+
+	respond(c, http.StatusOK, utils.CountResponse{Count: count})
+}
@@ -0,0 +1,313 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+	mock_service "github.com/wandermaia/customer-api/internal/domain/service/mock"
+	"github.com/wandermaia/customer-api/internal/handler"
+	"github.com/wandermaia/customer-api/internal/rsql"
+	"github.com/wandermaia/customer-api/internal/utils"
+)
+
+// setupSegmentRouter cria um Gin engine de teste com as rotas do SegmentHandler sobre o serviço mockado.
+func setupSegmentRouter(t *testing.T) (*gin.Engine, *mock_service.MockSegmentService) {
+	gin.SetMode(gin.TestMode)
+	mockService := mock_service.NewMockSegmentService(gomock.NewController(t))
+	router := gin.New()
+	handler.NewSegmentHandler(mockService).RegisterRoutes(router)
+	return router, mockService
+}
+
+// TestSegmentHandler_CreateSegment testa o endpoint POST /api/segments.
+func TestSegmentHandler_CreateSegment(t *testing.T) {
+	router, mockService := setupSegmentRouter(t)
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		body := []byte(`{"id": 99, "name": "Ativos da Corp", "filter": "active==true;email=ilike=*@corp.com"}`)
+
+		// Define a expectativa: o serviço recebe o segmento sem o id enviado no corpo.
+		mockService.EXPECT().
+			CreateSegment(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, s *model.Segment) error {
+				assert.Zero(t, s.ID)
+				assert.Equal(t, "Ativos da Corp", s.Name)
+				assert.Equal(t, "active==true;email=ilike=*@corp.com", s.Filter)
+				s.ID = 1
+				return nil
+			}).Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/segments", body)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var response handler.SegmentResponse
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, uint(1), response.ID)
+		assert.Equal(t, "Ativos da Corp", response.Name)
+	})
+
+	// Subteste para o cenário em que a expressão de filtro é inválida.
+	t.Run("Invalid Filter Expression", func(t *testing.T) {
+		parseErr := &rsql.Error{Position: 14, Message: "esperado nome de campo, encontrado o fim da expressão"}
+
+		// Define a expectativa: o serviço rejeita a expressão com a posição do erro.
+		mockService.EXPECT().
+			CreateSegment(gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("%w: %w", service.ErrInvalidSegment, parseErr)).
+			Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/segments", []byte(`{"name": "Ativos", "filter": "active==true;"}`))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var problem utils.Problem
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+		assert.Equal(t, 14, problem.Position)
+		assert.Equal(t, parseErr.Error(), problem.Detail)
+	})
+
+	// Subteste para o cenário em que os campos do segmento não passam na validação.
+	t.Run("Validation Error", func(t *testing.T) {
+		validationErr := validator.New().Struct(struct {
+			Name string `validate:"required"`
+		}{})
+
+		mockService.EXPECT().
+			CreateSegment(gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("%w: %w", service.ErrInvalidSegment, validationErr)).
+			Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPost, "/api/segments", []byte(`{"filter": "active==true"}`))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var problem utils.Problem
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+		assert.Equal(t, service.ErrInvalidSegment.Error(), problem.Detail)
+		if assert.Len(t, problem.Errors, 1) {
+			assert.Equal(t, "required", problem.Errors[0].Rule)
+		}
+	})
+}
+
+// TestSegmentHandler_GetAllSegments testa o endpoint GET /api/segments.
+func TestSegmentHandler_GetAllSegments(t *testing.T) {
+	router, mockService := setupSegmentRouter(t)
+
+	// Subteste para o cenário de sucesso em XML, com a lista envolvida no elemento segments.
+	t.Run("Success XML", func(t *testing.T) {
+		mockService.EXPECT().
+			GetAllSegments(gomock.Any()).
+			Return([]*model.Segment{{ID: 1, Name: "Ativos", Filter: "active==true"}}, nil).
+			Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequestWithHeaders(router, recorder, http.MethodGet, "/api/segments", nil, map[string]string{"Accept": "application/xml"})
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "<segments><segment><id>1</id><name>Ativos</name><filter>active==true</filter>")
+	})
+
+	// Subteste para o cenário sem segmentos: a lista é vazia, e não nula.
+	t.Run("Empty", func(t *testing.T) {
+		mockService.EXPECT().GetAllSegments(gomock.Any()).Return(nil, nil).Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/segments", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `[]`, recorder.Body.String())
+	})
+}
+
+// TestSegmentHandler_GetSegmentByID testa o endpoint GET /api/segments/:id.
+func TestSegmentHandler_GetSegmentByID(t *testing.T) {
+	router, mockService := setupSegmentRouter(t)
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		mockService.EXPECT().
+			GetSegmentByID(gomock.Any(), uint(1)).
+			Return(&model.Segment{ID: 1, Name: "Ativos", Filter: "active==true"}, nil).
+			Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/segments/1", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response handler.SegmentResponse
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, "active==true", response.Filter)
+	})
+
+	// Subteste para o cenário de segmento não encontrado.
+	t.Run("Not Found", func(t *testing.T) {
+		mockService.EXPECT().GetSegmentByID(gomock.Any(), uint(2)).Return(nil, service.ErrSegmentNotFound).Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/segments/2", nil)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrSegmentNotFound.Error())
+	})
+
+	// Subteste para o cenário de ID inválido: o serviço não é chamado.
+	t.Run("Invalid ID", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/segments/abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "ID inválido")
+	})
+}
+
+// TestSegmentHandler_UpdateSegment testa o endpoint PUT /api/segments/:id.
+func TestSegmentHandler_UpdateSegment(t *testing.T) {
+	router, mockService := setupSegmentRouter(t)
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		// Define a expectativa: o serviço recebe o segmento com o ID da URL.
+		mockService.EXPECT().
+			UpdateSegment(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, s *model.Segment) error {
+				assert.Equal(t, uint(1), s.ID)
+				assert.Equal(t, "active==false", s.Filter)
+				return nil
+			}).Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPut, "/api/segments/1", []byte(`{"name": "Inativos", "filter": "active==false"}`))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `"name":"Inativos"`)
+	})
+
+	// Subteste para o cenário de segmento não encontrado.
+	t.Run("Not Found", func(t *testing.T) {
+		mockService.EXPECT().UpdateSegment(gomock.Any(), gomock.Any()).Return(service.ErrSegmentNotFound).Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodPut, "/api/segments/2", []byte(`{"name": "Inativos", "filter": "active==false"}`))
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+// TestSegmentHandler_DeleteSegment testa o endpoint DELETE /api/segments/:id.
+func TestSegmentHandler_DeleteSegment(t *testing.T) {
+	router, mockService := setupSegmentRouter(t)
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		mockService.EXPECT().DeleteSegment(gomock.Any(), uint(1)).Return(nil).Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodDelete, "/api/segments/1", nil)
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	// Subteste para o cenário de falha do banco de dados.
+	t.Run("Service Internal Error", func(t *testing.T) {
+		mockService.EXPECT().DeleteSegment(gomock.Any(), uint(1)).Return(errors.New("db error")).Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodDelete, "/api/segments/1", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "Erro ao excluir segmento")
+	})
+}
+
+// TestSegmentHandler_GetSegmentCustomers testa o endpoint GET /api/segments/:id/customers.
+func TestSegmentHandler_GetSegmentCustomers(t *testing.T) {
+	router, mockService := setupSegmentRouter(t)
+
+	// Subteste para o cenário de sucesso, com a paginação, a ordenação e o cabeçalho Link da listagem de clientes.
+	t.Run("Success", func(t *testing.T) {
+		page := &model.CustomerPage{
+			Items:    []*model.Customer{{ID: 3, Name: "Ana", Email: "ana@corp.com", Active: true}},
+			Total:    int64Ptr(3),
+			Page:     2,
+			PageSize: 1,
+		}
+		mockService.EXPECT().
+			GetSegmentCustomers(gomock.Any(), uint(1), nil, model.Sort{{Field: "name"}}, model.Pagination{Page: 2, PageSize: 1}).
+			Return(page, nil).
+			Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/segments/1/customers?page=2&page_size=1&sort=name", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var response handler.CustomerPageResponse
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, int64(3), *response.Total)
+		if assert.Len(t, response.Items, 1) {
+			assert.Equal(t, "ana@corp.com", response.Items[0].Email)
+		}
+		assert.Contains(t, recorder.Header().Get("Link"), `rel="prev"`)
+	})
+
+	// Subteste para o cenário de ordenação inválida: o serviço não é chamado.
+	t.Run("Invalid Sort", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/segments/1/customers?sort=password", nil)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	// Subteste para o cenário de segmento não encontrado.
+	t.Run("Not Found", func(t *testing.T) {
+		mockService.EXPECT().
+			GetSegmentCustomers(gomock.Any(), uint(2), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, service.ErrSegmentNotFound).
+			Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/segments/2/customers", nil)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+// TestSegmentHandler_CountSegmentCustomers testa o endpoint GET /api/segments/:id/count.
+func TestSegmentHandler_CountSegmentCustomers(t *testing.T) {
+	router, mockService := setupSegmentRouter(t)
+
+	// Subteste para o cenário de sucesso.
+	t.Run("Success", func(t *testing.T) {
+		mockService.EXPECT().CountSegmentCustomers(gomock.Any(), uint(1)).Return(int64(7), nil).Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/segments/1/count", nil)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"count": 7}`, recorder.Body.String())
+	})
+
+	// Subteste para o cenário de segmento não encontrado.
+	t.Run("Not Found", func(t *testing.T) {
+		mockService.EXPECT().CountSegmentCustomers(gomock.Any(), uint(2)).Return(int64(0), service.ErrSegmentNotFound).Times(1)
+
+		recorder := httptest.NewRecorder()
+		performRequest(router, recorder, http.MethodGet, "/api/segments/2/count", nil)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/wandermaia/customer-api/internal/domain/service"
+	"github.com/wandermaia/customer-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// idempotencyKeyHeader é o cabeçalho que identifica uma requisição POST repetível
+	idempotencyKeyHeader = "Idempotency-Key"
+	// maxIdempotencyKeyLength é o tamanho máximo aceito para a chave de idempotência
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders são os cabeçalhos da resposta original, além do Content-Type, devolvidos nas repetições
+var replayedHeaders = []string{"ETag", "Last-Modified", "Location"}
+
+// Idempotency é um middleware que evita o processamento duplicado de requisições POST
+// que informam o cabeçalho Idempotency-Key. A resposta de sucesso da requisição original
+// é armazenada e devolvida nas repetições com a mesma chave e o mesmo conteúdo.
+// O corpo da requisição é lido por completo para compor a impressão digital; por isso, o middleware
+// deve ser registrado somente nas rotas de criação com corpos pequenos, e nunca nas que leem o corpo em fluxo.
+func Idempotency(idempotency service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			utils.RespondProblem(c, utils.NewProblem(http.StatusBadRequest, "Idempotency-Key deve ter no máximo 255 caracteres"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			utils.RespondProblem(c, utils.NewProblem(http.StatusBadRequest, "Dados inválidos"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// A resposta é armazenada mesmo que o cliente desconecte antes do fim da requisição
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		record, err := idempotency.Begin(ctx, key, requestFingerprint(c.Request, body))
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrIdempotencyKeyReused):
+				utils.RespondProblem(c, utils.NewProblem(http.StatusUnprocessableEntity, err.Error()))
+			case errors.Is(err, service.ErrIdempotencyKeyInProgress):
+				utils.RespondProblem(c, utils.NewProblem(http.StatusConflict, err.Error()))
+			case errors.Is(err, service.ErrDatabaseUnavailable):
+				log.Printf("Falha ao verificar a chave de idempotência %q: %v", key, err)
+				utils.RespondProblem(c, utils.NewProblem(http.StatusServiceUnavailable, service.ErrDatabaseUnavailable.Error()))
+			default:
+				log.Printf("Falha ao verificar a chave de idempotência %q: %v", key, err)
+				utils.RespondProblem(c, utils.NewProblem(http.StatusInternalServerError, "Erro ao verificar a chave de idempotência"))
+			}
+			return
+		}
+
+		// Repetição de uma requisição já concluída: devolve a resposta original
+		if record != nil {
+			for name, value := range record.ResponseHeaders {
+				c.Header(name, value)
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		completed := false
+		defer func() {
+			// Libera a chave quando a requisição falha, inclusive em caso de panic,
+			// para que o cliente possa repeti-la
+			if !completed {
+				if err := idempotency.Release(ctx, key); err != nil {
+					log.Printf("Falha ao liberar a chave de idempotência %q: %v", key, err)
+				}
+			}
+		}()
+
+		writer := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if status := writer.Status(); status >= 200 && status < 300 {
+			if err := idempotency.Complete(ctx, key, status, writer.Header().Get("Content-Type"), responseHeaders(writer.Header()), writer.body.Bytes()); err != nil {
+				log.Printf("Falha ao armazenar a resposta da chave de idempotência %q: %v", key, err)
+				return
+			}
+			completed = true
+		}
+	}
+}
+
+// responseHeaders retorna os cabeçalhos de replayedHeaders informados na resposta
+func responseHeaders(header http.Header) map[string]string {
+	headers := map[string]string{}
+	for _, name := range replayedHeaders {
+		if value := header.Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	return headers
+}
+
+// requestFingerprint identifica o conteúdo da requisição associado à chave de idempotência
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// bodyRecorder replica o corpo da resposta escrito pelos handlers
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,179 @@
+package middleware_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/wandermaia/customer-api/internal/domain/model"
+	"github.com/wandermaia/customer-api/internal/domain/service"
+	mock_service "github.com/wandermaia/customer-api/internal/domain/service/mock"
+	"github.com/wandermaia/customer-api/internal/middleware"
+)
+
+// setupIdempotencyRouter configura um router com o middleware de idempotência e uma rota POST
+// que conta quantas vezes foi executada e responde com o status informado.
+func setupIdempotencyRouter(t *testing.T, status int) (*gin.Engine, *mock_service.MockIdempotencyService, *int) {
+	gin.SetMode(gin.TestMode)
+	mockService := mock_service.NewMockIdempotencyService(gomock.NewController(t))
+
+	calls := 0
+	router := gin.New()
+	router.Use(middleware.Idempotency(mockService))
+	router.POST("/api/customers", func(c *gin.Context) {
+		calls++
+		c.Header("ETag", `"1"`)
+		c.JSON(status, gin.H{"id": 1})
+	})
+	router.GET("/api/customers", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	return router, mockService, &calls
+}
+
+func postWithKey(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/customers", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// TestIdempotency verifica o processamento da primeira requisição, a repetição com a mesma chave,
+// a reutilização da chave com outro conteúdo e a liberação da chave quando a requisição falha.
+func TestIdempotency(t *testing.T) {
+	key := "6f1c2a8e-3b7d-4e5f-9a0b-1c2d3e4f5a6b"
+
+	// Subteste para a primeira requisição com a chave: processa e armazena a resposta.
+	t.Run("First Request Stores Response", func(t *testing.T) {
+		router, mockService, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+		var fingerprint string
+		mockService.EXPECT().
+			Begin(gomock.Any(), key, gomock.Any()).
+			DoAndReturn(func(_ any, _ string, fp string) (*model.IdempotencyRecord, error) {
+				fingerprint = fp
+				return nil, nil
+			}).Times(1)
+		// Expectativa: a resposta de sucesso é armazenada com o status, o tipo, a ETag e o corpo.
+		mockService.EXPECT().
+			Complete(gomock.Any(), key, http.StatusCreated, "application/json; charset=utf-8", map[string]string{"ETag": `"1"`}, []byte(`{"id":1}`)).
+			Return(nil).Times(1)
+
+		recorder := postWithKey(router, key, `{"name": "Maria"}`)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, 1, *calls)
+		assert.Len(t, fingerprint, 64)
+	})
+
+	// Subteste para a repetição de uma requisição concluída: devolve a resposta original sem executar o handler.
+	t.Run("Replay Returns Original Response", func(t *testing.T) {
+		router, mockService, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+		stored := &model.IdempotencyRecord{
+			Key: key, StatusCode: http.StatusCreated, ContentType: "application/json; charset=utf-8", ResponseBody: []byte(`{"id":42}`),
+			ResponseHeaders: map[string]string{"ETag": `"3"`, "Location": "/api/customers/42"},
+		}
+		mockService.EXPECT().Begin(gomock.Any(), key, gomock.Any()).Return(stored, nil).Times(1)
+
+		recorder := postWithKey(router, key, `{"name": "Maria"}`)
+
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		assert.Equal(t, `{"id":42}`, recorder.Body.String())
+		assert.Equal(t, `"3"`, recorder.Header().Get("ETag"))
+		assert.Equal(t, "/api/customers/42", recorder.Header().Get("Location"))
+		assert.Equal(t, "true", recorder.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, 0, *calls)
+	})
+
+	// Subteste para a mesma chave com conteúdo diferente.
+	t.Run("Key Reused Returns 422", func(t *testing.T) {
+		router, mockService, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+		mockService.EXPECT().Begin(gomock.Any(), key, gomock.Any()).Return(nil, service.ErrIdempotencyKeyReused).Times(1)
+
+		recorder := postWithKey(router, key, `{"name": "Outra"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), service.ErrIdempotencyKeyReused.Error())
+		assert.Equal(t, 0, *calls)
+	})
+
+	// Subteste para a repetição enquanto a requisição original ainda está em andamento.
+	t.Run("Request In Progress Returns 409", func(t *testing.T) {
+		router, mockService, _ := setupIdempotencyRouter(t, http.StatusCreated)
+
+		mockService.EXPECT().Begin(gomock.Any(), key, gomock.Any()).Return(nil, service.ErrIdempotencyKeyInProgress).Times(1)
+
+		recorder := postWithKey(router, key, `{"name": "Maria"}`)
+
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	// Subteste para requisições que falham: a chave é liberada para que o cliente possa repetir.
+	t.Run("Failed Request Releases Key", func(t *testing.T) {
+		router, mockService, calls := setupIdempotencyRouter(t, http.StatusBadRequest)
+
+		mockService.EXPECT().Begin(gomock.Any(), key, gomock.Any()).Return(nil, nil).Times(1)
+		mockService.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockService.EXPECT().Release(gomock.Any(), key).Return(nil).Times(1)
+
+		recorder := postWithKey(router, key, `{"name": ""}`)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, 1, *calls)
+	})
+
+	// Subteste para falha ao verificar a chave.
+	t.Run("Service Error Returns 500", func(t *testing.T) {
+		router, mockService, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+		mockService.EXPECT().Begin(gomock.Any(), key, gomock.Any()).Return(nil, errors.New("connection refused")).Times(1)
+
+		recorder := postWithKey(router, key, `{"name": "Maria"}`)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Equal(t, 0, *calls)
+	})
+
+	// Subteste para chaves maiores que o limite aceito.
+	t.Run("Key Too Long", func(t *testing.T) {
+		router, _, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+		recorder := postWithKey(router, strings.Repeat("a", 256), `{"name": "Maria"}`)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, 0, *calls)
+	})
+
+	// Subteste para requisições sem a chave ou que não são POST: o middleware não interfere.
+	t.Run("Without Key Or Not POST", func(t *testing.T) {
+		router, mockService, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+		mockService.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		recorder := postWithKey(router, "", `{"name": "Maria"}`)
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/customers", nil)
+		req.Header.Set("Idempotency-Key", key)
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		assert.Equal(t, 2, *calls)
+	})
+}
@@ -0,0 +1,69 @@
+// Package rsql implementa um dialeto reduzido da linguagem de consulta RSQL para expressões de filtro,
+// como active==true;(name=like=Silva*,email=like=*@corp.com).
+//
+// Comparações são combinadas com ; (AND) e , (OR), e o AND tem precedência sobre o OR; parênteses
+// agrupam as expressões. Os campos e os tipos de seus valores são definidos por um Schema.
+package rsql
+
+// FieldType é o tipo dos valores de um campo, que define os operadores aceitos e a conversão dos valores
+type FieldType int
+
+const (
+	String FieldType = iota
+	Integer
+	Boolean
+	// Time aceita datas no formato RFC 3339 ou AAAA-MM-DD
+	Time
+)
+
+// Schema associa os campos que podem ser usados nas expressões aos tipos de seus valores
+type Schema map[string]FieldType
+
+// Operator é um operador de comparação, em sua forma canônica
+type Operator string
+
+const (
+	Equal          Operator = "=="
+	NotEqual       Operator = "!="
+	Less           Operator = "=lt="
+	LessOrEqual    Operator = "=le="
+	Greater        Operator = "=gt="
+	GreaterOrEqual Operator = "=ge="
+	In             Operator = "=in="
+	NotIn          Operator = "=out="
+	// Like compara o texto com um padrão em que * representa qualquer sequência de caracteres
+	Like Operator = "=like="
+	// ILike é o Like sem diferenciar maiúsculas e minúsculas
+	ILike Operator = "=ilike="
+)
+
+// LogicalOperator combina os operandos de uma expressão lógica
+type LogicalOperator string
+
+const (
+	And LogicalOperator = ";"
+	Or  LogicalOperator = ","
+)
+
+// Node é um nó da árvore sintática de uma expressão de filtro: *Comparison ou *Logical
+type Node interface {
+	node()
+}
+
+// Comparison compara um campo com um ou mais valores, já convertidos para o tipo do campo:
+// string, int64, bool ou time.Time. Somente In e NotIn têm mais de um valor.
+type Comparison struct {
+	Field    string
+	Operator Operator
+	Values   []any
+}
+
+// Logical combina duas ou mais expressões com o mesmo operador lógico
+type Logical struct {
+	Operator LogicalOperator
+	Operands []Node
+}
+
+func (*Comparison) node() {}
+
+func (*Logical) node() {}
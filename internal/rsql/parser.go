@@ -0,0 +1,391 @@
+package rsql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Error indica uma expressão inválida e a posição, a partir de 1, do caractere em que o erro foi encontrado
+type Error struct {
+	Position int
+	Message  string
+	// Allowed lista os valores aceitos na posição do erro, quando conhecidos
+	Allowed []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("expressão de filtro inválida na posição %d: %s", e.Position, e.Message)
+}
+
+// namedOperators são os operadores na forma =nome=, indexados pelo nome
+var namedOperators = map[string]Operator{
+	"lt":    Less,
+	"le":    LessOrEqual,
+	"gt":    Greater,
+	"ge":    GreaterOrEqual,
+	"in":    In,
+	"out":   NotIn,
+	"like":  Like,
+	"ilike": ILike,
+}
+
+// symbolOperators são os operadores simbólicos, com os de dois caracteres antes dos seus prefixos
+var symbolOperators = []struct {
+	symbol   string
+	operator Operator
+}{
+	{"==", Equal},
+	{"!=", NotEqual},
+	{"<=", LessOrEqual},
+	{">=", GreaterOrEqual},
+	{"<", Less},
+	{">", Greater},
+}
+
+var (
+	errInvalidValue  = errors.New("valor inválido")
+	errNegativeValue = errors.New("valor negativo")
+	// errValueOutOfRange indica um inteiro maior que o aceito pelas colunas bigint
+	errValueOutOfRange = fmt.Errorf("valor acima de %d", math.MaxInt64)
+)
+
+// timeLayouts são os formatos aceitos nos valores de campos Time
+var timeLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// parser analisa a expressão por descida recursiva, sobre a gramática:
+//
+//	or         = and { "," and }
+//	and        = primary { ";" primary }
+//	primary    = "(" or ")" | comparison
+//	comparison = field operator ( value | "(" value { "," value } ")" )
+//	value      = texto sem caracteres reservados | 'texto' | "texto"
+type parser struct {
+	input  []rune
+	pos    int
+	schema Schema
+}
+
+// Parse converte a expressão na sua árvore sintática, validando os campos contra o schema e convertendo os
+// valores para o tipo de cada campo. Os erros são do tipo *Error.
+func Parse(input string, schema Schema) (Node, error) {
+	p := &parser{input: []rune(input), schema: schema}
+
+	p.skipSpaces()
+	if p.eof() {
+		return nil, p.errorAt(p.pos, "a expressão está vazia")
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if !p.eof() {
+		return nil, p.errorAt(p.pos, fmt.Sprintf("caractere inesperado %q", p.peek()))
+	}
+	return node, nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	return p.parseLogical(Or, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	return p.parseLogical(And, p.parsePrimary)
+}
+
+// parseLogical lê os operandos separados pelo operador lógico, retornando o próprio operando quando há apenas um
+func (p *parser) parseLogical(operator LogicalOperator, operand func() (Node, error)) (Node, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []Node{first}
+	for p.consume(string(operator)) {
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &Logical{Operator: operator, Operands: operands}, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	p.skipSpaces()
+	start := p.pos
+	if !p.consume("(") {
+		return p.parseComparison()
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(")") {
+		return nil, p.expected("')' para fechar o '(' da posição " + strconv.Itoa(start+1))
+	}
+	return node, nil
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	start := p.pos
+	for !p.eof() && isFieldRune(p.peek()) {
+		p.pos++
+	}
+	field := string(p.input[start:p.pos])
+	if field == "" {
+		return nil, p.expected("nome de campo")
+	}
+
+	fieldType, ok := p.schema[field]
+	if !ok {
+		err := p.errorAt(start, fmt.Sprintf("o campo %q não pode ser usado no filtro", field))
+		err.Allowed = p.fields()
+		return nil, err
+	}
+
+	p.skipSpaces()
+	operatorPos := p.pos
+	operator, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+	if !accepts(fieldType, operator) {
+		return nil, p.errorAt(operatorPos, fmt.Sprintf("o operador %s não pode ser usado com o campo %q", operator, field))
+	}
+
+	p.skipSpaces()
+	var values []any
+	if p.consume("(") {
+		if operator != In && operator != NotIn {
+			return nil, p.errorAt(p.pos-1, fmt.Sprintf("o operador %s aceita somente um valor", operator))
+		}
+		for {
+			value, err := p.parseValue(field, fieldType)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if p.consume(")") {
+				break
+			}
+			if !p.consume(",") {
+				return nil, p.expected("',' ou ')' na lista de valores")
+			}
+		}
+	} else {
+		value, err := p.parseValue(field, fieldType)
+		if err != nil {
+			return nil, err
+		}
+		values = []any{value}
+	}
+
+	return &Comparison{Field: field, Operator: operator, Values: values}, nil
+}
+
+func (p *parser) parseOperator() (Operator, error) {
+	for _, s := range symbolOperators {
+		if p.consume(s.symbol) {
+			return s.operator, nil
+		}
+	}
+
+	start := p.pos
+	if !p.consume("=") {
+		return "", p.expected("operador de comparação")
+	}
+	for !p.eof() && unicode.IsLetter(p.peek()) {
+		p.pos++
+	}
+	name := string(p.input[start+1 : p.pos])
+	operator, ok := namedOperators[name]
+	if !ok || !p.consume("=") {
+		err := p.errorAt(start, fmt.Sprintf("operador desconhecido %q", string(p.input[start:p.pos])))
+		err.Allowed = operatorNames()
+		return "", err
+	}
+	return operator, nil
+}
+
+// parseValue lê um valor, entre aspas ou não, e o converte para o tipo do campo
+func (p *parser) parseValue(field string, fieldType FieldType) (any, error) {
+	p.skipSpaces()
+	start := p.pos
+
+	var text string
+	if quote := p.peekOrZero(); quote == '"' || quote == '\'' {
+		var err error
+		if text, err = p.parseQuoted(quote); err != nil {
+			return nil, err
+		}
+	} else {
+		for !p.eof() && !isReserved(p.peek()) {
+			p.pos++
+		}
+		text = string(p.input[start:p.pos])
+		if text == "" {
+			return nil, p.expected("valor")
+		}
+	}
+
+	value, err := convert(text, fieldType)
+	if err != nil {
+		return nil, p.errorAt(start, fmt.Sprintf("%s para o campo %q: %q", err, field, text))
+	}
+	p.skipSpaces()
+	return value, nil
+}
+
+// parseQuoted lê um texto entre aspas, em que \ escapa o caractere seguinte
+func (p *parser) parseQuoted(quote rune) (string, error) {
+	start := p.pos
+	p.pos++
+
+	var text strings.Builder
+	for !p.eof() {
+		r := p.peek()
+		p.pos++
+		switch {
+		case r == quote:
+			return text.String(), nil
+		case r == '\\' && !p.eof():
+			text.WriteRune(p.peek())
+			p.pos++
+		default:
+			text.WriteRune(r)
+		}
+	}
+	return "", p.errorAt(start, "as aspas abertas nesta posição não foram fechadas")
+}
+
+// convert converte o texto para o tipo do campo. Inteiros negativos ou acima de math.MaxInt64 não são aceitos.
+func convert(text string, fieldType FieldType) (any, error) {
+	switch fieldType {
+	case Integer:
+		value, err := strconv.ParseInt(text, 10, 64)
+		switch {
+		case err == nil && value >= 0:
+			return value, nil
+		case err == nil, errors.Is(err, strconv.ErrRange) && strings.HasPrefix(text, "-"):
+			return nil, errNegativeValue
+		case errors.Is(err, strconv.ErrRange):
+			return nil, errValueOutOfRange
+		}
+		return nil, errInvalidValue
+	case Boolean:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, errInvalidValue
+		}
+		return value, nil
+	case Time:
+		for _, layout := range timeLayouts {
+			if value, err := time.Parse(layout, text); err == nil {
+				return value, nil
+			}
+		}
+		return nil, errInvalidValue
+	default:
+		return text, nil
+	}
+}
+
+// accepts indica se o operador pode ser usado com campos do tipo informado
+func accepts(fieldType FieldType, operator Operator) bool {
+	switch operator {
+	case Like, ILike:
+		return fieldType == String
+	case Less, LessOrEqual, Greater, GreaterOrEqual:
+		return fieldType != Boolean
+	default:
+		return true
+	}
+}
+
+// consume avança sobre o texto informado, ignorando os espaços que o precedem, e indica se ele foi encontrado
+func (p *parser) consume(text string) bool {
+	p.skipSpaces()
+	runes := []rune(text)
+	if len(p.input)-p.pos < len(runes) || string(p.input[p.pos:p.pos+len(runes)]) != text {
+		return false
+	}
+	p.pos += len(runes)
+	return true
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() rune {
+	return p.input[p.pos]
+}
+
+// peekOrZero retorna o caractere atual, ou zero ao final da expressão
+func (p *parser) peekOrZero() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.peek()
+}
+
+// expected indica o que era esperado na posição atual
+func (p *parser) expected(what string) *Error {
+	if p.eof() {
+		return p.errorAt(p.pos, "esperado "+what+", encontrado o fim da expressão")
+	}
+	return p.errorAt(p.pos, fmt.Sprintf("esperado %s, encontrado %q", what, p.peek()))
+}
+
+func (p *parser) errorAt(pos int, message string) *Error {
+	return &Error{Position: pos + 1, Message: message}
+}
+
+// fields retorna os campos do schema em ordem alfabética
+func (p *parser) fields() []string {
+	fields := make([]string, 0, len(p.schema))
+	for field := range p.schema {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// operatorNames retorna todos os operadores aceitos, na forma canônica
+func operatorNames() []string {
+	operators := []Operator{Equal, NotEqual, Less, LessOrEqual, Greater, GreaterOrEqual, In, NotIn, Like, ILike}
+	names := make([]string, len(operators))
+	for i, operator := range operators {
+		names[i] = string(operator)
+	}
+	return names
+}
+
+func isFieldRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isReserved indica os caracteres que encerram um valor sem aspas
+func isReserved(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(`"'();,=!<>`, r)
+}
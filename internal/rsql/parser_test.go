@@ -0,0 +1,124 @@
+package rsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wandermaia/customer-api/internal/rsql"
+)
+
+// schema é o conjunto de campos usado nos testes, com um campo de cada tipo
+var schema = rsql.Schema{
+	"id":         rsql.Integer,
+	"name":       rsql.String,
+	"email":      rsql.String,
+	"active":     rsql.Boolean,
+	"created_at": rsql.Time,
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Single Comparison", func(t *testing.T) {
+		node, err := rsql.Parse("active==true", schema)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &rsql.Comparison{Field: "active", Operator: rsql.Equal, Values: []any{true}}, node)
+	})
+
+	t.Run("AND Takes Precedence Over OR", func(t *testing.T) {
+		node, err := rsql.Parse("id==1,id==2;name==Ana", schema)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &rsql.Logical{Operator: rsql.Or, Operands: []rsql.Node{
+			&rsql.Comparison{Field: "id", Operator: rsql.Equal, Values: []any{int64(1)}},
+			&rsql.Logical{Operator: rsql.And, Operands: []rsql.Node{
+				&rsql.Comparison{Field: "id", Operator: rsql.Equal, Values: []any{int64(2)}},
+				&rsql.Comparison{Field: "name", Operator: rsql.Equal, Values: []any{"Ana"}},
+			}},
+		}}, node)
+	})
+
+	t.Run("Parentheses", func(t *testing.T) {
+		node, err := rsql.Parse("active==true;(name=like=Silva*,email=like=*@corp.com)", schema)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &rsql.Logical{Operator: rsql.And, Operands: []rsql.Node{
+			&rsql.Comparison{Field: "active", Operator: rsql.Equal, Values: []any{true}},
+			&rsql.Logical{Operator: rsql.Or, Operands: []rsql.Node{
+				&rsql.Comparison{Field: "name", Operator: rsql.Like, Values: []any{"Silva*"}},
+				&rsql.Comparison{Field: "email", Operator: rsql.Like, Values: []any{"*@corp.com"}},
+			}},
+		}}, node)
+	})
+
+	t.Run("Value Lists, Quotes And Spaces", func(t *testing.T) {
+		node, err := rsql.Parse(` id=in=(1, 2 ,3) ; name != 'Ana \'Maria\'' ; email=ilike="a,b;c"`, schema)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &rsql.Logical{Operator: rsql.And, Operands: []rsql.Node{
+			&rsql.Comparison{Field: "id", Operator: rsql.In, Values: []any{int64(1), int64(2), int64(3)}},
+			&rsql.Comparison{Field: "name", Operator: rsql.NotEqual, Values: []any{"Ana 'Maria'"}},
+			&rsql.Comparison{Field: "email", Operator: rsql.ILike, Values: []any{"a,b;c"}},
+		}}, node)
+	})
+
+	t.Run("Symbolic Operators And Dates", func(t *testing.T) {
+		node, err := rsql.Parse("created_at>=2025-01-01;created_at<2025-06-01T12:00:00Z", schema)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &rsql.Logical{Operator: rsql.And, Operands: []rsql.Node{
+			&rsql.Comparison{Field: "created_at", Operator: rsql.GreaterOrEqual, Values: []any{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			&rsql.Comparison{Field: "created_at", Operator: rsql.Less, Values: []any{time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}},
+		}}, node)
+	})
+
+	t.Run("Errors Point At Position", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			position int
+			message  string
+		}{
+			{"", 1, "a expressão está vazia"},
+			{"active==true;", 14, "esperado nome de campo, encontrado o fim da expressão"},
+			{"active==true;(name==Ana", 24, "esperado ')' para fechar o '(' da posição 14, encontrado o fim da expressão"},
+			{"active==true)", 13, `caractere inesperado ')'`},
+			{"password==123", 1, `o campo "password" não pode ser usado no filtro`},
+			{"name=x", 5, `operador desconhecido "=x"`},
+			{"name=~Ana", 5, `operador desconhecido "="`},
+			{"name Ana", 6, `esperado operador de comparação, encontrado 'A'`},
+			{"active=like=tru*", 7, `o operador =like= não pode ser usado com o campo "active"`},
+			{"active=gt=false", 7, `o operador =gt= não pode ser usado com o campo "active"`},
+			{"id==(1,2)", 5, "o operador == aceita somente um valor"},
+			{"id=in=(1,2", 11, "esperado ',' ou ')' na lista de valores, encontrado o fim da expressão"},
+			{"id==abc", 5, `valor inválido para o campo "id": "abc"`},
+			{"id==-1", 5, `valor negativo para o campo "id": "-1"`},
+			{"id=in=(1,18446744073709551615)", 10, `valor acima de 9223372036854775807 para o campo "id": "18446744073709551615"`},
+			{"id==18446744073709551615", 5, `valor acima de 9223372036854775807 para o campo "id": "18446744073709551615"`},
+			{"id==-18446744073709551615", 5, `valor negativo para o campo "id": "-18446744073709551615"`},
+			{"created_at>ontem", 12, `valor inválido para o campo "created_at": "ontem"`},
+			{"name=='Ana", 7, "as aspas abertas nesta posição não foram fechadas"},
+			{"nome==João;name==", 1, `o campo "nome" não pode ser usado no filtro`},
+			{"name==João;id==", 16, "esperado valor, encontrado o fim da expressão"},
+		}
+
+		for _, tt := range tests {
+			_, err := rsql.Parse(tt.input, schema)
+
+			var parseErr *rsql.Error
+			if assert.ErrorAs(t, err, &parseErr, tt.input) {
+				assert.Equal(t, tt.position, parseErr.Position, tt.input)
+				assert.Equal(t, tt.message, parseErr.Message, tt.input)
+			}
+		}
+	})
+
+	t.Run("Unknown Field Lists Allowed Fields", func(t *testing.T) {
+		_, err := rsql.Parse("password==123", schema)
+
+		var parseErr *rsql.Error
+		assert.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, []string{"active", "created_at", "email", "id", "name"}, parseErr.Allowed)
+		assert.Equal(t, `expressão de filtro inválida na posição 1: o campo "password" não pode ser usado no filtro`, err.Error())
+	})
+}
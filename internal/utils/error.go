@@ -2,14 +2,11 @@
 
 package utils
 
-// ErrorResponse modelo para respostas de erro
-// @Description Modelo padrão para respostas de erro da API
-type ErrorResponse struct {
-	Error string `json:"error" example:"Mensagem de erro"`
-}
+import "encoding/xml"
 
 // CountResponse modelo para resposta de contagem
 // @Description Modelo para resposta de contagem de registros
 type CountResponse struct {
-	Count int64 `json:"count" example:"42"`
+	XMLName xml.Name `json:"-" xml:"customer_count" yaml:"-" swaggerignore:"true"`
+	Count   int64    `json:"count" xml:"count" yaml:"count" example:"42"`
 }
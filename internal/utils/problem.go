@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ProblemContentType é o tipo de mídia das respostas de erro (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem modelo para respostas de erro no formato RFC 7807
+// @Description Resposta de erro no formato application/problem+json (RFC 7807)
+type Problem struct {
+	Type     string       `json:"type" xml:"type" yaml:"type" example:"about:blank"`
+	Title    string       `json:"title" xml:"title" yaml:"title" example:"Bad Request"`
+	Status   int          `json:"status" xml:"status" yaml:"status" example:"400"`
+	Detail   string       `json:"detail,omitempty" xml:"detail,omitempty" yaml:"detail,omitempty" example:"dados do cliente inválidos"`
+	Instance string       `json:"instance,omitempty" xml:"instance,omitempty" yaml:"instance,omitempty" example:"/api/customers"`
+	Errors   []FieldError `json:"errors,omitempty" xml:"errors>error,omitempty" yaml:"errors,omitempty"`
+	// AllowedValues lista os valores aceitos quando o problema é um parâmetro fora da lista permitida
+	AllowedValues []string `json:"allowed_values,omitempty" xml:"allowed_values>value,omitempty" yaml:"allowed_values,omitempty"`
+	// Position indica o caractere, a partir de 1, em que foi encontrado o erro de uma expressão inválida
+	Position int `json:"position,omitempty" xml:"position,omitempty" yaml:"position,omitempty" example:"14"`
+}
+
+// FieldError descreve um campo que não atende a uma regra de validação
+// @Description Campo que não atende a uma regra de validação
+type FieldError struct {
+	Field   string `json:"field" xml:"field" yaml:"field" example:"email"`
+	Rule    string `json:"rule" xml:"rule" yaml:"rule" example:"email"`
+	Message string `json:"message" xml:"message" yaml:"message" example:"deve ser um email válido"`
+}
+
+// NewProblem cria um problema com o status e o detalhe informados
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// RespondProblem encerra a requisição respondendo o problema como application/problem+json.
+// Quando não informado, instance recebe o caminho da requisição.
+func RespondProblem(c *gin.Context, problem *Problem) {
+	if problem.Instance == "" {
+		problem.Instance = c.Request.URL.Path
+	}
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// FieldErrors converte os erros do validator em erros por campo
+func FieldErrors(errs validator.ValidationErrors) []FieldError {
+	fieldErrors := make([]FieldError, len(errs))
+	for i, err := range errs {
+		fieldErrors[i] = FieldError{Field: err.Field(), Rule: err.Tag(), Message: validationMessage(err)}
+	}
+	return fieldErrors
+}
+
+// validationMessage descreve a regra de validação violada
+func validationMessage(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return "campo obrigatório"
+	case "email":
+		return "deve ser um email válido"
+	case "min":
+		return fmt.Sprintf("deve ter no mínimo %s caracteres", err.Param())
+	case "max":
+		return fmt.Sprintf("deve ter no máximo %s caracteres", err.Param())
+	default:
+		return fmt.Sprintf("não atende à regra %s", err.Tag())
+	}
+}
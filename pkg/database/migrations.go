@@ -0,0 +1,162 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migration representa uma alteração de esquema que não pode ser expressa pelo AutoMigrate.
+// Cada migração é aplicada uma única vez, em uma transação, na ordem em que aparece em migrations.
+// Check, quando informado, é executado na mesma transação antes das instruções e impede a migração com um erro
+// que descreve os dados a corrigir.
+type migration struct {
+	Version    string
+	Check      func(tx *gorm.DB) error
+	Statements []string
+}
+
+// schemaMigration registra as migrações já aplicadas no banco de dados
+type schemaMigration struct {
+	Version   string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+// migrations contém as migrações do esquema, em ordem de aplicação
+var migrations = []migration{
+	{
+		Version: "0001_customers_email_unique",
+		Check:   checkDuplicateEmails,
+		Statements: []string{
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email_lower ON customers (LOWER(email))`,
+		},
+	},
+	{
+		// Clientes excluídos logicamente não reservam o email
+		Version: "0002_customers_email_unique_not_deleted",
+		Statements: []string{
+			`DROP INDEX IF EXISTS idx_customers_email_lower`,
+			`CREATE UNIQUE INDEX idx_customers_email_lower ON customers (LOWER(email)) WHERE deleted_at IS NULL`,
+		},
+	},
+	{
+		// Busca por nome sem diferenciar acentos, ordenada pela semelhança dos trigramas. A função unaccent não é
+		// IMMUTABLE e não pode ser usada em índices; immutable_unaccent fixa o dicionário para permitir o índice GIN.
+		Version: "0003_customers_name_trigram_search",
+		Statements: []string{
+			`CREATE EXTENSION IF NOT EXISTS unaccent`,
+			`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+			`CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+				LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+				AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$`,
+			`CREATE INDEX IF NOT EXISTS idx_customers_name_trgm ON customers USING GIN (immutable_unaccent(name) gin_trgm_ops)`,
+		},
+	},
+	{
+		// Busca por partes do email, dos dígitos do telefone e do endereço. As expressões dos índices devem ser
+		// idênticas às usadas nas consultas para que o planejador as utilize.
+		Version: "0004_customers_multi_field_search",
+		Statements: []string{
+			`CREATE INDEX IF NOT EXISTS idx_customers_email_trgm ON customers USING GIN (email gin_trgm_ops)`,
+			`CREATE INDEX IF NOT EXISTS idx_customers_phone_digits_trgm ON customers USING GIN (regexp_replace(phone, '\D', '', 'g') gin_trgm_ops)`,
+			`CREATE INDEX IF NOT EXISTS idx_customers_address_trgm ON customers USING GIN (immutable_unaccent(address) gin_trgm_ops)`,
+		},
+	},
+	{
+		// Busca textual em português sobre o nome e o endereço, com o nome de maior peso na pontuação. A coluna é
+		// gerada pelo banco de dados e não faz parte do modelo, para que o GORM nunca tente gravá-la.
+		Version: "0005_customers_full_text_search",
+		Statements: []string{
+			`ALTER TABLE customers ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+				setweight(to_tsvector('portuguese', coalesce(name, '')), 'A') ||
+				setweight(to_tsvector('portuguese', coalesce(address, '')), 'B')
+			) STORED`,
+			`CREATE INDEX IF NOT EXISTS idx_customers_search_vector ON customers USING GIN (search_vector)`,
+		},
+	},
+}
+
+// runMigrations aplica as migrações pendentes
+func runMigrations(db *gorm.DB) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		var applied int64
+		if err := db.Model(&schemaMigration{}).Where("version = ?", m.Version).Count(&applied).Error; err != nil {
+			return err
+		}
+		if applied > 0 {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if m.Check != nil {
+				if err := m.Check(tx); err != nil {
+					return err
+				}
+			}
+			for _, statement := range m.Statements {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migração %s: %w", m.Version, err)
+		}
+		log.Printf("Migração %s aplicada", m.Version)
+	}
+
+	return nil
+}
+
+// maxReportedDuplicates é a quantidade máxima de emails duplicados listados no erro de checkDuplicateEmails
+const maxReportedDuplicates = 20
+
+// duplicateEmail é um email, sem diferenciar maiúsculas e minúsculas, usado por mais de um cliente
+type duplicateEmail struct {
+	Email string
+	IDs   string
+}
+
+// checkDuplicateEmails impede a criação do índice único sobre LOWER(email) quando há clientes, inclusive os
+// excluídos logicamente, com o mesmo email. Sem a verificação, o CREATE UNIQUE INDEX falha com um erro do
+// PostgreSQL que não indica quais clientes estão em conflito.
+func checkDuplicateEmails(tx *gorm.DB) error {
+	var duplicates []duplicateEmail
+	err := tx.Raw(`SELECT LOWER(email) AS email, string_agg(id::text, ', ' ORDER BY id) AS ids
+		FROM customers
+		GROUP BY LOWER(email)
+		HAVING COUNT(*) > 1
+		ORDER BY LOWER(email)
+		LIMIT ?`, maxReportedDuplicates+1).Scan(&duplicates).Error
+	if err != nil {
+		return err
+	}
+	return duplicateEmailsError(duplicates)
+}
+
+// duplicateEmailsError descreve os emails duplicados e os ids dos clientes que os utilizam, retornando nil quando
+// não há duplicados
+func duplicateEmailsError(duplicates []duplicateEmail) error {
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	conflicts := make([]string, 0, maxReportedDuplicates)
+	for i, duplicate := range duplicates {
+		if i == maxReportedDuplicates {
+			conflicts = append(conflicts, "...")
+			break
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s (ids %s)", duplicate.Email, duplicate.IDs))
+	}
+	return fmt.Errorf("existem clientes com o mesmo email, sem diferenciar maiúsculas e minúsculas, que impedem a criação do índice único; "+
+		"altere o email ou remova definitivamente os clientes duplicados antes de iniciar a aplicação: %s", strings.Join(conflicts, "; "))
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateEmailsError(t *testing.T) {
+	// Subteste para a base sem emails duplicados: a migração pode ser aplicada.
+	t.Run("No Duplicates", func(t *testing.T) {
+		assert.NoError(t, duplicateEmailsError(nil))
+	})
+
+	// Subteste para a base com emails duplicados: o erro lista cada email e os ids dos clientes em conflito.
+	t.Run("Duplicates", func(t *testing.T) {
+		err := duplicateEmailsError([]duplicateEmail{
+			{Email: "ana@example.com", IDs: "1, 4"},
+			{Email: "joao@example.com", IDs: "2, 3, 9"},
+		})
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "ana@example.com (ids 1, 4); joao@example.com (ids 2, 3, 9)")
+			assert.NotContains(t, err.Error(), "...")
+		}
+	})
+
+	// Subteste para a base com mais emails duplicados que o limite: somente os primeiros são listados.
+	t.Run("Too Many Duplicates", func(t *testing.T) {
+		duplicates := make([]duplicateEmail, maxReportedDuplicates+1)
+		for i := range duplicates {
+			duplicates[i] = duplicateEmail{Email: fmt.Sprintf("cliente%02d@example.com", i), IDs: "1, 2"}
+		}
+
+		err := duplicateEmailsError(duplicates)
+
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), fmt.Sprintf("cliente%02d@example.com", maxReportedDuplicates-1))
+			assert.NotContains(t, err.Error(), fmt.Sprintf("cliente%02d@example.com", maxReportedDuplicates))
+			assert.Contains(t, err.Error(), "; ...")
+		}
+	})
+}
@@ -37,7 +37,12 @@ func NewPostgresConnection(cfg *config.Config) (*gorm.DB, error) {
 	}
 
 	// Auto-migra as tabelas
-	if err := db.AutoMigrate(&model.Customer{}); err != nil {
+	if err := db.AutoMigrate(&model.Customer{}, &model.IdempotencyRecord{}, &model.Segment{}); err != nil {
+		return nil, err
+	}
+
+	// Aplica as migrações que não podem ser expressas pelo AutoMigrate
+	if err := runMigrations(db); err != nil {
 		return nil, err
 	}
 